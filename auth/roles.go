@@ -0,0 +1,71 @@
+package auth
+
+// User roles stored in users.role
+const (
+	RoleLearner    = "learner"
+	RoleInstructor = "instructor"
+	RoleAdmin      = "admin"
+)
+
+// Permissions checked by middleware.RequirePermission
+const (
+	PermContentRead       = "content:read"       // Browse chapters, videos and learner quiz views
+	PermAnswerKeyRead     = "content:answer_key" // See correct answers before answering
+	PermContentManage     = "content:manage"     // Create, update and delete course content
+	PermLockedContent     = "content:locked"     // Open chapters whose prerequisites are not met
+	PermProgressOwn       = "progress:own"       // Track own progress and submit quiz answers
+	PermLearnerDataRead   = "learners:read"      // Read other users' progress and quiz history
+	PermLearnerDataManage = "learners:manage"    // Modify or reset other users' data
+	PermRolesManage       = "roles:manage"       // Grant and revoke roles
+)
+
+var rolePermissions = map[string][]string{
+	RoleLearner: {
+		PermContentRead,
+		PermProgressOwn,
+	},
+	RoleInstructor: {
+		PermContentRead,
+		PermProgressOwn,
+		PermAnswerKeyRead,
+		PermContentManage,
+		PermLockedContent,
+		PermLearnerDataRead,
+	},
+	RoleAdmin: {
+		PermContentRead,
+		PermProgressOwn,
+		PermAnswerKeyRead,
+		PermContentManage,
+		PermLockedContent,
+		PermLearnerDataRead,
+		PermLearnerDataManage,
+		PermRolesManage,
+	},
+}
+
+// IsValidRole - Check whether a role name is known
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// HasPermission - Check whether a role grants a permission
+func HasPermission(role, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Roles - All known roles, lowest privilege first
+func Roles() []string {
+	return []string{RoleLearner, RoleInstructor, RoleAdmin}
+}
+
+// Permissions - Permissions granted to a role
+func Permissions(role string) []string {
+	return append([]string(nil), rolePermissions[role]...)
+}
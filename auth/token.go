@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"learning-app-backend/config"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	secret          []byte
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims carried by an access token. The token ID (jti) is the auth session ID
+// so that revoking the session also invalidates every access token issued for it.
+type Claims struct {
+	jwt.RegisteredClaims
+}
+
+// UserID returns the user the token was issued to
+func (c *Claims) UserID() string {
+	return c.Subject
+}
+
+// SessionID returns the auth session the token belongs to
+func (c *Claims) SessionID() (uint, error) {
+	id, err := strconv.ParseUint(c.ID, 10, 64)
+	return uint(id), err
+}
+
+// InitTokens - Configure token signing from application config
+func InitTokens(cfg *config.Config) {
+	secret = []byte(cfg.JWTSecret)
+	accessTokenTTL = cfg.AccessTokenTTL
+	refreshTokenTTL = cfg.RefreshTokenTTL
+}
+
+// AccessTokenTTL returns the configured access token lifetime
+func AccessTokenTTL() time.Duration {
+	return accessTokenTTL
+}
+
+// RefreshTokenTTL returns the configured refresh token lifetime
+func RefreshTokenTTL() time.Duration {
+	return refreshTokenTTL
+}
+
+// IssueAccessToken - Sign a short-lived access token for a user session
+func IssueAccessToken(userID string, sessionID uint) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(accessTokenTTL)
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			ID:        strconv.FormatUint(uint64(sessionID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ParseAccessToken - Verify signature and expiry of an access token
+func ParseAccessToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil || !token.Valid || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	if _, err := claims.SessionID(); err != nil {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// NewRefreshToken - Generate an opaque refresh token and the hash to store for it
+func NewRefreshToken() (token string, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken - Refresh tokens are stored hashed so a database leak cannot replay them
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
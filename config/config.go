@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"time"
 )
 
 type Config struct {
@@ -9,15 +10,25 @@ type Config struct {
 	Port         string
 	Environment  string
 	DatabaseType string // "postgres" or "sqlite"
+	AutoMigrate  bool   // Apply pending schema migrations at startup
+
+	// Auth settings
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
 }
 
 func LoadConfig() *Config {
 	// Default to development settings
 	config := &Config{
-		DatabaseURL:  "learning_app.db",
-		Port:         "8080",
-		Environment:  "development",
-		DatabaseType: "sqlite",
+		DatabaseURL:     "learning_app.db",
+		Port:            "8080",
+		Environment:     "development",
+		DatabaseType:    "sqlite",
+		AutoMigrate:     true,
+		JWTSecret:       "dev-secret-change-me",
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 30 * 24 * time.Hour,
 	}
 
 	// Check for production database URL
@@ -27,10 +38,35 @@ func LoadConfig() *Config {
 		config.Environment = "production"
 	}
 
+	// Allow a custom SQLite file for development (":memory:" for a throwaway database)
+	if sqlitePath := os.Getenv("SQLITE_PATH"); sqlitePath != "" && config.DatabaseType == "sqlite" {
+		config.DatabaseURL = sqlitePath
+	}
+
 	// Check for custom port
 	if port := os.Getenv("PORT"); port != "" {
 		config.Port = port
 	}
 
+	// Allow disabling automatic migrations (run `learnhub-server migrate up` instead)
+	if autoMigrate := os.Getenv("AUTO_MIGRATE"); autoMigrate == "false" || autoMigrate == "0" {
+		config.AutoMigrate = false
+	}
+
+	// Check for token signing secret (required in production)
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		config.JWTSecret = secret
+	} else if config.Environment == "production" {
+		config.JWTSecret = ""
+	}
+
+	// Check for custom token lifetimes (Go duration format, e.g. "15m", "720h")
+	if ttl, err := time.ParseDuration(os.Getenv("ACCESS_TOKEN_TTL")); err == nil && ttl > 0 {
+		config.AccessTokenTTL = ttl
+	}
+	if ttl, err := time.ParseDuration(os.Getenv("REFRESH_TOKEN_TTL")); err == nil && ttl > 0 {
+		config.RefreshTokenTTL = ttl
+	}
+
 	return config
 }
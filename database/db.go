@@ -12,6 +12,9 @@ import (
 
 var DB *gorm.DB
 
+// SQL is the raw SQL connection used by the handlers; queries are rewritten for the active dialect
+var SQL *Conn
+
 func InitDatabase(cfg *config.Config) {
 	var err error
 	var dialector gorm.Dialector
@@ -33,5 +36,17 @@ func InitDatabase(cfg *config.Config) {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	sqlDB, err := DB.DB()
+	if err != nil {
+		log.Fatal("Failed to get database handle:", err)
+	}
+
+	// SQLite allows a single writer, and every connection to ":memory:" is a separate database
+	if cfg.DatabaseType != "postgres" {
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	SQL = &Conn{DB: sqlDB, Dialect: DialectFor(cfg.DatabaseType)}
+
 	log.Printf("Database connection established (%s)", cfg.DatabaseType)
 }
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+)
+
+// Dialect rewrites the Postgres-flavoured SQL used throughout the handlers
+// ($1 placeholders, NOW()) into something the active database understands.
+type Dialect interface {
+	Name() string
+	Rebind(query string) string
+	ForUpdate(table string) string
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return "postgres" }
+
+// Rebind - Handlers are written for Postgres, nothing to rewrite
+func (postgresDialect) Rebind(query string) string { return query }
+
+// ForUpdate - Lock the rows a transaction reads from table (a name or alias in the query)
+func (postgresDialect) ForUpdate(table string) string { return " FOR UPDATE OF " + table }
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return "sqlite" }
+
+var (
+	postgresPlaceholder = regexp.MustCompile(`\$(\d+)`)
+	nowCall             = regexp.MustCompile(`(?i)\bNOW\(\)`)
+)
+
+// sqliteNow produces the same text layout go-sqlite3 uses when binding a UTC
+// time.Time, so timestamps written by SQL and by Go compare correctly as text.
+const sqliteNow = `strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')`
+
+// Rebind - Use numbered ?NNN parameters (so $2 before $1 still binds correctly)
+// and replace NOW() with a UTC timestamp expression
+func (sqliteDialect) Rebind(query string) string {
+	query = postgresPlaceholder.ReplaceAllString(query, "?$1")
+	return nowCall.ReplaceAllString(query, sqliteNow)
+}
+
+// ForUpdate - Nothing to add: SQLite runs on a single connection, so an open transaction
+// already keeps every other writer out
+func (sqliteDialect) ForUpdate(table string) string { return "" }
+
+// DialectFor - Get the dialect for a config.DatabaseType value
+func DialectFor(databaseType string) Dialect {
+	if strings.EqualFold(databaseType, "postgres") {
+		return postgresDialect{}
+	}
+	return sqliteDialect{}
+}
+
+// Conn wraps *sql.DB and rewrites every query for the active dialect
+type Conn struct {
+	*sql.DB
+	Dialect Dialect
+}
+
+func (c *Conn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.DB.Query(c.Dialect.Rebind(query), args...)
+}
+
+func (c *Conn) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.DB.QueryRow(c.Dialect.Rebind(query), args...)
+}
+
+func (c *Conn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.DB.Exec(c.Dialect.Rebind(query), args...)
+}
+
+func (c *Conn) Begin() (*Tx, error) {
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, Dialect: c.Dialect}, nil
+}
+
+// Tx wraps *sql.Tx and rewrites every query for the active dialect
+type Tx struct {
+	*sql.Tx
+	Dialect Dialect
+}
+
+func (t *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.Tx.Query(t.Dialect.Rebind(query), args...)
+}
+
+func (t *Tx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return t.Tx.QueryRow(t.Dialect.Rebind(query), args...)
+}
+
+func (t *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.Tx.Exec(t.Dialect.Rebind(query), args...)
+}
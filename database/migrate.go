@@ -0,0 +1,236 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migrations live in migrations/<dialect>/NNNN_name.up.sql and NNNN_name.down.sql
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+type Migration struct {
+	Version int
+	Name    string
+	UpSQL   string
+	DownSQL string
+}
+
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// LoadMigrations - Read the embedded migrations for a dialect ("postgres" or "sqlite"), oldest first
+func LoadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %q: %w", dialect, err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		prefix, label, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.UpSQL = string(content)
+		} else {
+			m.DownSQL = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for _, m := range byVersion {
+		if m.UpSQL == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// MigrateUp - Apply all pending migrations, returns how many were applied
+func MigrateUp(dialect string) (int, error) {
+	sqlDB := SQL
+
+	migrations, err := LoadMigrations(dialect)
+	if err != nil {
+		return 0, err
+	}
+
+	applied, err := appliedMigrations(sqlDB)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		log.Printf("Applying migration %04d_%s...", m.Version, m.Name)
+		err := inTransaction(sqlDB, func(tx *Tx) error {
+			// Migration files are already written for their dialect
+			if _, err := tx.Tx.Exec(m.UpSQL); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+				m.Version, m.Name, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// MigrateDown - Roll back the most recent `steps` applied migrations
+func MigrateDown(dialect string, steps int) (int, error) {
+	sqlDB := SQL
+
+	migrations, err := LoadMigrations(dialect)
+	if err != nil {
+		return 0, err
+	}
+
+	applied, err := appliedMigrations(sqlDB)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if m.DownSQL == "" {
+			return count, fmt.Errorf("migration %04d_%s has no down file", m.Version, m.Name)
+		}
+
+		log.Printf("Rolling back migration %04d_%s...", m.Version, m.Name)
+		err := inTransaction(sqlDB, func(tx *Tx) error {
+			if _, err := tx.Tx.Exec(m.DownSQL); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("rollback of %04d_%s failed: %w", m.Version, m.Name, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// GetMigrationStatus - List every known migration and whether it has been applied
+func GetMigrationStatus(dialect string) ([]MigrationStatus, error) {
+	sqlDB := SQL
+
+	migrations, err := LoadMigrations(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedMigrations(sqlDB)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if appliedAt, ok := applied[m.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// appliedMigrations - Create the bookkeeping table if needed and read applied versions
+func appliedMigrations(sqlDB *Conn) (map[int]time.Time, error) {
+	createQuery := `CREATE TABLE IF NOT EXISTS schema_migrations (
+						version     INTEGER PRIMARY KEY,
+						name        VARCHAR(255) NOT NULL,
+						applied_at  TIMESTAMP NOT NULL
+					)`
+	if _, err := sqlDB.Exec(createQuery); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	rows, err := sqlDB.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+func inTransaction(sqlDB *Conn, fn func(tx *Tx) error) error {
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,80 @@
+-- Baseline schema. Uses IF NOT EXISTS so databases created before migrations
+-- existed are adopted as-is.
+
+CREATE TABLE IF NOT EXISTS users (
+    id          SERIAL PRIMARY KEY,
+    user_id     VARCHAR(255) NOT NULL UNIQUE,
+    username    VARCHAR(255) NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at  TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS chapters (
+    id           SERIAL PRIMARY KEY,
+    title        VARCHAR(255) NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    order_index  INTEGER NOT NULL DEFAULT 0,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at   TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS videos (
+    id                SERIAL PRIMARY KEY,
+    chapter_id        INTEGER NOT NULL REFERENCES chapters(id),
+    title             VARCHAR(255) NOT NULL,
+    video_url         TEXT NOT NULL,
+    duration_seconds  INTEGER NOT NULL DEFAULT 0,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at        TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS quiz_questions (
+    id              SERIAL PRIMARY KEY,
+    chapter_id      INTEGER NOT NULL REFERENCES chapters(id),
+    question_text   TEXT NOT NULL,
+    option_a        TEXT NOT NULL DEFAULT '',
+    option_b        TEXT NOT NULL DEFAULT '',
+    option_c        TEXT NOT NULL DEFAULT '',
+    option_d        TEXT NOT NULL DEFAULT '',
+    correct_answer  VARCHAR(10) NOT NULL,
+    order_index     INTEGER NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at      TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS progresses (
+    id                   SERIAL PRIMARY KEY,
+    user_id              VARCHAR(255) NOT NULL,
+    chapter_id           INTEGER NOT NULL REFERENCES chapters(id),
+    content_type         VARCHAR(20) NOT NULL,
+    video_timestamp      INTEGER,
+    quiz_question_index  INTEGER,
+    is_completed         BOOLEAN NOT NULL DEFAULT FALSE,
+    last_updated         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at           TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS quiz_answers (
+    id                SERIAL PRIMARY KEY,
+    user_id           VARCHAR(255) NOT NULL,
+    chapter_id        INTEGER NOT NULL REFERENCES chapters(id),
+    quiz_question_id  INTEGER NOT NULL REFERENCES quiz_questions(id),
+    user_answer       VARCHAR(10) NOT NULL,
+    is_correct        BOOLEAN NOT NULL DEFAULT FALSE,
+    answered_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at        TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_videos_chapter_id ON videos (chapter_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_chapter_id ON quiz_questions (chapter_id);
+CREATE INDEX IF NOT EXISTS idx_progresses_user_chapter ON progresses (user_id, chapter_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_answers_user_chapter ON quiz_answers (user_id, chapter_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_answers_user_question ON quiz_answers (user_id, quiz_question_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS auth_sessions;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+ALTER TABLE users DROP COLUMN IF EXISTS password_hash;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_chapters_course_id;
+ALTER TABLE chapters DROP COLUMN IF EXISTS course_id;
+DROP TABLE IF EXISTS courses;
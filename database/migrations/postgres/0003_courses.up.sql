@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS courses (
+    id           SERIAL PRIMARY KEY,
+    title        VARCHAR(255) NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    order_index  INTEGER NOT NULL DEFAULT 0,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at   TIMESTAMPTZ
+);
+
+-- Chapters that existed before courses move into a default course
+INSERT INTO courses (title, description, order_index)
+SELECT 'LearnHub', '', 1 WHERE EXISTS (SELECT 1 FROM chapters);
+
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS course_id INTEGER REFERENCES courses(id);
+UPDATE chapters SET course_id = (SELECT MIN(id) FROM courses) WHERE course_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_chapters_course_id ON chapters (course_id);
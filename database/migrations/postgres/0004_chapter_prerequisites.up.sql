@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS chapter_prerequisites (
+    id                   SERIAL PRIMARY KEY,
+    chapter_id           INTEGER NOT NULL REFERENCES chapters(id),
+    required_chapter_id  INTEGER NOT NULL REFERENCES chapters(id),
+    requirement_type     VARCHAR(20) NOT NULL DEFAULT 'quiz_passed',
+    min_score_percent    INTEGER NOT NULL DEFAULT 70,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at           TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_chapter_prerequisites_chapter_id ON chapter_prerequisites (chapter_id);
@@ -0,0 +1,20 @@
+ALTER TABLE quiz_answers ALTER COLUMN user_answer TYPE VARCHAR(10) USING LEFT(user_answer, 10);
+
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS option_a TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS option_b TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS option_c TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS option_d TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS correct_answer VARCHAR(10) NOT NULL DEFAULT '';
+
+-- Only the first four options and first correct key survive; other question types lose their answer key
+UPDATE quiz_questions SET
+    option_a = COALESCE(options::json->0->>'text', ''),
+    option_b = COALESCE(options::json->1->>'text', ''),
+    option_c = COALESCE(options::json->2->>'text', ''),
+    option_d = COALESCE(options::json->3->>'text', ''),
+    correct_answer = CASE WHEN question_type = 'multiple_choice'
+        THEN COALESCE(answer_key::json->'option_keys'->>0, '') ELSE '' END;
+
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS answer_key;
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS options;
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS question_type;
@@ -0,0 +1,30 @@
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS question_type VARCHAR(20) NOT NULL DEFAULT 'multiple_choice';
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS options TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS answer_key TEXT NOT NULL DEFAULT '{}';
+
+-- Existing A-D questions become multiple choice with a variable-length option list
+UPDATE quiz_questions SET
+    options = (CASE
+        WHEN option_c = '' THEN json_build_array(
+            json_build_object('key', 'A', 'text', option_a),
+            json_build_object('key', 'B', 'text', option_b))
+        WHEN option_d = '' THEN json_build_array(
+            json_build_object('key', 'A', 'text', option_a),
+            json_build_object('key', 'B', 'text', option_b),
+            json_build_object('key', 'C', 'text', option_c))
+        ELSE json_build_array(
+            json_build_object('key', 'A', 'text', option_a),
+            json_build_object('key', 'B', 'text', option_b),
+            json_build_object('key', 'C', 'text', option_c),
+            json_build_object('key', 'D', 'text', option_d))
+    END)::text,
+    answer_key = json_build_object('option_keys', json_build_array(correct_answer))::text;
+
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS option_a;
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS option_b;
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS option_c;
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS option_d;
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS correct_answer;
+
+-- Short-text, numeric and list answers no longer fit in a single option letter
+ALTER TABLE quiz_answers ALTER COLUMN user_answer TYPE TEXT;
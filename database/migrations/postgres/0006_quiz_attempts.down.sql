@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_quiz_answers_attempt_id;
+ALTER TABLE quiz_answers DROP COLUMN IF EXISTS attempt_id;
+DROP TABLE IF EXISTS quiz_attempts;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id               SERIAL PRIMARY KEY,
+    user_id          VARCHAR(255) NOT NULL,
+    chapter_id       INTEGER NOT NULL REFERENCES chapters(id),
+    status           VARCHAR(20) NOT NULL DEFAULT 'in_progress',
+    total_questions  INTEGER NOT NULL DEFAULT 0,
+    correct_answers  INTEGER NOT NULL DEFAULT 0,
+    score_percent    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    started_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at      TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at       TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_user_chapter ON quiz_attempts (user_id, chapter_id);
+
+-- Answers given before attempts existed keep a NULL attempt
+ALTER TABLE quiz_answers ADD COLUMN IF NOT EXISTS attempt_id INTEGER REFERENCES quiz_attempts(id);
+CREATE INDEX IF NOT EXISTS idx_quiz_answers_attempt_id ON quiz_answers (attempt_id);
@@ -0,0 +1,4 @@
+ALTER TABLE quiz_answers DROP COLUMN IF EXISTS is_late;
+ALTER TABLE quiz_attempts DROP COLUMN IF EXISTS deadline_at;
+ALTER TABLE chapters DROP COLUMN IF EXISTS quiz_late_policy;
+ALTER TABLE chapters DROP COLUMN IF EXISTS quiz_time_limit_seconds;
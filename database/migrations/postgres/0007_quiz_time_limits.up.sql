@@ -0,0 +1,6 @@
+-- 0 means the chapter's quiz is untimed
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS quiz_time_limit_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS quiz_late_policy VARCHAR(10) NOT NULL DEFAULT 'reject';
+
+ALTER TABLE quiz_attempts ADD COLUMN IF NOT EXISTS deadline_at TIMESTAMPTZ;
+ALTER TABLE quiz_answers ADD COLUMN IF NOT EXISTS is_late BOOLEAN NOT NULL DEFAULT FALSE;
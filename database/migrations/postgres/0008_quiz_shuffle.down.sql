@@ -0,0 +1,2 @@
+ALTER TABLE chapters DROP COLUMN IF EXISTS shuffle_options;
+ALTER TABLE chapters DROP COLUMN IF EXISTS shuffle_questions;
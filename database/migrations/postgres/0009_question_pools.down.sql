@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS quiz_attempt_questions;
+DROP TABLE IF EXISTS quiz_draw_rules;
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS difficulty;
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS topic;
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS pool_id;
+DROP TABLE IF EXISTS question_pools;
@@ -0,0 +1 @@
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS explanation;
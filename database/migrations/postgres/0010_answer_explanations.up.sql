@@ -0,0 +1 @@
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS explanation TEXT NOT NULL DEFAULT '';
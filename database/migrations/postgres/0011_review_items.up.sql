@@ -0,0 +1,42 @@
+-- One spaced-repetition (SM-2) schedule per user and answered question
+CREATE TABLE IF NOT EXISTS review_items (
+    id                SERIAL PRIMARY KEY,
+    user_id           VARCHAR(255) NOT NULL,
+    chapter_id        INTEGER NOT NULL REFERENCES chapters(id),
+    quiz_question_id  INTEGER NOT NULL REFERENCES quiz_questions(id),
+    ease_factor       DOUBLE PRECISION NOT NULL DEFAULT 2.5,
+    interval_days     INTEGER NOT NULL DEFAULT 0,
+    repetitions       INTEGER NOT NULL DEFAULT 0,
+    lapses            INTEGER NOT NULL DEFAULT 0,
+    due_at            TIMESTAMPTZ NOT NULL,
+    last_reviewed_at  TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at        TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_review_items_user_question ON review_items (user_id, quiz_question_id)
+    WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_review_items_user_due ON review_items (user_id, due_at);
+
+-- Answers given in review sessions; kept apart from quiz_answers so chapter scores are unaffected
+CREATE TABLE IF NOT EXISTS review_answers (
+    id                SERIAL PRIMARY KEY,
+    review_item_id    INTEGER NOT NULL REFERENCES review_items(id),
+    user_id           VARCHAR(255) NOT NULL,
+    quiz_question_id  INTEGER NOT NULL REFERENCES quiz_questions(id),
+    user_answer       TEXT NOT NULL,
+    is_correct        BOOLEAN NOT NULL,
+    quality           INTEGER NOT NULL,
+    reviewed_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_answers_review_item_id ON review_answers (review_item_id);
+
+-- Questions answered before reviews existed are due right away
+INSERT INTO review_items (user_id, chapter_id, quiz_question_id, due_at)
+SELECT user_id, chapter_id, quiz_question_id, NOW()
+FROM quiz_answers
+WHERE deleted_at IS NULL
+GROUP BY user_id, chapter_id, quiz_question_id;
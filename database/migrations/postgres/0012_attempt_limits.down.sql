@@ -0,0 +1,3 @@
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS max_attempts;
+ALTER TABLE chapters DROP COLUMN IF EXISTS answer_cooldown_seconds;
+ALTER TABLE chapters DROP COLUMN IF EXISTS max_answer_attempts;
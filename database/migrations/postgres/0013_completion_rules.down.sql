@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS chapter_completions;
+ALTER TABLE chapters DROP COLUMN IF EXISTS pass_score_percent;
+ALTER TABLE chapters DROP COLUMN IF EXISTS min_video_percent;
@@ -0,0 +1,18 @@
+-- Server-side completion rules: how much of the video must be watched and the quiz score to pass
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS min_video_percent INTEGER NOT NULL DEFAULT 90;
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS pass_score_percent INTEGER NOT NULL DEFAULT 70;
+
+-- Chapters a learner has passed; recorded once and kept even if later answers score lower
+CREATE TABLE IF NOT EXISTS chapter_completions (
+    id                  SERIAL PRIMARY KEY,
+    user_id             VARCHAR(255) NOT NULL,
+    chapter_id          INTEGER NOT NULL REFERENCES chapters(id),
+    video_percent       DOUBLE PRECISION,
+    quiz_score_percent  DOUBLE PRECISION,
+    completed_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at          TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chapter_completions_user_chapter ON chapter_completions (user_id, chapter_id)
+    WHERE deleted_at IS NULL;
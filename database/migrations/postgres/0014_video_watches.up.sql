@@ -0,0 +1,16 @@
+-- Parts of a video a learner has actually played, merged from heartbeat intervals
+CREATE TABLE IF NOT EXISTS video_watches (
+    id               SERIAL PRIMARY KEY,
+    user_id          VARCHAR(255) NOT NULL,
+    chapter_id       INTEGER NOT NULL REFERENCES chapters(id),
+    video_id         INTEGER NOT NULL REFERENCES videos(id),
+    watched_ranges   TEXT NOT NULL DEFAULT '[]',
+    watched_seconds  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    last_position    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at       TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_video_watches_user_video ON video_watches (user_id, video_id)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,26 @@
+-- Append-only log of every progress write; progresses holds the current state derived from it
+CREATE TABLE IF NOT EXISTS progress_events (
+    id                   SERIAL PRIMARY KEY,
+    user_id              VARCHAR(255) NOT NULL,
+    event_type           VARCHAR(20) NOT NULL,
+    source               VARCHAR(20) NOT NULL DEFAULT '',
+    chapter_id           INTEGER REFERENCES chapters(id),
+    content_type         VARCHAR(20) NOT NULL DEFAULT '',
+    video_timestamp      INTEGER,
+    quiz_question_index  INTEGER,
+    is_completed         BOOLEAN NOT NULL DEFAULT FALSE,
+    occurred_at          TIMESTAMPTZ NOT NULL,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_progress_events_user_occurred ON progress_events (user_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_progress_events_user_chapter ON progress_events (user_id, chapter_id, occurred_at);
+
+-- Start the log from the current rows so replaying it rebuilds them
+INSERT INTO progress_events (user_id, event_type, source, chapter_id, content_type, video_timestamp,
+    quiz_question_index, is_completed, occurred_at, created_at)
+SELECT user_id, 'saved', 'progress', chapter_id, content_type, video_timestamp,
+    quiz_question_index, is_completed, last_updated, updated_at
+FROM progresses
+WHERE deleted_at IS NULL
+ORDER BY updated_at, id;
@@ -0,0 +1 @@
+ALTER TABLE video_watches DROP COLUMN IF EXISTS last_heartbeat_at;
@@ -0,0 +1 @@
+ALTER TABLE learning_sessions DROP COLUMN IF EXISTS watched_seconds;
@@ -0,0 +1,2 @@
+-- Seconds of video played during each learning session, which minutes goals count
+ALTER TABLE learning_sessions ADD COLUMN IF NOT EXISTS watched_seconds DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- What each learner's streak calendar showed when it was last worked out, so later requests only
+-- look at the days since and the streak running into them
+CREATE TABLE IF NOT EXISTS streak_records (
+    user_id          VARCHAR(255) PRIMARY KEY,
+    longest_streak   INTEGER NOT NULL DEFAULT 0,
+    last_goal_date   VARCHAR(10) NOT NULL DEFAULT '',
+    counted_through  VARCHAR(10) NOT NULL,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1 @@
+ALTER TABLE chapters DROP COLUMN IF EXISTS max_quiz_attempts;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS quiz_answers;
+DROP TABLE IF EXISTS progresses;
+DROP TABLE IF EXISTS quiz_questions;
+DROP TABLE IF EXISTS videos;
+DROP TABLE IF EXISTS chapters;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,80 @@
+-- Baseline schema. Uses IF NOT EXISTS so databases created before migrations
+-- existed are adopted as-is.
+
+CREATE TABLE IF NOT EXISTS users (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     VARCHAR(255) NOT NULL UNIQUE,
+    username    VARCHAR(255) NOT NULL DEFAULT '',
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at  DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS chapters (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    title        VARCHAR(255) NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    order_index  INTEGER NOT NULL DEFAULT 0,
+    created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at   DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS videos (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    chapter_id        INTEGER NOT NULL REFERENCES chapters(id),
+    title             VARCHAR(255) NOT NULL,
+    video_url         TEXT NOT NULL,
+    duration_seconds  INTEGER NOT NULL DEFAULT 0,
+    created_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at        DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS quiz_questions (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    chapter_id      INTEGER NOT NULL REFERENCES chapters(id),
+    question_text   TEXT NOT NULL,
+    option_a        TEXT NOT NULL DEFAULT '',
+    option_b        TEXT NOT NULL DEFAULT '',
+    option_c        TEXT NOT NULL DEFAULT '',
+    option_d        TEXT NOT NULL DEFAULT '',
+    correct_answer  VARCHAR(10) NOT NULL,
+    order_index     INTEGER NOT NULL DEFAULT 0,
+    created_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at      DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS progresses (
+    id                   INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id              VARCHAR(255) NOT NULL,
+    chapter_id           INTEGER NOT NULL REFERENCES chapters(id),
+    content_type         VARCHAR(20) NOT NULL,
+    video_timestamp      INTEGER,
+    quiz_question_index  INTEGER,
+    is_completed         BOOLEAN NOT NULL DEFAULT 0,
+    last_updated         DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at           DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at           DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at           DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS quiz_answers (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id           VARCHAR(255) NOT NULL,
+    chapter_id        INTEGER NOT NULL REFERENCES chapters(id),
+    quiz_question_id  INTEGER NOT NULL REFERENCES quiz_questions(id),
+    user_answer       VARCHAR(10) NOT NULL,
+    is_correct        BOOLEAN NOT NULL DEFAULT 0,
+    answered_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at        DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_videos_chapter_id ON videos (chapter_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_chapter_id ON quiz_questions (chapter_id);
+CREATE INDEX IF NOT EXISTS idx_progresses_user_chapter ON progresses (user_id, chapter_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_answers_user_chapter ON quiz_answers (user_id, chapter_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_answers_user_question ON quiz_answers (user_id, quiz_question_id);
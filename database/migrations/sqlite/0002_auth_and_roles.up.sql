@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN password_hash VARCHAR(255);
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'learner';
+
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id                  INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id             VARCHAR(255) NOT NULL,
+    refresh_token_hash  VARCHAR(64) NOT NULL UNIQUE,
+    expires_at          DATETIME NOT NULL,
+    revoked_at          DATETIME,
+    created_at          DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at          DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user_id ON auth_sessions (user_id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS chapter_prerequisites (
+    id                   INTEGER PRIMARY KEY AUTOINCREMENT,
+    chapter_id           INTEGER NOT NULL REFERENCES chapters(id),
+    required_chapter_id  INTEGER NOT NULL REFERENCES chapters(id),
+    requirement_type     VARCHAR(20) NOT NULL DEFAULT 'quiz_passed',
+    min_score_percent    INTEGER NOT NULL DEFAULT 70,
+    created_at           DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at           DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at           DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_chapter_prerequisites_chapter_id ON chapter_prerequisites (chapter_id);
@@ -0,0 +1,18 @@
+ALTER TABLE quiz_questions ADD COLUMN option_a TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz_questions ADD COLUMN option_b TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz_questions ADD COLUMN option_c TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz_questions ADD COLUMN option_d TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz_questions ADD COLUMN correct_answer VARCHAR(10) NOT NULL DEFAULT '';
+
+-- Only the first four options and first correct key survive; other question types lose their answer key
+UPDATE quiz_questions SET
+    option_a = COALESCE(json_extract(options, '$[0].text'), ''),
+    option_b = COALESCE(json_extract(options, '$[1].text'), ''),
+    option_c = COALESCE(json_extract(options, '$[2].text'), ''),
+    option_d = COALESCE(json_extract(options, '$[3].text'), ''),
+    correct_answer = CASE WHEN question_type = 'multiple_choice'
+        THEN COALESCE(json_extract(answer_key, '$.option_keys[0]'), '') ELSE '' END;
+
+ALTER TABLE quiz_questions DROP COLUMN answer_key;
+ALTER TABLE quiz_questions DROP COLUMN options;
+ALTER TABLE quiz_questions DROP COLUMN question_type;
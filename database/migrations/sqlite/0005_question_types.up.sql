@@ -0,0 +1,27 @@
+ALTER TABLE quiz_questions ADD COLUMN question_type VARCHAR(20) NOT NULL DEFAULT 'multiple_choice';
+ALTER TABLE quiz_questions ADD COLUMN options TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE quiz_questions ADD COLUMN answer_key TEXT NOT NULL DEFAULT '{}';
+
+-- Existing A-D questions become multiple choice with a variable-length option list
+UPDATE quiz_questions SET
+    options = CASE
+        WHEN option_c = '' THEN json_array(
+            json_object('key', 'A', 'text', option_a),
+            json_object('key', 'B', 'text', option_b))
+        WHEN option_d = '' THEN json_array(
+            json_object('key', 'A', 'text', option_a),
+            json_object('key', 'B', 'text', option_b),
+            json_object('key', 'C', 'text', option_c))
+        ELSE json_array(
+            json_object('key', 'A', 'text', option_a),
+            json_object('key', 'B', 'text', option_b),
+            json_object('key', 'C', 'text', option_c),
+            json_object('key', 'D', 'text', option_d))
+    END,
+    answer_key = json_object('option_keys', json_array(correct_answer));
+
+ALTER TABLE quiz_questions DROP COLUMN option_a;
+ALTER TABLE quiz_questions DROP COLUMN option_b;
+ALTER TABLE quiz_questions DROP COLUMN option_c;
+ALTER TABLE quiz_questions DROP COLUMN option_d;
+ALTER TABLE quiz_questions DROP COLUMN correct_answer;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id          VARCHAR(255) NOT NULL,
+    chapter_id       INTEGER NOT NULL REFERENCES chapters(id),
+    status           VARCHAR(20) NOT NULL DEFAULT 'in_progress',
+    total_questions  INTEGER NOT NULL DEFAULT 0,
+    correct_answers  INTEGER NOT NULL DEFAULT 0,
+    score_percent    REAL NOT NULL DEFAULT 0,
+    started_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at      DATETIME,
+    created_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at       DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_user_chapter ON quiz_attempts (user_id, chapter_id);
+
+-- Answers given before attempts existed keep a NULL attempt
+ALTER TABLE quiz_answers ADD COLUMN attempt_id INTEGER REFERENCES quiz_attempts(id);
+CREATE INDEX IF NOT EXISTS idx_quiz_answers_attempt_id ON quiz_answers (attempt_id);
@@ -0,0 +1,4 @@
+ALTER TABLE quiz_answers DROP COLUMN is_late;
+ALTER TABLE quiz_attempts DROP COLUMN deadline_at;
+ALTER TABLE chapters DROP COLUMN quiz_late_policy;
+ALTER TABLE chapters DROP COLUMN quiz_time_limit_seconds;
@@ -0,0 +1,2 @@
+ALTER TABLE chapters DROP COLUMN shuffle_options;
+ALTER TABLE chapters DROP COLUMN shuffle_questions;
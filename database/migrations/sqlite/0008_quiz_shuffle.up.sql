@@ -0,0 +1,2 @@
+ALTER TABLE chapters ADD COLUMN shuffle_questions BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE chapters ADD COLUMN shuffle_options BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,41 @@
+-- Named banks of a chapter's questions that quiz rules draw from
+CREATE TABLE IF NOT EXISTS question_pools (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    chapter_id   INTEGER NOT NULL REFERENCES chapters(id),
+    title        VARCHAR(255) NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at   DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_pools_chapter_id ON question_pools (chapter_id);
+
+-- Questions outside any pool keep a NULL pool_id (no REFERENCES: SQLite cannot drop a
+-- foreign-key column in the down migration)
+ALTER TABLE quiz_questions ADD COLUMN pool_id INTEGER;
+ALTER TABLE quiz_questions ADD COLUMN topic VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE quiz_questions ADD COLUMN difficulty VARCHAR(10) NOT NULL DEFAULT '';
+
+-- "Draw draw_count questions from pool_id"; chapters without rules show every question
+CREATE TABLE IF NOT EXISTS quiz_draw_rules (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    chapter_id   INTEGER NOT NULL REFERENCES chapters(id),
+    pool_id      INTEGER NOT NULL REFERENCES question_pools(id),
+    draw_count   INTEGER NOT NULL,
+    topic        VARCHAR(100) NOT NULL DEFAULT '',
+    difficulty   VARCHAR(10) NOT NULL DEFAULT '',
+    created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at   DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_draw_rules_chapter_id ON quiz_draw_rules (chapter_id);
+
+-- The questions drawn for an attempt, frozen when it starts
+CREATE TABLE IF NOT EXISTS quiz_attempt_questions (
+    attempt_id        INTEGER NOT NULL REFERENCES quiz_attempts(id),
+    quiz_question_id  INTEGER NOT NULL REFERENCES quiz_questions(id),
+    position          INTEGER NOT NULL,
+    PRIMARY KEY (attempt_id, quiz_question_id)
+);
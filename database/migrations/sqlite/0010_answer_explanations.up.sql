@@ -0,0 +1 @@
+ALTER TABLE quiz_questions ADD COLUMN explanation TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS review_answers;
+DROP TABLE IF EXISTS review_items;
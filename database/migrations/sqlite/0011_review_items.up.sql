@@ -0,0 +1,42 @@
+-- One spaced-repetition (SM-2) schedule per user and answered question
+CREATE TABLE IF NOT EXISTS review_items (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id           VARCHAR(255) NOT NULL,
+    chapter_id        INTEGER NOT NULL REFERENCES chapters(id),
+    quiz_question_id  INTEGER NOT NULL REFERENCES quiz_questions(id),
+    ease_factor       REAL NOT NULL DEFAULT 2.5,
+    interval_days     INTEGER NOT NULL DEFAULT 0,
+    repetitions       INTEGER NOT NULL DEFAULT 0,
+    lapses            INTEGER NOT NULL DEFAULT 0,
+    due_at            DATETIME NOT NULL,
+    last_reviewed_at  DATETIME,
+    created_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at        DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_review_items_user_question ON review_items (user_id, quiz_question_id)
+    WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_review_items_user_due ON review_items (user_id, due_at);
+
+-- Answers given in review sessions; kept apart from quiz_answers so chapter scores are unaffected
+CREATE TABLE IF NOT EXISTS review_answers (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    review_item_id    INTEGER NOT NULL REFERENCES review_items(id),
+    user_id           VARCHAR(255) NOT NULL,
+    quiz_question_id  INTEGER NOT NULL REFERENCES quiz_questions(id),
+    user_answer       TEXT NOT NULL,
+    is_correct        BOOLEAN NOT NULL,
+    quality           INTEGER NOT NULL,
+    reviewed_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_answers_review_item_id ON review_answers (review_item_id);
+
+-- Questions answered before reviews existed are due right away
+INSERT INTO review_items (user_id, chapter_id, quiz_question_id, due_at)
+SELECT user_id, chapter_id, quiz_question_id, strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')
+FROM quiz_answers
+WHERE deleted_at IS NULL
+GROUP BY user_id, chapter_id, quiz_question_id;
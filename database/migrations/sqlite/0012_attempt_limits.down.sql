@@ -0,0 +1,3 @@
+ALTER TABLE quiz_questions DROP COLUMN max_attempts;
+ALTER TABLE chapters DROP COLUMN answer_cooldown_seconds;
+ALTER TABLE chapters DROP COLUMN max_answer_attempts;
@@ -0,0 +1,4 @@
+-- Answer limits per question (0 = unlimited); a question's own max_attempts overrides its chapter's
+ALTER TABLE chapters ADD COLUMN max_answer_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE chapters ADD COLUMN answer_cooldown_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE quiz_questions ADD COLUMN max_attempts INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS chapter_completions;
+ALTER TABLE chapters DROP COLUMN pass_score_percent;
+ALTER TABLE chapters DROP COLUMN min_video_percent;
@@ -0,0 +1,18 @@
+-- Server-side completion rules: how much of the video must be watched and the quiz score to pass
+ALTER TABLE chapters ADD COLUMN min_video_percent INTEGER NOT NULL DEFAULT 90;
+ALTER TABLE chapters ADD COLUMN pass_score_percent INTEGER NOT NULL DEFAULT 70;
+
+-- Chapters a learner has passed; recorded once and kept even if later answers score lower
+CREATE TABLE IF NOT EXISTS chapter_completions (
+    id                  INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id             VARCHAR(255) NOT NULL,
+    chapter_id          INTEGER NOT NULL REFERENCES chapters(id),
+    video_percent       REAL,
+    quiz_score_percent  REAL,
+    completed_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at          DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at          DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chapter_completions_user_chapter ON chapter_completions (user_id, chapter_id)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,16 @@
+-- Parts of a video a learner has actually played, merged from heartbeat intervals
+CREATE TABLE IF NOT EXISTS video_watches (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id          VARCHAR(255) NOT NULL,
+    chapter_id       INTEGER NOT NULL REFERENCES chapters(id),
+    video_id         INTEGER NOT NULL REFERENCES videos(id),
+    watched_ranges   TEXT NOT NULL DEFAULT '[]',
+    watched_seconds  REAL NOT NULL DEFAULT 0,
+    last_position    REAL NOT NULL DEFAULT 0,
+    created_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at       DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_video_watches_user_video ON video_watches (user_id, video_id)
+    WHERE deleted_at IS NULL;
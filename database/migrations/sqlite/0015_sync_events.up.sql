@@ -0,0 +1,13 @@
+-- Offline events already synced, by the client's event ID, so resent batches are not applied twice
+CREATE TABLE IF NOT EXISTS sync_events (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id          VARCHAR(255) NOT NULL,
+    client_event_id  VARCHAR(255) NOT NULL,
+    event_type       VARCHAR(20) NOT NULL,
+    status           VARCHAR(20) NOT NULL,
+    message          TEXT NOT NULL DEFAULT '',
+    occurred_at      DATETIME NOT NULL,
+    created_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sync_events_user_event ON sync_events (user_id, client_event_id);
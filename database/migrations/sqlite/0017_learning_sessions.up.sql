@@ -0,0 +1,15 @@
+-- Stretches of continuous learner activity on a chapter, split where the learner went idle
+CREATE TABLE IF NOT EXISTS learning_sessions (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id           VARCHAR(255) NOT NULL,
+    chapter_id        INTEGER NOT NULL REFERENCES chapters(id),
+    started_at        DATETIME NOT NULL,
+    last_activity_at  DATETIME NOT NULL,
+    active_seconds    REAL NOT NULL DEFAULT 0,
+    activity_count    INTEGER NOT NULL DEFAULT 0,
+    created_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_learning_sessions_user_chapter ON learning_sessions (user_id, chapter_id, last_activity_at);
+CREATE INDEX IF NOT EXISTS idx_learning_sessions_user_started ON learning_sessions (user_id, started_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_review_answers_user_reviewed;
+DROP INDEX IF EXISTS idx_quiz_answers_user_answered;
+DROP TABLE IF EXISTS streak_freezes;
+DROP TABLE IF EXISTS streak_goals;
@@ -0,0 +1,27 @@
+-- Learners' daily goals. A new row takes effect from its day on, so changing the goal never
+-- re-judges days that are already over
+CREATE TABLE IF NOT EXISTS streak_goals (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id         VARCHAR(255) NOT NULL,
+    goal_type       VARCHAR(20) NOT NULL,
+    target          INTEGER NOT NULL,
+    timezone        VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    effective_from  VARCHAR(10) NOT NULL,
+    created_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_streak_goals_user_effective ON streak_goals (user_id, effective_from);
+
+-- Days a learner froze so missing their goal on them does not break the streak
+CREATE TABLE IF NOT EXISTS streak_freezes (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id      VARCHAR(255) NOT NULL,
+    freeze_date  VARCHAR(10) NOT NULL,
+    created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_streak_freezes_user_date ON streak_freezes (user_id, freeze_date);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_answers_user_answered ON quiz_answers (user_id, answered_at);
+CREATE INDEX IF NOT EXISTS idx_review_answers_user_reviewed ON review_answers (user_id, reviewed_at);
@@ -0,0 +1 @@
+ALTER TABLE video_watches DROP COLUMN last_heartbeat_at;
@@ -0,0 +1,4 @@
+-- When the latest heartbeat on a watch happened, so the next one cannot claim more video than
+-- could have played since
+ALTER TABLE video_watches ADD COLUMN last_heartbeat_at DATETIME;
+UPDATE video_watches SET last_heartbeat_at = updated_at;
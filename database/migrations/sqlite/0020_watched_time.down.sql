@@ -0,0 +1 @@
+ALTER TABLE learning_sessions DROP COLUMN watched_seconds;
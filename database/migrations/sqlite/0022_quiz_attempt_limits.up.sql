@@ -0,0 +1,2 @@
+-- Quiz attempts a learner may start per chapter (0 = unlimited)
+ALTER TABLE chapters ADD COLUMN max_quiz_attempts INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"learning-app-backend/models"
+	"learning-app-backend/store"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DailyActivity - A learner's time on task for one day in their time zone
+type DailyActivity struct {
+	Date           string  `json:"date"` // YYYY-MM-DD
+	ActiveSeconds  float64 `json:"active_seconds"`
+	WatchedSeconds float64 `json:"watched_seconds"`
+	SessionCount   int     `json:"session_count"`
+}
+
+// recordActivity - Count a save, heartbeat or answer on a chapter toward the learner's time on
+// task, and the video a heartbeat played (already capped by its allowance) toward their watched
+// time. Only the time between the learner's writes counts, never time a client claims. Offline
+// events (see SyncEvents) count when they happened, but never before the learner's latest
+// recorded activity, so a batch cannot add more time than passed since. The learner's streak
+// record is brought up to today as well
+func (h *Handler) recordActivity(userID string, chapterID uint, offlineAt *time.Time, watched float64) error {
+	at := time.Now()
+	var since time.Time
+	latest, err := h.Progress.FindLearningSession(userID, 0, time.Time{}, at)
+	if err == nil {
+		since = latest.LastActivityAt
+	} else if err != store.ErrNotFound {
+		return err
+	}
+	if offlineAt != nil {
+		at = *offlineAt
+		if at.Before(since) {
+			at = since
+		}
+	}
+
+	// Activity within the idle timeout of a session continues it
+	session, err := h.Progress.FindLearningSession(userID, chapterID, at.Add(-models.SessionIdleTimeout), at)
+	if err == store.ErrNotFound {
+		session = &models.LearningSession{UserID: userID, ChapterID: chapterID}
+	} else if err != nil {
+		return err
+	}
+
+	session.Record(at, since)
+	session.WatchedSeconds += watched
+	if err := h.Progress.SaveLearningSession(session); err != nil {
+		return err
+	}
+	return h.refreshStreakRecord(userID)
+}
+
+// chapterActiveTime - The learner's total time on task per chapter, with chapter titles
+func (h *Handler) chapterActiveTime(sessions []models.LearningSession) ([]models.ChapterActiveTime, error) {
+	chapters, err := h.Chapters.ListChapters(0)
+	if err != nil {
+		return nil, err
+	}
+	titles := map[uint]string{}
+	for _, chapter := range chapters {
+		titles[chapter.ID] = chapter.Title
+	}
+
+	totals := models.SummarizeSessions(sessions)
+	for i := range totals {
+		totals[i].ChapterTitle = titles[totals[i].ChapterID]
+	}
+	return totals, nil
+}
+
+// GetActivitySummary - Get the learner's time on task per chapter and per day, optionally
+// within a date range (?from=&to=, see GetProgressTimeline)
+func (h *Handler) GetActivitySummary(c *gin.Context) {
+	userID := c.Param("userId")
+
+	// Days are counted in the learner's time zone (UTC unless ?tz= names one)
+	loc, err := time.LoadLocation(c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid time zone",
+		})
+		return
+	}
+	from, ok := parseTimelineBound(c, "from", loc, false)
+	if !ok {
+		return
+	}
+	to, ok := parseTimelineBound(c, "to", loc, true)
+	if !ok {
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "from must be before to",
+		})
+		return
+	}
+
+	sessions, err := h.Progress.ListLearningSessions(userID, from, to)
+	var chapters []models.ChapterActiveTime
+	if err == nil {
+		chapters, err = h.chapterActiveTime(sessions)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch learning activity",
+		})
+		return
+	}
+
+	// A session counts toward the day it started on
+	total := 0.0
+	byDay := map[string]*DailyActivity{}
+	for _, session := range sessions {
+		total += session.ActiveSeconds
+		date := session.StartedAt.In(loc).Format("2006-01-02")
+		day, ok := byDay[date]
+		if !ok {
+			day = &DailyActivity{Date: date}
+			byDay[date] = day
+		}
+		day.ActiveSeconds += session.ActiveSeconds
+		day.WatchedSeconds += session.WatchedSeconds
+		day.SessionCount++
+	}
+	days := make([]DailyActivity, 0, len(byDay))
+	for _, day := range byDay {
+		days = append(days, *day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":              true,
+		"timezone":             loc.String(),
+		"total_active_seconds": total,
+		"session_count":        len(sessions),
+		"idle_timeout_seconds": int(models.SessionIdleTimeout.Seconds()),
+		"chapters":             chapters,
+		"days":                 days,
+	})
+}
@@ -1,8 +1,9 @@
 package handlers
 
 import (
-	"database/sql"
-	"learning-app-backend/database"
+	"learning-app-backend/auth"
+	"learning-app-backend/middleware"
+	"learning-app-backend/store"
 	"net/http"
 	"strings"
 	"time"
@@ -10,21 +11,32 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+type RegisterRequest struct {
+	UserID   string `json:"user_id" binding:"required"`
+	Username string `json:"username"`
+	Password string `json:"password" binding:"required"`
+}
+
 type LoginRequest struct {
-	UserID string `json:"user_id" binding:"required"`
+	UserID   string `json:"user_id" binding:"required"`
+	Password string `json:"password" binding:"required"`
 }
 
-type User struct {
-	ID        uint      `json:"id"`
-	UserID    string    `json:"user_id"`
-	Username  string    `json:"username"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
-// Login - Query-driven login
-func Login(c *gin.Context) {
-	var req LoginRequest
+type AuthTokens struct {
+	AccessToken           string    `json:"access_token"`
+	TokenType             string    `json:"token_type"`
+	ExpiresAt             time.Time `json:"expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+// Register - Create a user with a hashed password
+func (h *Handler) Register(c *gin.Context) {
+	var req RegisterRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -43,34 +55,99 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	sqlDB, _ := database.DB.DB()
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Username == "" {
+		req.Username = req.UserID
+	}
+
+	passwordHash, err := auth.HashPassword(req.Password)
+	if err == auth.ErrPasswordTooShort {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Password must be at least 8 characters",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create user",
+		})
+		return
+	}
+
+	// Check if user already exists
+	userExists, err := h.Users.UserExists(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+	if userExists {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "User ID is already taken",
+		})
+		return
+	}
+
+	user, err := h.Users.CreateUser(req.UserID, req.Username, passwordHash)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create user",
+		})
+		return
+	}
+
+	tokens, err := h.createSession(user.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create session",
+		})
+		return
+	}
 
-	// Check if user exists
-	var user User
-	query := `SELECT id, user_id, username, created_at, updated_at
-			  FROM users WHERE user_id = $1 AND deleted_at IS NULL`
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Registration successful",
+		"user":    user,
+		"tokens":  tokens,
+	})
+}
+
+// Login - Verify password and issue access + refresh tokens
+func (h *Handler) Login(c *gin.Context) {
+	var req LoginRequest
 
-	err := sqlDB.QueryRow(query, req.UserID).Scan(
-		&user.ID, &user.UserID, &user.Username, &user.CreatedAt, &user.UpdatedAt,
-	)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
 
-	if err == sql.ErrNoRows {
-		// Create new user
-		insertQuery := `INSERT INTO users (user_id, username, created_at, updated_at)
-						VALUES ($1, $2, NOW(), NOW())
-						RETURNING id, user_id, username, created_at, updated_at`
+	req.UserID = strings.TrimSpace(req.UserID)
+	if req.UserID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "User ID cannot be empty",
+		})
+		return
+	}
 
-		err = sqlDB.QueryRow(insertQuery, req.UserID, req.UserID).Scan(
-			&user.ID, &user.UserID, &user.Username, &user.CreatedAt, &user.UpdatedAt,
-		)
+	user, err := h.Users.GetUser(req.UserID)
 
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"message": "Failed to create user",
-			})
-			return
-		}
+	// Same response for unknown users and wrong passwords to avoid user enumeration
+	if err == store.ErrNotFound || (err == nil && (user.PasswordHash == "" || !auth.CheckPassword(user.PasswordHash, req.Password))) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Invalid user ID or password",
+		})
+		return
 	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -79,15 +156,100 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	tokens, err := h.createSession(user.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create session",
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Login successful",
 		"user":    user,
+		"tokens":  tokens,
+	})
+}
+
+// RefreshToken - Exchange a refresh token for a new access token (rotates the refresh token)
+func (h *Handler) RefreshToken(c *gin.Context) {
+	var req RefreshRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	session, err := h.Users.FindActiveSessionByRefreshHash(auth.HashRefreshToken(req.RefreshToken))
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Invalid or expired refresh token",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+
+	refreshToken, refreshHash, err := auth.NewRefreshToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to refresh session",
+		})
+		return
+	}
+
+	refreshExpiresAt := time.Now().UTC().Add(auth.RefreshTokenTTL())
+	if err := h.Users.RotateSession(session.ID, refreshHash, refreshExpiresAt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to refresh session",
+		})
+		return
+	}
+
+	accessToken, expiresAt, err := auth.IssueAccessToken(session.UserID, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to refresh session",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Token refreshed",
+		"tokens": AuthTokens{
+			AccessToken:           accessToken,
+			TokenType:             "Bearer",
+			ExpiresAt:             expiresAt,
+			RefreshToken:          refreshToken,
+			RefreshTokenExpiresAt: refreshExpiresAt,
+		},
 	})
 }
 
-// Logout - Simple logout endpoint
-func Logout(c *gin.Context) {
+// Logout - Revoke the current session (access and refresh tokens)
+func (h *Handler) Logout(c *gin.Context) {
+	if err := h.Users.RevokeSession(middleware.AuthSessionID(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to logout",
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Logout successful",
@@ -95,20 +257,11 @@ func Logout(c *gin.Context) {
 }
 
 // GetUser - Get user details
-func GetUser(c *gin.Context) {
+func (h *Handler) GetUser(c *gin.Context) {
 	userID := c.Param("userId")
 
-	sqlDB, _ := database.DB.DB()
-
-	var user User
-	query := `SELECT id, user_id, username, created_at, updated_at
-			  FROM users WHERE user_id = $1 AND deleted_at IS NULL`
-
-	err := sqlDB.QueryRow(query, userID).Scan(
-		&user.ID, &user.UserID, &user.Username, &user.CreatedAt, &user.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
+	user, err := h.Users.GetUser(userID)
+	if err == store.ErrNotFound {
 		c.JSON(http.StatusNotFound, gin.H{
 			"success": false,
 			"message": "User not found",
@@ -127,3 +280,43 @@ func GetUser(c *gin.Context) {
 		"user":    user,
 	})
 }
+
+// createSession - Store a new auth session and issue its tokens
+func (h *Handler) createSession(userID string) (*AuthTokens, error) {
+	refreshToken, refreshHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshExpiresAt := time.Now().UTC().Add(auth.RefreshTokenTTL())
+
+	session, err := h.Users.CreateSession(userID, refreshHash, refreshExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, expiresAt, err := auth.IssueAccessToken(userID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthTokens{
+		AccessToken:           accessToken,
+		TokenType:             "Bearer",
+		ExpiresAt:             expiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// requireSameUser - Reject body-supplied user IDs that don't match the token
+func requireSameUser(c *gin.Context, userID string) bool {
+	if userID != middleware.AuthUserID(c) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"message": "You can only access your own data",
+		})
+		return false
+	}
+	return true
+}
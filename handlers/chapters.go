@@ -1,61 +1,30 @@
 package handlers
 
 import (
-	"database/sql"
-	"learning-app-backend/database"
+	"learning-app-backend/middleware"
+	"learning-app-backend/models"
+	"learning-app-backend/store"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-type Chapter struct {
-	ID          uint      `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	OrderIndex  int       `json:"order_index"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-}
-
-type Video struct {
-	ID              uint      `json:"id"`
-	ChapterID       uint      `json:"chapter_id"`
-	Title           string    `json:"title"`
-	VideoURL        string    `json:"video_url"`
-	DurationSeconds int       `json:"duration_seconds"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
-}
-
-type QuizQuestion struct {
-	ID            uint      `json:"id"`
-	ChapterID     uint      `json:"chapter_id"`
-	QuestionText  string    `json:"question_text"`
-	OptionA       string    `json:"option_a"`
-	OptionB       string    `json:"option_b"`
-	OptionC       string    `json:"option_c"`
-	OptionD       string    `json:"option_d"`
-	CorrectAnswer string    `json:"correct_answer"`
-	OrderIndex    int       `json:"order_index"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+// ChapterWithLock - A chapter plus whether the caller has unlocked it
+type ChapterWithLock struct {
+	models.Chapter
+	ChapterLock
 }
 
 type ChapterWithContent struct {
-	Chapter
-	Video         *Video         `json:"video,omitempty"`
-	QuizQuestions []QuizQuestion `json:"quiz_questions,omitempty"`
+	models.Chapter
+	ChapterLock
+	Video         *models.Video                `json:"video,omitempty"`
+	QuizQuestions []models.LearnerQuizQuestion `json:"quiz_questions,omitempty"`
 }
 
-// GetAllChaptersRaw - Get all chapters using raw SQL
-func GetAllChapters(c *gin.Context) {
-	sqlDB, _ := database.DB.DB()
-
-	query := `SELECT id, title, description, order_index, created_at, updated_at
-			  FROM chapters WHERE deleted_at IS NULL ORDER BY order_index ASC`
-
-	rows, err := sqlDB.Query(query)
+// GetAllChapters - Get all chapters
+func (h *Handler) GetAllChapters(c *gin.Context) {
+	chapters, err := h.Chapters.ListChapters(0)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -63,39 +32,31 @@ func GetAllChapters(c *gin.Context) {
 		})
 		return
 	}
-	defer rows.Close()
-
-	var chapters []Chapter
-	for rows.Next() {
-		var ch Chapter
-		err := rows.Scan(&ch.ID, &ch.Title, &ch.Description, &ch.OrderIndex, &ch.CreatedAt, &ch.UpdatedAt)
-		if err != nil {
-			continue
-		}
-		chapters = append(chapters, ch)
+
+	lockedChapters, err := h.withLocks(c, chapters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to evaluate prerequisites",
+		})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":  true,
-		"chapters": chapters,
+		"chapters": lockedChapters,
 	})
 }
 
-// GetChapterByIDRaw - Get chapter by ID
-func GetChapterByID(c *gin.Context) {
-	chapterID := c.Param("id")
-	sqlDB, _ := database.DB.DB()
-
-	var chapter Chapter
-	query := `SELECT id, title, description, order_index, created_at, updated_at
-			  FROM chapters WHERE id = $1 AND deleted_at IS NULL`
-
-	err := sqlDB.QueryRow(query, chapterID).Scan(
-		&chapter.ID, &chapter.Title, &chapter.Description, &chapter.OrderIndex,
-		&chapter.CreatedAt, &chapter.UpdatedAt,
-	)
+// GetChapterByID - Get chapter by ID
+func (h *Handler) GetChapterByID(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
 
-	if err == sql.ErrNoRows {
+	chapter, err := h.Chapters.GetChapter(chapterID)
+	if err == store.ErrNotFound {
 		c.JSON(http.StatusNotFound, gin.H{
 			"success": false,
 			"message": "Chapter not found",
@@ -115,21 +76,19 @@ func GetChapterByID(c *gin.Context) {
 	})
 }
 
-// GetChapterVideoRaw - Get video for a chapter
-func GetChapterVideo(c *gin.Context) {
-	chapterID := c.Param("id")
-	sqlDB, _ := database.DB.DB()
-
-	var video Video
-	query := `SELECT id, chapter_id, title, video_url, duration_seconds, created_at, updated_at
-			  FROM videos WHERE chapter_id = $1 AND deleted_at IS NULL`
+// GetChapterVideo - Get video for a chapter
+func (h *Handler) GetChapterVideo(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
 
-	err := sqlDB.QueryRow(query, chapterID).Scan(
-		&video.ID, &video.ChapterID, &video.Title, &video.VideoURL,
-		&video.DurationSeconds, &video.CreatedAt, &video.UpdatedAt,
-	)
+	if !h.requireUnlocked(c, chapterID) {
+		return
+	}
 
-	if err == sql.ErrNoRows {
+	video, err := h.Chapters.GetChapterVideo(chapterID)
+	if err == store.ErrNotFound {
 		c.JSON(http.StatusNotFound, gin.H{
 			"success": false,
 			"message": "Video not found for this chapter",
@@ -149,17 +108,34 @@ func GetChapterVideo(c *gin.Context) {
 	})
 }
 
-// GetChapterQuizRaw - Get quiz questions for a chapter
-func GetChapterQuiz(c *gin.Context) {
-	chapterID := c.Param("id")
-	sqlDB, _ := database.DB.DB()
+// GetChapterQuiz - Get quiz questions for a chapter (without correct answers)
+func (h *Handler) GetChapterQuiz(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
+
+	if !h.requireUnlocked(c, chapterID) {
+		return
+	}
 
-	query := `SELECT id, chapter_id, question_text, option_a, option_b, option_c, option_d,
-			  correct_answer, order_index, created_at, updated_at
-			  FROM quiz_questions WHERE chapter_id = $1 AND deleted_at IS NULL
-			  ORDER BY order_index ASC`
+	chapter, err := h.Chapters.GetChapter(chapterID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Chapter not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
 
-	rows, err := sqlDB.Query(query, chapterID)
+	// Shuffled or pooled chapters show each learner their own questions and order
+	quiz, err := h.learnerQuizFor(middleware.AuthUserID(c), chapter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -167,21 +143,8 @@ func GetChapterQuiz(c *gin.Context) {
 		})
 		return
 	}
-	defer rows.Close()
-
-	var questions []QuizQuestion
-	for rows.Next() {
-		var q QuizQuestion
-		err := rows.Scan(&q.ID, &q.ChapterID, &q.QuestionText, &q.OptionA, &q.OptionB,
-			&q.OptionC, &q.OptionD, &q.CorrectAnswer, &q.OrderIndex,
-			&q.CreatedAt, &q.UpdatedAt)
-		if err != nil {
-			continue
-		}
-		questions = append(questions, q)
-	}
 
-	if len(questions) == 0 {
+	if len(quiz.questions) == 0 {
 		c.JSON(http.StatusNotFound, gin.H{
 			"success": false,
 			"message": "No quiz questions found for this chapter",
@@ -191,26 +154,20 @@ func GetChapterQuiz(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":   true,
-		"questions": questions,
+		"questions": quiz.presentation.learnerViews(quiz.questions),
 	})
 }
 
-// GetChapterContentRaw - Get chapter with video and quiz questions
-func GetChapterContent(c *gin.Context) {
-	chapterID := c.Param("id")
-	sqlDB, _ := database.DB.DB()
+// GetChapterContent - Get chapter with video and quiz questions
+func (h *Handler) GetChapterContent(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
 
 	// Get chapter
-	var chapter ChapterWithContent
-	chapterQuery := `SELECT id, title, description, order_index, created_at, updated_at
-					 FROM chapters WHERE id = $1 AND deleted_at IS NULL`
-
-	err := sqlDB.QueryRow(chapterQuery, chapterID).Scan(
-		&chapter.ID, &chapter.Title, &chapter.Description, &chapter.OrderIndex,
-		&chapter.CreatedAt, &chapter.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
+	ch, err := h.Chapters.GetChapter(chapterID)
+	if err == store.ErrNotFound {
 		c.JSON(http.StatusNotFound, gin.H{
 			"success": false,
 			"message": "Chapter not found",
@@ -224,40 +181,34 @@ func GetChapterContent(c *gin.Context) {
 		return
 	}
 
-	// Get video
-	var video Video
-	videoQuery := `SELECT id, chapter_id, title, video_url, duration_seconds, created_at, updated_at
-				   FROM videos WHERE chapter_id = $1 AND deleted_at IS NULL`
+	lock, err := h.chapterLock(c, chapterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to evaluate prerequisites",
+		})
+		return
+	}
+
+	chapter := ChapterWithContent{Chapter: *ch, ChapterLock: lock}
 
-	err = sqlDB.QueryRow(videoQuery, chapterID).Scan(
-		&video.ID, &video.ChapterID, &video.Title, &video.VideoURL,
-		&video.DurationSeconds, &video.CreatedAt, &video.UpdatedAt,
-	)
+	// Locked chapters only show what is missing, not the content
+	if lock.Locked {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"chapter": chapter,
+		})
+		return
+	}
 
-	if err == nil {
-		chapter.Video = &video
+	// Get video
+	if video, err := h.Chapters.GetChapterVideo(chapterID); err == nil {
+		chapter.Video = video
 	}
 
-	// Get quiz questions
-	quizQuery := `SELECT id, chapter_id, question_text, option_a, option_b, option_c, option_d,
-				  correct_answer, order_index, created_at, updated_at
-				  FROM quiz_questions WHERE chapter_id = $1 AND deleted_at IS NULL
-				  ORDER BY order_index ASC`
-
-	rows, err := sqlDB.Query(quizQuery, chapterID)
-	if err == nil {
-		defer rows.Close()
-		var questions []QuizQuestion
-		for rows.Next() {
-			var q QuizQuestion
-			err := rows.Scan(&q.ID, &q.ChapterID, &q.QuestionText, &q.OptionA, &q.OptionB,
-				&q.OptionC, &q.OptionD, &q.CorrectAnswer, &q.OrderIndex,
-				&q.CreatedAt, &q.UpdatedAt)
-			if err == nil {
-				questions = append(questions, q)
-			}
-		}
-		chapter.QuizQuestions = questions
+	// Get quiz questions (learner view, no correct answers)
+	if quiz, err := h.learnerQuizFor(middleware.AuthUserID(c), ch); err == nil {
+		chapter.QuizQuestions = quiz.presentation.learnerViews(quiz.questions)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -265,3 +216,47 @@ func GetChapterContent(c *gin.Context) {
 		"chapter": chapter,
 	})
 }
+
+// GetChapterAnswerKey - Get quiz questions with correct answers (instructor/admin only)
+func (h *Handler) GetChapterAnswerKey(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
+
+	questions, err := h.Chapters.ListQuizQuestions(chapterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch quiz questions",
+		})
+		return
+	}
+
+	if len(questions) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "No quiz questions found for this chapter",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"questions": questions,
+	})
+}
+
+// withLocks - Attach the caller's lock state to each chapter
+func (h *Handler) withLocks(c *gin.Context, chapters []models.Chapter) ([]ChapterWithLock, error) {
+	locks, err := h.chapterLocks(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ChapterWithLock
+	for _, chapter := range chapters {
+		result = append(result, ChapterWithLock{Chapter: chapter, ChapterLock: locks[chapter.ID]})
+	}
+	return result, nil
+}
@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"learning-app-backend/models"
+	"learning-app-backend/store"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// learnerActivity - A learner's progress rows, video watches, quiz attempts, completions and
+// latest quiz answers, with the chapters' videos, by chapter
+type learnerActivity struct {
+	progress    map[uint][]models.Progress
+	watches     map[uint]models.VideoWatch
+	attempts    map[uint][]models.QuizAttempt
+	started     map[uint]int // Quiz attempts started, cleared ones included
+	completions map[uint]models.ChapterCompletion
+	videos      map[uint]models.Video
+	questions   map[uint][]models.QuizQuestionWithUserAnswer
+}
+
+// learnerActivityFor - Load the learner's activity for one chapter (chapterID 0 = every chapter,
+// in a fixed number of queries)
+func (h *Handler) learnerActivityFor(userID string, chapterID uint) (*learnerActivity, error) {
+	var records []models.Progress
+	var err error
+	if chapterID == 0 {
+		records, err = h.Progress.ListUserProgress(userID)
+	} else {
+		records, err = h.Progress.ListChapterProgress(userID, chapterID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	watches, err := h.Progress.ListVideoWatches(userID, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := h.Quiz.ListAttempts(userID, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	started, err := h.Quiz.StartedAttempts(userID, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	completions, err := h.Progress.ListCompletions(userID)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := h.Quiz.ListQuestionsWithLatestAnswer(userID, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	var videos []models.Video
+	if chapterID == 0 {
+		if videos, err = h.Chapters.ListVideos(0); err != nil {
+			return nil, err
+		}
+	} else {
+		video, err := h.Chapters.GetChapterVideo(chapterID)
+		if err == nil {
+			videos = append(videos, *video)
+		} else if err != store.ErrNotFound {
+			return nil, err
+		}
+	}
+
+	activity := &learnerActivity{
+		progress:    map[uint][]models.Progress{},
+		watches:     map[uint]models.VideoWatch{},
+		attempts:    map[uint][]models.QuizAttempt{},
+		started:     started,
+		completions: map[uint]models.ChapterCompletion{},
+		videos:      map[uint]models.Video{},
+		questions:   map[uint][]models.QuizQuestionWithUserAnswer{},
+	}
+	for _, record := range records {
+		activity.progress[record.ChapterID] = append(activity.progress[record.ChapterID], record)
+	}
+	for _, watch := range watches {
+		activity.watches[watch.ChapterID] = watch
+	}
+	for _, attempt := range attempts {
+		activity.attempts[attempt.ChapterID] = append(activity.attempts[attempt.ChapterID], attempt)
+	}
+	for _, completion := range completions {
+		activity.completions[completion.ChapterID] = completion
+	}
+	for _, question := range questions {
+		activity.questions[question.ChapterID] = append(activity.questions[question.ChapterID], question)
+	}
+	for _, video := range videos {
+		activity.videos[video.ChapterID] = video
+	}
+	return activity, nil
+}
+
+// quizResult - A learner's standing on a chapter's quiz
+type quizResult struct {
+	questions int
+	answered  int
+	score     float64 // The better of the latest answers' score and the best finished attempt
+	finished  bool    // An attempt was finished or every question has been answered
+}
+
+// quizResultFor - Score the learner's quiz for a chapter
+func (h *Handler) quizResultFor(userID string, chapterID uint, attempts []models.QuizAttempt) (quizResult, error) {
+	questions, err := h.Quiz.ListQuestionsWithLatestAnswer(userID, chapterID)
+	if err != nil {
+		return quizResult{}, err
+	}
+	return scoreQuiz(questions, attempts), nil
+}
+
+// scoreQuiz - Score a chapter's questions (with the learner's latest answers) and attempts
+func scoreQuiz(questions []models.QuizQuestionWithUserAnswer, attempts []models.QuizAttempt) quizResult {
+	if len(questions) == 0 {
+		return quizResult{}
+	}
+
+	result := quizResult{questions: len(questions)}
+	correct := 0
+	for _, q := range questions {
+		if q.HasAnswered {
+			result.answered++
+			if *q.IsCorrect {
+				correct++
+			}
+		}
+	}
+	result.score = float64(correct) / float64(len(questions)) * 100
+	result.finished = result.answered == result.questions
+
+	// Pooled quizzes only draw some questions, so finished attempts are scored on their own
+	for _, attempt := range attempts {
+		if attempt.Status == models.AttemptFinished {
+			result.finished = true
+			result.score = max(result.score, attempt.ScorePercent)
+		}
+	}
+	return result
+}
+
+// chapterStatus - Measure the learner against the chapter's completion rules: the video watched to
+// min_video_percent and the quiz scored at pass_score_percent, whichever the chapter has
+func chapterStatus(chapter *models.Chapter, activity *learnerActivity) models.ChapterStatus {
+	status := models.ChapterStatus{
+		ChapterID:        chapter.ID,
+		ChapterTitle:     chapter.Title,
+		MinVideoPercent:  chapter.MinVideoPercent,
+		PassScorePercent: chapter.PassScorePercent,
+	}
+
+	records := activity.progress[chapter.ID]
+	attempts := activity.attempts[chapter.ID]
+	started := len(records) > 0 || len(attempts) > 0
+
+	var videoRecord *models.Progress
+	markedCompleted := false
+	for i := range records {
+		if records[i].ContentType == "video" {
+			videoRecord = &records[i]
+		}
+		markedCompleted = markedCompleted || records[i].IsCompleted
+	}
+
+	video, hasVideo := activity.videos[chapter.ID]
+	if hasVideo {
+		// Only the parts actually played count, not how far the learner seeked
+		watch := activity.watches[chapter.ID]
+		watch.SetDuration(video.DurationSeconds)
+		status.VideoPercent = watch.PercentWatched
+		status.VideoCompleted = videoRecord != nil && videoRecord.IsCompleted
+	}
+
+	quiz := scoreQuiz(activity.questions[chapter.ID], attempts)
+	hasQuiz := quiz.questions > 0
+	if hasQuiz {
+		started = started || quiz.answered > 0
+		status.QuizScorePercent = &quiz.score
+		status.QuizPassed = quiz.score >= float64(chapter.PassScorePercent)
+		status.QuizAttemptsLeft = chapter.QuizAttemptsLeft(activity.started[chapter.ID])
+	}
+
+	// Chapters with neither a video nor a quiz have nothing to measure, so the client's flag stands
+	passed := markedCompleted
+	if hasVideo || hasQuiz {
+		passed = (!hasVideo || status.VideoCompleted) && (!hasQuiz || status.QuizPassed)
+	}
+
+	completion, completed := activity.completions[chapter.ID]
+	switch {
+	case completed:
+		status.Status = models.ChapterPassed
+		status.CompletedAt = &completion.CompletedAt
+	case passed:
+		status.Status = models.ChapterPassed
+	case hasQuiz && quiz.finished && !status.QuizPassed:
+		status.Status = models.ChapterFailed
+		if status.QuizAttemptsLeft != nil && *status.QuizAttemptsLeft == 0 && !attemptInProgress(attempts) {
+			status.Status = models.ChapterNoAttempts
+		}
+	case started:
+		status.Status = models.ChapterInProgress
+	default:
+		status.Status = models.ChapterNotStarted
+	}
+	return status
+}
+
+// attemptInProgress - Whether any of the attempts can still take answers
+func attemptInProgress(attempts []models.QuizAttempt) bool {
+	for _, attempt := range attempts {
+		if attempt.Status == models.AttemptInProgress {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshChapterStatus - Re-evaluate the chapter after learner activity and record its
+// completion the first time it is passed
+func (h *Handler) refreshChapterStatus(userID string, chapter *models.Chapter) (models.ChapterStatus, error) {
+	activity, err := h.learnerActivityFor(userID, chapter.ID)
+	if err != nil {
+		return models.ChapterStatus{}, err
+	}
+	status := chapterStatus(chapter, activity)
+	if status.Status != models.ChapterPassed || status.CompletedAt != nil {
+		return status, nil
+	}
+
+	completion := models.ChapterCompletion{
+		UserID:           userID,
+		ChapterID:        chapter.ID,
+		VideoPercent:     status.VideoPercent,
+		QuizScorePercent: status.QuizScorePercent,
+	}
+	if err := h.Progress.CreateCompletion(&completion); err != nil {
+		return status, err
+	}
+	if completion.ID != 0 {
+		status.CompletedAt = &completion.CompletedAt
+	}
+	return status, nil
+}
+
+// progressCompleted - Decide is_completed for a progress row on the server: a video once the learner
+// has watched min_video_percent of it, a quiz once it is passed. Rows stay completed once they are;
+// without a measurable video or any quiz questions the client's flag is kept
+func (h *Handler) progressCompleted(chapter *models.Chapter, progress *models.Progress, clientCompleted bool) (bool, error) {
+	records, err := h.Progress.ListChapterProgress(progress.UserID, chapter.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, record := range records {
+		if record.ContentType == progress.ContentType && record.IsCompleted {
+			return true, nil
+		}
+	}
+
+	if progress.ContentType == "video" {
+		video, err := h.Chapters.GetChapterVideo(chapter.ID)
+		if err == store.ErrNotFound {
+			return clientCompleted, nil
+		} else if err != nil {
+			return false, err
+		}
+		if video.DurationSeconds <= 0 {
+			return clientCompleted, nil
+		}
+		watch, err := h.Progress.GetVideoWatch(progress.UserID, video.ID)
+		if err == store.ErrNotFound {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		return *watch.PercentWatched >= float64(chapter.MinVideoPercent), nil
+	}
+
+	attempts, err := h.Quiz.ListAttempts(progress.UserID, chapter.ID)
+	if err != nil {
+		return false, err
+	}
+	quiz, err := h.quizResultFor(progress.UserID, chapter.ID, attempts)
+	if err != nil {
+		return false, err
+	}
+	if quiz.questions == 0 {
+		return clientCompleted, nil
+	}
+	return quiz.score >= float64(chapter.PassScorePercent), nil
+}
+
+// GetChapterStatus - Get a learner's status for one chapter (not_started, in_progress, passed, failed)
+func (h *Handler) GetChapterStatus(c *gin.Context) {
+	userID := c.Param("userId")
+	chapterID, ok := parseID(c, "chapterId", "chapter ID")
+	if !ok {
+		return
+	}
+
+	chapter, err := h.Chapters.GetChapter(chapterID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Chapter not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+
+	activity, err := h.learnerActivityFor(userID, chapterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to evaluate chapter status",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"status":  chapterStatus(chapter, activity),
+	})
+}
+
+// GetChapterStatuses - Get a learner's status for every chapter, optionally within one course
+func (h *Handler) GetChapterStatuses(c *gin.Context) {
+	userID := c.Param("userId")
+
+	var courseID uint
+	if raw := c.Query("course_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid course ID",
+			})
+			return
+		}
+		courseID = uint(id)
+		if !h.courseExists(c, courseID) {
+			return
+		}
+	}
+
+	statuses, err := h.chapterStatuses(userID, courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to evaluate chapter status",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"statuses": statuses,
+	})
+}
+
+// chapterStatuses - Every chapter's status for the learner in course order (courseID 0 = all courses)
+func (h *Handler) chapterStatuses(userID string, courseID uint) ([]models.ChapterStatus, error) {
+	chapters, err := h.Chapters.ListChapters(courseID)
+	if err != nil {
+		return nil, err
+	}
+	activity, err := h.learnerActivityFor(userID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]models.ChapterStatus, 0, len(chapters))
+	for i := range chapters {
+		statuses = append(statuses, chapterStatus(&chapters[i], activity))
+	}
+	return statuses, nil
+}
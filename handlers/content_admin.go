@@ -0,0 +1,917 @@
+package handlers
+
+import (
+	"encoding/json"
+	"learning-app-backend/models"
+	"learning-app-backend/store"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	OrderIndex  *int   `json:"order_index" binding:"required"`
+}
+
+type ChapterRequest struct {
+	CourseID              uint   `json:"course_id" binding:"required"`
+	Title                 string `json:"title" binding:"required"`
+	Description           string `json:"description"`
+	OrderIndex            *int   `json:"order_index" binding:"required"`
+	QuizTimeLimitSeconds  int    `json:"quiz_time_limit_seconds"`
+	QuizLatePolicy        string `json:"quiz_late_policy"`
+	ShuffleQuestions      bool   `json:"shuffle_questions"`
+	ShuffleOptions        bool   `json:"shuffle_options"`
+	MaxAnswerAttempts     int    `json:"max_answer_attempts"`
+	AnswerCooldownSeconds int    `json:"answer_cooldown_seconds"`
+	MaxQuizAttempts       int    `json:"max_quiz_attempts"`
+	MinVideoPercent       *int   `json:"min_video_percent"`  // Default 90
+	PassScorePercent      *int   `json:"pass_score_percent"` // Default 70
+}
+
+// toChapter - Build the stored chapter from a validated request
+func (r *ChapterRequest) toChapter() models.Chapter {
+	return models.Chapter{
+		CourseID:              r.CourseID,
+		Title:                 r.Title,
+		Description:           r.Description,
+		OrderIndex:            *r.OrderIndex,
+		QuizTimeLimitSeconds:  r.QuizTimeLimitSeconds,
+		QuizLatePolicy:        r.QuizLatePolicy,
+		ShuffleQuestions:      r.ShuffleQuestions,
+		ShuffleOptions:        r.ShuffleOptions,
+		MaxAnswerAttempts:     r.MaxAnswerAttempts,
+		AnswerCooldownSeconds: r.AnswerCooldownSeconds,
+		MaxQuizAttempts:       r.MaxQuizAttempts,
+		MinVideoPercent:       *r.MinVideoPercent,
+		PassScorePercent:      *r.PassScorePercent,
+	}
+}
+
+type VideoRequest struct {
+	Title           string `json:"title" binding:"required"`
+	VideoURL        string `json:"video_url" binding:"required"`
+	DurationSeconds int    `json:"duration_seconds"`
+}
+
+// QuizQuestionRequest - correct_answer is an option key, a list of keys, a number or
+// accepted text depending on question_type; option_a-option_d are still accepted in
+// place of options for multiple choice questions
+type QuizQuestionRequest struct {
+	QuestionType  string                  `json:"question_type"`
+	QuestionText  string                  `json:"question_text" binding:"required"`
+	Options       []models.QuestionOption `json:"options"`
+	OptionA       string                  `json:"option_a"`
+	OptionB       string                  `json:"option_b"`
+	OptionC       string                  `json:"option_c"`
+	OptionD       string                  `json:"option_d"`
+	CorrectAnswer json.RawMessage         `json:"correct_answer" binding:"required"`
+	Tolerance     float64                 `json:"tolerance"`
+	Explanation   string                  `json:"explanation"`
+	MaxAttempts   int                     `json:"max_attempts"`
+	PoolID        *uint                   `json:"pool_id"`
+	Topic         string                  `json:"topic"`
+	Difficulty    string                  `json:"difficulty"`
+	OrderIndex    *int                    `json:"order_index" binding:"required"`
+}
+
+// toQuestion - Build and validate the stored question ("" message when valid)
+func (r *QuizQuestionRequest) toQuestion() (models.QuizQuestion, string) {
+	q := models.QuizQuestion{
+		QuestionType: strings.TrimSpace(r.QuestionType),
+		QuestionText: strings.TrimSpace(r.QuestionText),
+		Explanation:  strings.TrimSpace(r.Explanation),
+		MaxAttempts:  r.MaxAttempts,
+		PoolID:       r.PoolID,
+		Topic:        strings.TrimSpace(r.Topic),
+		Difficulty:   strings.ToLower(strings.TrimSpace(r.Difficulty)),
+		OrderIndex:   *r.OrderIndex,
+	}
+	if q.QuestionType == "" {
+		q.QuestionType = models.QuestionMultipleChoice
+	}
+	if !models.IsValidDifficulty(q.Difficulty) {
+		return q, "difficulty must be one of: easy, medium, hard"
+	}
+
+	if q.QuestionText == "" {
+		return q, "question_text cannot be empty"
+	}
+	if *r.OrderIndex < 0 {
+		return q, "order_index cannot be negative"
+	}
+	if r.MaxAttempts < 0 {
+		return q, "max_attempts cannot be negative"
+	}
+	if !models.IsValidQuestionType(q.QuestionType) {
+		return q, q.Validate()
+	}
+
+	q.Options = r.options(q.QuestionType)
+
+	switch q.QuestionType {
+	case models.QuestionNumeric:
+		var number float64
+		if err := json.Unmarshal(r.CorrectAnswer, &number); err != nil {
+			return q, "correct_answer must be a number"
+		}
+		q.AnswerKey = models.AnswerKey{Number: &number, Tolerance: r.Tolerance}
+
+	case models.QuestionShortText:
+		var accepted []string
+		if err := json.Unmarshal(r.CorrectAnswer, &accepted); err != nil {
+			var single string
+			if err := json.Unmarshal(r.CorrectAnswer, &single); err != nil {
+				return q, "correct_answer must be accepted text or a list of accepted texts"
+			}
+			accepted = []string{single}
+		}
+		for i := range accepted {
+			accepted[i] = strings.TrimSpace(accepted[i])
+		}
+		q.AnswerKey = models.AnswerKey{AcceptedAnswers: accepted}
+
+	default:
+		// Option answers are written the way learners submit them
+		if msg := q.ValidateOptions(); msg != "" {
+			return q, msg
+		}
+		answer, err := q.NormalizeAnswer(r.CorrectAnswer)
+		if err != nil {
+			return q, "correct_answer: " + err.Error()
+		}
+		q.AnswerKey = models.AnswerKey{OptionKeys: strings.Split(answer, ",")}
+	}
+
+	return q, q.Validate()
+}
+
+// options - The request's options, with missing keys filled in as A, B, C...
+func (r *QuizQuestionRequest) options(questionType string) []models.QuestionOption {
+	switch questionType {
+	case models.QuestionTrueFalse:
+		// The options are fixed, but feedback can still be given for "true" and "false"
+		options := models.TrueFalseOptions()
+		for i := range options {
+			for _, option := range r.Options {
+				if strings.EqualFold(strings.TrimSpace(option.Key), options[i].Key) {
+					options[i].Feedback = strings.TrimSpace(option.Feedback)
+				}
+			}
+		}
+		return options
+	case models.QuestionNumeric, models.QuestionShortText:
+		return r.Options
+	}
+
+	options := r.Options
+	if len(options) == 0 && questionType == models.QuestionMultipleChoice {
+		for _, text := range []string{r.OptionA, r.OptionB, r.OptionC, r.OptionD} {
+			if strings.TrimSpace(text) != "" {
+				options = append(options, models.QuestionOption{Text: text})
+			}
+		}
+	}
+
+	result := make([]models.QuestionOption, 0, len(options))
+	for i, option := range options {
+		key := strings.TrimSpace(option.Key)
+		if key == "" {
+			key = strconv.Itoa(i + 1)
+			if i < 26 {
+				key = string(rune('A' + i))
+			}
+		}
+		result = append(result, models.QuestionOption{
+			Key:      key,
+			Text:     strings.TrimSpace(option.Text),
+			Feedback: strings.TrimSpace(option.Feedback),
+		})
+	}
+	return result
+}
+
+// CreateCourse - Create a course
+func (h *Handler) CreateCourse(c *gin.Context) {
+	var req CourseRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if msg := validateCourseRequest(&req); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return
+	}
+
+	if !h.courseOrderIndexAvailable(c, *req.OrderIndex, 0) {
+		return
+	}
+
+	course := models.Course{Title: req.Title, Description: req.Description, OrderIndex: *req.OrderIndex}
+	if err := h.Courses.CreateCourse(&course); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create course",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Course created successfully",
+		"course":  course,
+	})
+}
+
+// UpdateCourse - Replace a course's title, description and order
+func (h *Handler) UpdateCourse(c *gin.Context) {
+	courseID, ok := parseID(c, "id", "course ID")
+	if !ok {
+		return
+	}
+	var req CourseRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if msg := validateCourseRequest(&req); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return
+	}
+
+	if !h.courseExists(c, courseID) {
+		return
+	}
+
+	if !h.courseOrderIndexAvailable(c, *req.OrderIndex, courseID) {
+		return
+	}
+
+	course := models.Course{ID: courseID, Title: req.Title, Description: req.Description, OrderIndex: *req.OrderIndex}
+	err := h.Courses.UpdateCourse(&course)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Course not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update course",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Course updated successfully",
+		"course":  course,
+	})
+}
+
+// DeleteCourse - Soft delete an empty course (chapters must be moved or deleted first)
+func (h *Handler) DeleteCourse(c *gin.Context) {
+	courseID, ok := parseID(c, "id", "course ID")
+	if !ok {
+		return
+	}
+
+	if !h.courseExists(c, courseID) {
+		return
+	}
+
+	chapters, err := h.Chapters.ListChapters(courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+	if len(chapters) > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Course still has chapters; move or delete them first",
+		})
+		return
+	}
+
+	err = h.Courses.DeleteCourse(courseID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Course not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to delete course",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Course deleted successfully",
+	})
+}
+
+// CreateChapter - Create a chapter
+func (h *Handler) CreateChapter(c *gin.Context) {
+	var req ChapterRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if msg := validateChapterRequest(&req); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return
+	}
+
+	if !h.courseExists(c, req.CourseID) {
+		return
+	}
+
+	if !h.chapterOrderIndexAvailable(c, req.CourseID, *req.OrderIndex, 0) {
+		return
+	}
+
+	chapter := req.toChapter()
+	err := h.Chapters.CreateChapter(&chapter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create chapter",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Chapter created successfully",
+		"chapter": chapter,
+	})
+}
+
+// UpdateChapter - Replace a chapter's title, description and order
+func (h *Handler) UpdateChapter(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
+	var req ChapterRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if msg := validateChapterRequest(&req); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return
+	}
+
+	if !h.chapterExists(c, chapterID) {
+		return
+	}
+
+	if !h.courseExists(c, req.CourseID) {
+		return
+	}
+
+	if !h.chapterOrderIndexAvailable(c, req.CourseID, *req.OrderIndex, chapterID) {
+		return
+	}
+
+	chapter := req.toChapter()
+	chapter.ID = chapterID
+	err := h.Chapters.UpdateChapter(&chapter)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Chapter not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update chapter",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Chapter updated successfully",
+		"chapter": chapter,
+	})
+}
+
+// DeleteChapter - Soft delete a chapter together with its video and quiz questions
+func (h *Handler) DeleteChapter(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
+
+	err := h.Chapters.DeleteChapter(chapterID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Chapter not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to delete chapter",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Chapter deleted successfully",
+	})
+}
+
+// CreateChapterVideo - Attach the video to a chapter (one video per chapter)
+func (h *Handler) CreateChapterVideo(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
+	var req VideoRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if msg := validateVideoRequest(&req); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return
+	}
+
+	if !h.chapterExists(c, chapterID) {
+		return
+	}
+
+	_, err := h.Chapters.GetChapterVideo(chapterID)
+	if err == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Chapter already has a video",
+		})
+		return
+	} else if err != store.ErrNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+
+	video := models.Video{ChapterID: chapterID, Title: req.Title, VideoURL: req.VideoURL, DurationSeconds: req.DurationSeconds}
+	err = h.Chapters.CreateVideo(&video)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create video",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Video created successfully",
+		"video":   video,
+	})
+}
+
+// UpdateVideo - Replace a video's title, URL and duration
+func (h *Handler) UpdateVideo(c *gin.Context) {
+	videoID, ok := parseID(c, "videoId", "video ID")
+	if !ok {
+		return
+	}
+	var req VideoRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if msg := validateVideoRequest(&req); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return
+	}
+
+	video := models.Video{ID: videoID, Title: req.Title, VideoURL: req.VideoURL, DurationSeconds: req.DurationSeconds}
+	err := h.Chapters.UpdateVideo(&video)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Video not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update video",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Video updated successfully",
+		"video":   video,
+	})
+}
+
+// DeleteVideo - Soft delete a video
+func (h *Handler) DeleteVideo(c *gin.Context) {
+	videoID, ok := parseID(c, "videoId", "video ID")
+	if !ok {
+		return
+	}
+
+	err := h.Chapters.DeleteVideo(videoID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Video not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to delete video",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Video deleted successfully",
+	})
+}
+
+// CreateQuizQuestion - Add a quiz question to a chapter
+func (h *Handler) CreateQuizQuestion(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
+	var req QuizQuestionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	q, msg := req.toQuestion()
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return
+	}
+
+	if !h.chapterExists(c, chapterID) {
+		return
+	}
+
+	if !h.questionOrderIndexAvailable(c, chapterID, *req.OrderIndex, 0) {
+		return
+	}
+
+	if q.PoolID != nil && !h.poolInChapter(c, *q.PoolID, chapterID) {
+		return
+	}
+
+	q.ChapterID = chapterID
+	err := h.Chapters.CreateQuizQuestion(&q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create quiz question",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":  true,
+		"message":  "Quiz question created successfully",
+		"question": q,
+	})
+}
+
+// UpdateQuizQuestion - Replace a quiz question's text, options, answer and order
+func (h *Handler) UpdateQuizQuestion(c *gin.Context) {
+	questionID, ok := parseID(c, "questionId", "question ID")
+	if !ok {
+		return
+	}
+	var req QuizQuestionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	q, msg := req.toQuestion()
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return
+	}
+
+	existing, err := h.Chapters.GetQuizQuestion(questionID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Quiz question not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+
+	if !h.questionOrderIndexAvailable(c, existing.ChapterID, *req.OrderIndex, questionID) {
+		return
+	}
+
+	if q.PoolID != nil && !h.poolInChapter(c, *q.PoolID, existing.ChapterID) {
+		return
+	}
+
+	q.ID = questionID
+	err = h.Chapters.UpdateQuizQuestion(&q)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Quiz question not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update quiz question",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"message":  "Quiz question updated successfully",
+		"question": q,
+	})
+}
+
+// DeleteQuizQuestion - Soft delete a quiz question
+func (h *Handler) DeleteQuizQuestion(c *gin.Context) {
+	questionID, ok := parseID(c, "questionId", "question ID")
+	if !ok {
+		return
+	}
+
+	err := h.Chapters.DeleteQuizQuestion(questionID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Quiz question not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to delete quiz question",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Quiz question deleted successfully",
+	})
+}
+
+func validateCourseRequest(req *CourseRequest) string {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Description = strings.TrimSpace(req.Description)
+
+	if req.Title == "" {
+		return "title cannot be empty"
+	}
+	if *req.OrderIndex < 0 {
+		return "order_index cannot be negative"
+	}
+	return ""
+}
+
+func validateChapterRequest(req *ChapterRequest) string {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Description = strings.TrimSpace(req.Description)
+
+	if req.Title == "" {
+		return "title cannot be empty"
+	}
+	if *req.OrderIndex < 0 {
+		return "order_index cannot be negative"
+	}
+	if req.QuizTimeLimitSeconds < 0 {
+		return "quiz_time_limit_seconds cannot be negative"
+	}
+	if req.MaxAnswerAttempts < 0 {
+		return "max_answer_attempts cannot be negative"
+	}
+	if req.AnswerCooldownSeconds < 0 {
+		return "answer_cooldown_seconds cannot be negative"
+	}
+	if req.MaxQuizAttempts < 0 {
+		return "max_quiz_attempts cannot be negative"
+	}
+
+	if req.MinVideoPercent == nil {
+		minVideoPercent := models.DefaultMinVideoPercent
+		req.MinVideoPercent = &minVideoPercent
+	}
+	if req.PassScorePercent == nil {
+		passScorePercent := models.DefaultPassScorePercent
+		req.PassScorePercent = &passScorePercent
+	}
+	if *req.MinVideoPercent < 0 || *req.MinVideoPercent > 100 {
+		return "min_video_percent must be between 0 and 100"
+	}
+	if *req.PassScorePercent < 0 || *req.PassScorePercent > 100 {
+		return "pass_score_percent must be between 0 and 100"
+	}
+
+	req.QuizLatePolicy = strings.TrimSpace(req.QuizLatePolicy)
+	if req.QuizLatePolicy == "" {
+		req.QuizLatePolicy = models.LatePolicyReject
+	}
+	if req.QuizLatePolicy != models.LatePolicyReject && req.QuizLatePolicy != models.LatePolicyFlag {
+		return "quiz_late_policy must be 'reject' or 'flag'"
+	}
+	return ""
+}
+
+func validateVideoRequest(req *VideoRequest) string {
+	req.Title = strings.TrimSpace(req.Title)
+	req.VideoURL = strings.TrimSpace(req.VideoURL)
+
+	if req.Title == "" {
+		return "title cannot be empty"
+	}
+	if !strings.HasPrefix(req.VideoURL, "http://") && !strings.HasPrefix(req.VideoURL, "https://") {
+		return "video_url must be an http(s) URL"
+	}
+	if req.DurationSeconds < 0 {
+		return "duration_seconds cannot be negative"
+	}
+	return ""
+}
+
+// chapterExists - Respond 404 and return false if the chapter is missing
+func (h *Handler) chapterExists(c *gin.Context, chapterID uint) bool {
+	exists, err := h.Chapters.ChapterExists(chapterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return false
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Chapter not found",
+		})
+		return false
+	}
+	return true
+}
+
+// courseOrderIndexAvailable - Respond 409 and return false if another course uses the order index
+func (h *Handler) courseOrderIndexAvailable(c *gin.Context, orderIndex int, excludeID uint) bool {
+	taken, err := h.Courses.CourseOrderIndexTaken(orderIndex, excludeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return false
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Another course already uses this order_index",
+		})
+		return false
+	}
+	return true
+}
+
+// chapterOrderIndexAvailable - Respond 409 and return false if another chapter in the course uses the order index
+func (h *Handler) chapterOrderIndexAvailable(c *gin.Context, courseID uint, orderIndex int, excludeID uint) bool {
+	taken, err := h.Chapters.ChapterOrderIndexTaken(courseID, orderIndex, excludeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return false
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Another chapter in this course already uses this order_index",
+		})
+		return false
+	}
+	return true
+}
+
+// questionOrderIndexAvailable - Respond 409 and return false if the order index is taken in the chapter
+func (h *Handler) questionOrderIndexAvailable(c *gin.Context, chapterID uint, orderIndex int, excludeID uint) bool {
+	taken, err := h.Chapters.QuestionOrderIndexTaken(chapterID, orderIndex, excludeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return false
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Another question in this chapter already uses this order_index",
+		})
+		return false
+	}
+	return true
+}
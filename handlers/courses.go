@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"learning-app-backend/models"
+	"learning-app-backend/store"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseWithChapters struct {
+	models.Course
+	Chapters []ChapterWithLock `json:"chapters"`
+}
+
+// GetAllCourses - Get all courses
+func (h *Handler) GetAllCourses(c *gin.Context) {
+	courses, err := h.Courses.ListCourses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch courses",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"courses": courses,
+	})
+}
+
+// GetCourseByID - Get a course with its chapters
+func (h *Handler) GetCourseByID(c *gin.Context) {
+	courseID, ok := parseID(c, "id", "course ID")
+	if !ok {
+		return
+	}
+
+	course, err := h.Courses.GetCourse(courseID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Course not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+
+	chapters, err := h.Chapters.ListChapters(courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch chapters",
+		})
+		return
+	}
+
+	lockedChapters, err := h.withLocks(c, chapters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to evaluate prerequisites",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"course":  CourseWithChapters{Course: *course, Chapters: lockedChapters},
+	})
+}
+
+// GetCourseChapters - Get all chapters of a course
+func (h *Handler) GetCourseChapters(c *gin.Context) {
+	courseID, ok := parseID(c, "id", "course ID")
+	if !ok {
+		return
+	}
+
+	if !h.courseExists(c, courseID) {
+		return
+	}
+
+	chapters, err := h.Chapters.ListChapters(courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch chapters",
+		})
+		return
+	}
+
+	lockedChapters, err := h.withLocks(c, chapters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to evaluate prerequisites",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"chapters": lockedChapters,
+	})
+}
+
+// courseExists - Respond 404 and return false if the course is missing
+func (h *Handler) courseExists(c *gin.Context, courseID uint) bool {
+	exists, err := h.Courses.CourseExists(courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return false
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Course not found",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"learning-app-backend/models"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Why a chapter is recommended next
+const (
+	RecommendResume   = "resume"   // The learner left off here
+	RecommendRetry    = "retry"    // The quiz was finished below the pass score
+	RecommendContinue = "continue" // Started, not passed yet
+	RecommendStart    = "start"    // The first unlocked chapter not started yet
+)
+
+// DashboardChapter - A chapter's status for the learner, where it sits in its course, whether
+// the learner can open it yet and how long they have spent on it
+type DashboardChapter struct {
+	models.ChapterStatus
+	ChapterLock
+	CourseID      uint    `json:"course_id"`
+	OrderIndex    int     `json:"order_index"`
+	ActiveSeconds float64 `json:"active_seconds"`
+}
+
+// Completion - How many chapters the learner has passed
+type Completion struct {
+	PassedChapters    int     `json:"passed_chapters"`
+	TotalChapters     int     `json:"total_chapters"`
+	CompletionPercent float64 `json:"completion_percent"`
+}
+
+// CourseCompletion - Completion of one course
+type CourseCompletion struct {
+	CourseID uint   `json:"course_id"`
+	Title    string `json:"title"`
+	Completion
+}
+
+// QuizSummary - The learner's quiz scores across the chapters that have a quiz. The average
+// only covers quizzes the learner has answered
+type QuizSummary struct {
+	Quizzes             int      `json:"quizzes"`
+	Attempted           int      `json:"attempted"`
+	Passed              int      `json:"passed"`
+	AverageScorePercent *float64 `json:"average_score_percent,omitempty"`
+}
+
+// NextChapter - The chapter the learner should open next
+type NextChapter struct {
+	ChapterID    uint   `json:"chapter_id"`
+	ChapterTitle string `json:"chapter_title"`
+	CourseID     uint   `json:"course_id"`
+	Reason       string `json:"reason"`
+}
+
+// GetDashboard - Get everything a learner's dashboard shows in one response: each chapter's
+// status and lock, completion overall and per course, quiz averages, the next recommended
+// chapter and the resume point, optionally within one course (?course_id=)
+func (h *Handler) GetDashboard(c *gin.Context) {
+	userID := c.Param("userId")
+
+	var courseID uint
+	if raw := c.Query("course_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid course ID",
+			})
+			return
+		}
+		courseID = uint(id)
+		if !h.courseExists(c, courseID) {
+			return
+		}
+	}
+
+	dashboard, err := h.dashboard(userID, courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to build dashboard",
+		})
+		return
+	}
+	dashboard["success"] = true
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// dashboard - Build the dashboard from a fixed number of queries: every chapter and course, the
+// learner's activity, prerequisites, resume point and learning sessions are each loaded once
+func (h *Handler) dashboard(userID string, courseID uint) (gin.H, error) {
+	courses, err := h.Courses.ListCourses()
+	if err != nil {
+		return nil, err
+	}
+	// Prerequisites may point at other courses, so every chapter is evaluated
+	chapters, err := h.Chapters.ListChapters(0)
+	if err != nil {
+		return nil, err
+	}
+	activity, err := h.learnerActivityFor(userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	prerequisites, err := h.Chapters.ListPrerequisites(0)
+	if err != nil {
+		return nil, err
+	}
+	resume, err := h.latestProgressSummary(userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := h.Progress.ListLearningSessions(userID, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := map[uint]*models.Chapter{}
+	for i := range chapters {
+		byID[chapters[i].ID] = &chapters[i]
+	}
+	activeSeconds := map[uint]float64{}
+	for _, total := range models.SummarizeSessions(sessions) {
+		activeSeconds[total.ChapterID] = total.ActiveSeconds
+	}
+
+	// The learner's own locks, whoever is looking
+	locks := map[uint]ChapterLock{}
+	for _, p := range prerequisites {
+		required, ok := byID[p.RequiredChapterID]
+		if !ok {
+			continue
+		}
+		passed := chapterStatus(required, activity).Status == models.ChapterPassed
+		quiz := scoreQuiz(activity.questions[required.ID], activity.attempts[required.ID])
+		if reason := unmetPrerequisite(p, passed, quiz.score, quiz.questions); reason != "" {
+			lock := locks[p.ChapterID]
+			lock.Locked = true
+			lock.LockedReasons = append(lock.LockedReasons, reason)
+			locks[p.ChapterID] = lock
+		}
+	}
+
+	dashboardChapters := []DashboardChapter{}
+	completionByCourse := map[uint]Completion{}
+	var quiz QuizSummary
+	var quizScoreTotal, totalActive float64
+	for i := range chapters {
+		chapter := &chapters[i]
+		if courseID != 0 && chapter.CourseID != courseID {
+			continue
+		}
+
+		status := chapterStatus(chapter, activity)
+		dashboardChapters = append(dashboardChapters, DashboardChapter{
+			ChapterStatus: status,
+			ChapterLock:   locks[chapter.ID],
+			CourseID:      chapter.CourseID,
+			OrderIndex:    chapter.OrderIndex,
+			ActiveSeconds: activeSeconds[chapter.ID],
+		})
+		totalActive += activeSeconds[chapter.ID]
+
+		completion := completionByCourse[chapter.CourseID]
+		completion.TotalChapters++
+		if status.Status == models.ChapterPassed {
+			completion.PassedChapters++
+		}
+		completionByCourse[chapter.CourseID] = completion
+
+		if status.QuizScorePercent != nil {
+			quiz.Quizzes++
+			if status.QuizPassed {
+				quiz.Passed++
+			}
+			if result := scoreQuiz(activity.questions[chapter.ID], activity.attempts[chapter.ID]); result.answered > 0 || result.finished {
+				quiz.Attempted++
+				quizScoreTotal += *status.QuizScorePercent
+			}
+		}
+	}
+	if quiz.Attempted > 0 {
+		average := quizScoreTotal / float64(quiz.Attempted)
+		quiz.AverageScorePercent = &average
+	}
+
+	// Courses in their own order, with the overall completion across them
+	courseCompletions := []CourseCompletion{}
+	var overall Completion
+	for _, course := range courses {
+		if courseID != 0 && course.ID != courseID {
+			continue
+		}
+		completion := completionByCourse[course.ID]
+		completion.CompletionPercent = completionPercent(completion.PassedChapters, completion.TotalChapters)
+		courseCompletions = append(courseCompletions, CourseCompletion{CourseID: course.ID, Title: course.Title, Completion: completion})
+		overall.PassedChapters += completion.PassedChapters
+		overall.TotalChapters += completion.TotalChapters
+	}
+	overall.CompletionPercent = completionPercent(overall.PassedChapters, overall.TotalChapters)
+
+	return gin.H{
+		"chapters":       dashboardChapters,
+		"courses":        courseCompletions,
+		"completion":     overall,
+		"quiz":           quiz,
+		"next_chapter":   nextChapter(dashboardChapters, resume),
+		"resume":         resume,
+		"active_seconds": totalActive,
+	}, nil
+}
+
+// completionPercent - Share of chapters passed (0 when there are none)
+func completionPercent(passed, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(passed) / float64(total) * 100
+}
+
+// nextChapter - Recommend where the learner left off if that chapter is still open to them and
+// not passed, otherwise the first unlocked chapter in course order they have not passed. Chapters
+// failed with no quiz attempts left cannot be passed any more and are skipped. Nil once every
+// unlocked chapter is passed or out of attempts
+func nextChapter(chapters []DashboardChapter, resume UserProgressSummary) *NextChapter {
+	if resume.HasProgress {
+		for _, chapter := range chapters {
+			if chapter.ChapterID == *resume.LastChapterID && chapter.open() {
+				return &NextChapter{ChapterID: chapter.ChapterID, ChapterTitle: chapter.ChapterTitle,
+					CourseID: chapter.CourseID, Reason: RecommendResume}
+			}
+		}
+	}
+
+	for _, chapter := range chapters {
+		if !chapter.open() {
+			continue
+		}
+		reason := RecommendStart
+		switch chapter.Status {
+		case models.ChapterFailed:
+			reason = RecommendRetry
+		case models.ChapterInProgress:
+			reason = RecommendContinue
+		}
+		return &NextChapter{ChapterID: chapter.ChapterID, ChapterTitle: chapter.ChapterTitle,
+			CourseID: chapter.CourseID, Reason: reason}
+	}
+	return nil
+}
+
+// open - Whether the learner can still work toward passing the chapter
+func (ch DashboardChapter) open() bool {
+	return !ch.Locked && ch.Status != models.ChapterPassed && ch.Status != models.ChapterNoAttempts
+}
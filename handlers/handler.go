@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"learning-app-backend/realtime"
+	"learning-app-backend/store"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the API on top of the injected stores
+type Handler struct {
+	Users    store.UserStore
+	Courses  store.CourseStore
+	Chapters store.ChapterStore
+	Quiz     store.QuizStore
+	Reviews  store.ReviewStore
+	Progress store.ProgressStore
+	Streaks  store.StreakStore
+	Events   realtime.Broker // Pushes learner changes to their connected clients
+}
+
+// New - Create a Handler backed by the given stores and an in-process event broker
+func New(stores *store.Stores) *Handler {
+	return &Handler{
+		Users:    stores.Users,
+		Courses:  stores.Courses,
+		Chapters: stores.Chapters,
+		Quiz:     stores.Quiz,
+		Reviews:  stores.Reviews,
+		Progress: stores.Progress,
+		Streaks:  stores.Streaks,
+		Events:   realtime.NewMemoryBroker(),
+	}
+}
+
+// parseID - Read a numeric path parameter, responding 400 if it is not a valid ID
+func parseID(c *gin.Context, param, label string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(param), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid " + label,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// requestError - Why an action was refused: the status and message to answer with, plus any
+// extra response fields. Actions that offline sync shares with their endpoints return it
+type requestError struct {
+	status  int
+	message string
+	fields  gin.H
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+// refuse - A requestError without extra fields
+func refuse(status int, message string) *requestError {
+	return &requestError{status: status, message: message}
+}
+
+// with - The same error with extra response fields
+func (e *requestError) with(fields gin.H) *requestError {
+	e.fields = fields
+	return e
+}
+
+// respondError - Answer with a requestError's status, message and fields; other errors are 500s.
+// Cooldowns also set Retry-After
+func respondError(c *gin.Context, err error) {
+	refused, ok := err.(*requestError)
+	if !ok {
+		refused = refuse(http.StatusInternalServerError, "Internal server error")
+	}
+
+	response := gin.H{
+		"success": false,
+		"message": refused.message,
+	}
+	for key, value := range refused.fields {
+		response[key] = value
+	}
+	if retryAfter, ok := refused.fields["retry_after_seconds"].(int); ok {
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+	}
+	c.JSON(refused.status, response)
+}
+
+// respondResult - Answer 200 with a message and an action's result fields
+func respondResult(c *gin.Context, message string, result gin.H) {
+	response := gin.H{
+		"success": true,
+		"message": message,
+	}
+	for key, value := range result {
+		response[key] = value
+	}
+	c.JSON(http.StatusOK, response)
+}
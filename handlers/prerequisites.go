@@ -0,0 +1,437 @@
+package handlers
+
+import (
+	"fmt"
+	"learning-app-backend/auth"
+	"learning-app-backend/middleware"
+	"learning-app-backend/models"
+	"learning-app-backend/store"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMinScorePercent - Quiz score required by quiz_passed rules when none is given
+const DefaultMinScorePercent = 70
+
+type PrerequisiteRequest struct {
+	RequiredChapterID uint   `json:"required_chapter_id" binding:"required"`
+	Type              string `json:"type"`
+	MinScorePercent   *int   `json:"min_score_percent"`
+}
+
+type SequentialPrerequisitesRequest struct {
+	Type            string `json:"type"`
+	MinScorePercent *int   `json:"min_score_percent"`
+}
+
+// ChapterLock - Whether the caller may open a chapter yet, and what is still missing
+type ChapterLock struct {
+	Locked        bool     `json:"locked"`
+	LockedReasons []string `json:"locked_reasons,omitempty"`
+}
+
+// GetChapterPrerequisites - List the rules that unlock a chapter
+func (h *Handler) GetChapterPrerequisites(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
+
+	if !h.chapterExists(c, chapterID) {
+		return
+	}
+
+	prerequisites, err := h.Chapters.ListPrerequisites(chapterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch prerequisites",
+		})
+		return
+	}
+
+	lock, err := h.chapterLock(c, chapterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to evaluate prerequisites",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"prerequisites":  prerequisites,
+		"locked":         lock.Locked,
+		"locked_reasons": lock.LockedReasons,
+	})
+}
+
+// CreatePrerequisite - Require another chapter before a chapter unlocks
+func (h *Handler) CreatePrerequisite(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
+	var req PrerequisiteRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	prerequisite, msg := newPrerequisite(req.Type, req.MinScorePercent)
+	if msg == "" && req.RequiredChapterID == chapterID {
+		msg = "A chapter cannot require itself"
+	}
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return
+	}
+	prerequisite.ChapterID = chapterID
+	prerequisite.RequiredChapterID = req.RequiredChapterID
+
+	if !h.chapterExists(c, chapterID) || !h.chapterExists(c, req.RequiredChapterID) {
+		return
+	}
+
+	existing, err := h.Chapters.ListPrerequisites(0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+	if hasPrerequisite(existing, chapterID, req.RequiredChapterID) {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "This chapter already requires that chapter",
+		})
+		return
+	}
+	if createsCycle(existing, chapterID, req.RequiredChapterID) {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "This prerequisite would create a cycle between chapters",
+		})
+		return
+	}
+
+	if err := h.Chapters.CreatePrerequisite(&prerequisite); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create prerequisite",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":      true,
+		"message":      "Prerequisite created successfully",
+		"prerequisite": prerequisite,
+	})
+}
+
+// CreateSequentialPrerequisites - Make every chapter of a course require the one before it
+func (h *Handler) CreateSequentialPrerequisites(c *gin.Context) {
+	courseID, ok := parseID(c, "id", "course ID")
+	if !ok {
+		return
+	}
+	var req SequentialPrerequisitesRequest
+
+	// The body is optional, defaults are quiz_passed at 70%
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	template, msg := newPrerequisite(req.Type, req.MinScorePercent)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": msg,
+		})
+		return
+	}
+
+	if !h.courseExists(c, courseID) {
+		return
+	}
+
+	chapters, err := h.Chapters.ListChapters(courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch chapters",
+		})
+		return
+	}
+
+	existing, err := h.Chapters.ListPrerequisites(0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+
+	// Check every link first so the course is never left half-converted
+	for i := 1; i < len(chapters); i++ {
+		if createsCycle(existing, chapters[i].ID, chapters[i-1].ID) {
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"message": "Existing prerequisites conflict with the course order",
+			})
+			return
+		}
+	}
+
+	var created []models.ChapterPrerequisite
+	for i := 1; i < len(chapters); i++ {
+		if hasPrerequisite(existing, chapters[i].ID, chapters[i-1].ID) {
+			continue
+		}
+
+		prerequisite := template
+		prerequisite.ChapterID = chapters[i].ID
+		prerequisite.RequiredChapterID = chapters[i-1].ID
+		if err := h.Chapters.CreatePrerequisite(&prerequisite); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Failed to create prerequisites",
+			})
+			return
+		}
+		created = append(created, prerequisite)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":       true,
+		"message":       "Sequential prerequisites created successfully",
+		"prerequisites": created,
+	})
+}
+
+// DeletePrerequisite - Remove a prerequisite rule
+func (h *Handler) DeletePrerequisite(c *gin.Context) {
+	prerequisiteID, ok := parseID(c, "prerequisiteId", "prerequisite ID")
+	if !ok {
+		return
+	}
+
+	err := h.Chapters.DeletePrerequisite(prerequisiteID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Prerequisite not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to delete prerequisite",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Prerequisite deleted successfully",
+	})
+}
+
+// newPrerequisite - Validate the rule type and score, filling in defaults
+func newPrerequisite(requirementType string, minScorePercent *int) (models.ChapterPrerequisite, string) {
+	p := models.ChapterPrerequisite{
+		Type:            strings.ToLower(strings.TrimSpace(requirementType)),
+		MinScorePercent: DefaultMinScorePercent,
+	}
+	if p.Type == "" {
+		p.Type = models.PrerequisiteQuizPassed
+	}
+	if p.Type != models.PrerequisiteQuizPassed && p.Type != models.PrerequisiteCompleted {
+		return p, "Invalid type. Must be 'quiz_passed' or 'completed'"
+	}
+	if minScorePercent != nil {
+		if *minScorePercent < 0 || *minScorePercent > 100 {
+			return p, "min_score_percent must be between 0 and 100"
+		}
+		p.MinScorePercent = *minScorePercent
+	}
+	return p, ""
+}
+
+// hasPrerequisite - Check whether the chapter already requires the other chapter
+func hasPrerequisite(existing []models.ChapterPrerequisite, chapterID, requiredChapterID uint) bool {
+	for _, p := range existing {
+		if p.ChapterID == chapterID && p.RequiredChapterID == requiredChapterID {
+			return true
+		}
+	}
+	return false
+}
+
+// createsCycle - A new rule creates a cycle if the required chapter already (transitively) requires the chapter
+func createsCycle(existing []models.ChapterPrerequisite, chapterID, requiredChapterID uint) bool {
+	requires := map[uint][]uint{}
+	for _, p := range existing {
+		requires[p.ChapterID] = append(requires[p.ChapterID], p.RequiredChapterID)
+	}
+
+	seen := map[uint]bool{}
+	stack := []uint{requiredChapterID}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == chapterID {
+			return true
+		}
+		if !seen[current] {
+			seen[current] = true
+			stack = append(stack, requires[current]...)
+		}
+	}
+	return false
+}
+
+// chapterLock - Evaluate the chapter's prerequisites for the authenticated user
+func (h *Handler) chapterLock(c *gin.Context, chapterID uint) (ChapterLock, error) {
+	return h.learnerChapterLock(middleware.AuthUserID(c), middleware.AuthRole(c), chapterID)
+}
+
+// learnerChapterLock - Evaluate the chapter's prerequisites for a user with the given role.
+// Roles allowed to open locked content always see chapters unlocked.
+func (h *Handler) learnerChapterLock(userID, role string, chapterID uint) (ChapterLock, error) {
+	if auth.HasPermission(role, auth.PermLockedContent) {
+		return ChapterLock{}, nil
+	}
+
+	prerequisites, err := h.Chapters.ListPrerequisites(chapterID)
+	if err != nil {
+		return ChapterLock{}, err
+	}
+	return h.evaluatePrerequisites(userID, prerequisites)
+}
+
+// chapterLocks - Evaluate every chapter's prerequisites at once (for chapter listings)
+func (h *Handler) chapterLocks(c *gin.Context) (map[uint]ChapterLock, error) {
+	locks := map[uint]ChapterLock{}
+	if auth.HasPermission(middleware.AuthRole(c), auth.PermLockedContent) {
+		return locks, nil
+	}
+
+	prerequisites, err := h.Chapters.ListPrerequisites(0)
+	if err != nil {
+		return nil, err
+	}
+
+	byChapter := map[uint][]models.ChapterPrerequisite{}
+	for _, p := range prerequisites {
+		byChapter[p.ChapterID] = append(byChapter[p.ChapterID], p)
+	}
+
+	userID := middleware.AuthUserID(c)
+	for chapterID, rules := range byChapter {
+		lock, err := h.evaluatePrerequisites(userID, rules)
+		if err != nil {
+			return nil, err
+		}
+		locks[chapterID] = lock
+	}
+	return locks, nil
+}
+
+// evaluatePrerequisites - Check each rule against the user's chapter statuses and quiz answers
+func (h *Handler) evaluatePrerequisites(userID string, prerequisites []models.ChapterPrerequisite) (ChapterLock, error) {
+	var lock ChapterLock
+	for _, p := range prerequisites {
+		switch p.Type {
+		case models.PrerequisiteCompleted:
+			// The required chapter counts once it is passed under its own completion rules
+			required, err := h.Chapters.GetChapter(p.RequiredChapterID)
+			if err == store.ErrNotFound {
+				continue
+			} else if err != nil {
+				return ChapterLock{}, err
+			}
+			activity, err := h.learnerActivityFor(userID, required.ID)
+			if err != nil {
+				return ChapterLock{}, err
+			}
+			passed := chapterStatus(required, activity).Status == models.ChapterPassed
+			if reason := unmetPrerequisite(p, passed, 0, 0); reason != "" {
+				lock.LockedReasons = append(lock.LockedReasons, reason)
+			}
+
+		default: // models.PrerequisiteQuizPassed
+			// Scored like the chapter status, so pooled quizzes count their finished attempts
+			attempts, err := h.Quiz.ListAttempts(userID, p.RequiredChapterID)
+			if err != nil {
+				return ChapterLock{}, err
+			}
+			quiz, err := h.quizResultFor(userID, p.RequiredChapterID, attempts)
+			if err != nil {
+				return ChapterLock{}, err
+			}
+			if reason := unmetPrerequisite(p, false, quiz.score, quiz.questions); reason != "" {
+				lock.LockedReasons = append(lock.LockedReasons, reason)
+			}
+		}
+	}
+	lock.Locked = len(lock.LockedReasons) > 0
+	return lock, nil
+}
+
+// unmetPrerequisite - Why a rule still locks its chapter ("" once it is met), given whether the
+// required chapter is passed and its quiz score (see scoreQuiz)
+func unmetPrerequisite(p models.ChapterPrerequisite, passed bool, quizPercent float64, quizQuestions int) string {
+	switch p.Type {
+	case models.PrerequisiteCompleted:
+		if !passed {
+			return fmt.Sprintf("Complete %q first", p.RequiredChapterTitle)
+		}
+	default: // models.PrerequisiteQuizPassed
+		if quizQuestions > 0 && quizPercent < float64(p.MinScorePercent) {
+			return fmt.Sprintf("Score at least %d%% on the %q quiz (current: %.0f%%)",
+				p.MinScorePercent, p.RequiredChapterTitle, quizPercent)
+		}
+	}
+	return ""
+}
+
+// requireUnlocked - Respond 403 with the reasons and return false if the chapter is locked
+func (h *Handler) requireUnlocked(c *gin.Context, chapterID uint) bool {
+	if err := h.checkUnlocked(middleware.AuthUserID(c), middleware.AuthRole(c), chapterID); err != nil {
+		respondError(c, err)
+		return false
+	}
+	return true
+}
+
+// checkUnlocked - Refuse with 403 and the reasons if the chapter is locked for the user
+func (h *Handler) checkUnlocked(userID, role string, chapterID uint) error {
+	lock, err := h.learnerChapterLock(userID, role, chapterID)
+	if err != nil {
+		return refuse(http.StatusInternalServerError, "Failed to evaluate prerequisites")
+	}
+	if lock.Locked {
+		return refuse(http.StatusForbidden, "Chapter is locked").with(gin.H{"locked_reasons": lock.LockedReasons})
+	}
+	return nil
+}
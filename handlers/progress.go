@@ -1,8 +1,9 @@
 package handlers
 
 import (
-	"database/sql"
-	"learning-app-backend/database"
+	"learning-app-backend/models"
+	"learning-app-backend/realtime"
+	"learning-app-backend/store"
 	"net/http"
 	"time"
 
@@ -18,19 +19,6 @@ type SaveProgressRequest struct {
 	IsCompleted       bool   `json:"is_completed"`
 }
 
-type Progress struct {
-	ID                uint       `json:"id"`
-	UserID            string     `json:"user_id"`
-	ChapterID         uint       `json:"chapter_id"`
-	ContentType       string     `json:"content_type"`
-	VideoTimestamp    *int       `json:"video_timestamp,omitempty"`
-	QuizQuestionIndex *int       `json:"quiz_question_index,omitempty"`
-	IsCompleted       bool       `json:"is_completed"`
-	LastUpdated       time.Time  `json:"last_updated"`
-	CreatedAt         time.Time  `json:"created_at"`
-	UpdatedAt         time.Time  `json:"updated_at"`
-}
-
 type UserProgressSummary struct {
 	HasProgress      bool       `json:"has_progress"`
 	LastChapterID    *uint      `json:"last_chapter_id,omitempty"`
@@ -42,7 +30,7 @@ type UserProgressSummary struct {
 }
 
 // SaveProgressRaw - Save or update progress using raw SQL
-func SaveProgress(c *gin.Context) {
+func (h *Handler) SaveProgress(c *gin.Context) {
 	var req SaveProgressRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -53,163 +41,152 @@ func SaveProgress(c *gin.Context) {
 		return
 	}
 
+	if !requireSameUser(c, req.UserID) {
+		return
+	}
+
+	result, err := h.saveProgress(req, nil)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respondResult(c, "Progress saved successfully", result)
+}
+
+// saveProgress - Validate and save a progress row, returning it with the chapter status. Offline
+// events (see SyncEvents) are merged into the saved row instead of replacing it
+func (h *Handler) saveProgress(req SaveProgressRequest, offlineAt *time.Time) (gin.H, error) {
 	// Validate content type
 	if req.ContentType != "video" && req.ContentType != "quiz" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Invalid content type. Must be 'video' or 'quiz'",
-		})
-		return
+		return nil, refuse(http.StatusBadRequest, "Invalid content type. Must be 'video' or 'quiz'")
 	}
 
 	// Validate required fields
 	if req.ContentType == "video" && req.VideoTimestamp == nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "video_timestamp is required for video content type",
-		})
-		return
+		return nil, refuse(http.StatusBadRequest, "video_timestamp is required for video content type")
 	}
 
 	if req.ContentType == "quiz" && req.QuizQuestionIndex == nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "quiz_question_index is required for quiz content type",
-		})
-		return
+		return nil, refuse(http.StatusBadRequest, "quiz_question_index is required for quiz content type")
 	}
 
-	sqlDB, _ := database.DB.DB()
-
 	// Check if user exists
-	var userExists bool
-	userQuery := `SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1 AND deleted_at IS NULL)`
-	err := sqlDB.QueryRow(userQuery, req.UserID).Scan(&userExists)
+	userExists, err := h.Users.UserExists(req.UserID)
 	if err != nil || !userExists {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"message": "User not found",
-		})
-		return
+		return nil, refuse(http.StatusNotFound, "User not found")
 	}
 
 	// Check if chapter exists
-	var chapterExists bool
-	chapterQuery := `SELECT EXISTS(SELECT 1 FROM chapters WHERE id = $1 AND deleted_at IS NULL)`
-	err = sqlDB.QueryRow(chapterQuery, req.ChapterID).Scan(&chapterExists)
-	if err != nil || !chapterExists {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"message": "Chapter not found",
-		})
-		return
+	chapter, err := h.Chapters.GetChapter(req.ChapterID)
+	if err != nil {
+		return nil, refuse(http.StatusNotFound, "Chapter not found")
 	}
 
-	// Check if progress exists
-	var progressID sql.NullInt64
-	checkQuery := `SELECT id FROM progresses
-				   WHERE user_id = $1 AND chapter_id = $2 AND content_type = $3 AND deleted_at IS NULL`
-	err = sqlDB.QueryRow(checkQuery, req.UserID, req.ChapterID, req.ContentType).Scan(&progressID)
-
-	var progress Progress
-
-	if err == sql.ErrNoRows {
-		// Create new progress
-		insertQuery := `INSERT INTO progresses (user_id, chapter_id, content_type, video_timestamp,
-				   quiz_question_index, is_completed, last_updated, created_at, updated_at)
-				   VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW(), NOW())
-				   RETURNING id, user_id, chapter_id, content_type, video_timestamp,
-				   quiz_question_index, is_completed, last_updated, created_at, updated_at`
-
-		err = sqlDB.QueryRow(insertQuery, req.UserID, req.ChapterID, req.ContentType,
-			req.VideoTimestamp, req.QuizQuestionIndex, req.IsCompleted).Scan(
-			&progress.ID, &progress.UserID, &progress.ChapterID, &progress.ContentType,
-			&progress.VideoTimestamp, &progress.QuizQuestionIndex, &progress.IsCompleted,
-			&progress.LastUpdated, &progress.CreatedAt, &progress.UpdatedAt,
-		)
-
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"message": "Failed to save progress",
-			})
-			return
-		}
-	} else if err == nil {
-		// Update existing progress
-		updateQuery := `UPDATE progresses SET video_timestamp = $1, quiz_question_index = $2,
-						is_completed = $3, last_updated = NOW(), updated_at = NOW()
-						WHERE id = $4
-						RETURNING id, user_id, chapter_id, content_type, video_timestamp,
-						quiz_question_index, is_completed, last_updated, created_at, updated_at`
-
-		err = sqlDB.QueryRow(updateQuery, req.VideoTimestamp, req.QuizQuestionIndex,
-			req.IsCompleted, progressID.Int64).Scan(
-			&progress.ID, &progress.UserID, &progress.ChapterID, &progress.ContentType,
-			&progress.VideoTimestamp, &progress.QuizQuestionIndex, &progress.IsCompleted,
-			&progress.LastUpdated, &progress.CreatedAt, &progress.UpdatedAt,
-		)
-
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"message": "Failed to update progress",
-			})
-			return
+	progress := models.Progress{
+		UserID:            req.UserID,
+		ChapterID:         req.ChapterID,
+		ContentType:       req.ContentType,
+		VideoTimestamp:    req.VideoTimestamp,
+		QuizQuestionIndex: req.QuizQuestionIndex,
+	}
+	if offlineAt != nil {
+		if err := h.mergeOfflineProgress(&progress, *offlineAt); err != nil {
+			return nil, refuse(http.StatusInternalServerError, "Failed to fetch progress")
 		}
-	} else {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Database error",
-		})
-		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"message":  "Progress saved successfully",
-		"progress": progress,
-	})
+	// Completion follows the chapter's rules rather than the client's say-so
+	progress.IsCompleted, err = h.progressCompleted(chapter, &progress, req.IsCompleted)
+	if err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to evaluate chapter status")
+	}
+
+	if err := h.Progress.SaveProgress(&progress, progressEvent(models.ProgressSourceSave, offlineAt)); err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to save progress")
+	}
+
+	status, err := h.refreshChapterStatus(req.UserID, chapter)
+	if err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to update chapter status")
+	}
+
+	if err := h.recordActivity(req.UserID, chapter.ID, offlineAt, 0); err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to record learning time")
+	}
+
+	result := gin.H{
+		"progress":       progress,
+		"chapter_status": status,
+	}
+	h.publish(req.UserID, realtime.EventProgress, result)
+	return result, nil
+}
+
+// progressEvent - The log entry for a progress write. Offline writes are logged as sync events
+// at the time they happened
+func progressEvent(source string, offlineAt *time.Time) *models.ProgressEvent {
+	if offlineAt != nil {
+		return &models.ProgressEvent{Source: models.ProgressSourceSync, OccurredAt: *offlineAt}
+	}
+	return &models.ProgressEvent{Source: source}
 }
 
 // GetUserProgressRaw - Get latest progress for a user
-func GetUserProgress(c *gin.Context) {
-	userID := c.Param("userId")
-	sqlDB, _ := database.DB.DB()
+func (h *Handler) GetUserProgress(c *gin.Context) {
+	h.respondLatestProgress(c, c.Param("userId"), 0)
+}
 
-	query := `SELECT p.id, p.user_id, p.chapter_id, p.content_type, p.video_timestamp,
-			  p.quiz_question_index, p.is_completed, p.last_updated, ch.title
-			  FROM progresses p
-			  JOIN chapters ch ON p.chapter_id = ch.id
-			  WHERE p.user_id = $1 AND p.deleted_at IS NULL
-			  ORDER BY p.last_updated DESC LIMIT 1`
+// GetCourseProgress - Get latest progress for a user within one course
+func (h *Handler) GetCourseProgress(c *gin.Context) {
+	courseID, ok := parseID(c, "courseId", "course ID")
+	if !ok {
+		return
+	}
 
-	var progress Progress
-	var chapterTitle string
+	if !h.courseExists(c, courseID) {
+		return
+	}
 
-	err := sqlDB.QueryRow(query, userID).Scan(
-		&progress.ID, &progress.UserID, &progress.ChapterID, &progress.ContentType,
-		&progress.VideoTimestamp, &progress.QuizQuestionIndex, &progress.IsCompleted,
-		&progress.LastUpdated, &chapterTitle,
-	)
+	h.respondLatestProgress(c, c.Param("userId"), courseID)
+}
 
-	if err == sql.ErrNoRows {
+// respondLatestProgress - Write the resume summary for the user's latest progress (courseID 0 = any course)
+func (h *Handler) respondLatestProgress(c *gin.Context, userID string, courseID uint) {
+	summary, err := h.latestProgressSummary(userID, courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+	if !summary.HasProgress {
 		c.JSON(http.StatusOK, gin.H{
 			"success":      true,
 			"has_progress": false,
 			"message":      "No progress found for this user",
 		})
 		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"progress": summary,
+	})
+}
+
+// latestProgressSummary - The learner's resume point: their most recently updated progress row,
+// optionally within one course (courseID 0 = any)
+func (h *Handler) latestProgressSummary(userID string, courseID uint) (UserProgressSummary, error) {
+	progress, chapterTitle, err := h.Progress.LatestProgress(userID, courseID)
+	if err == store.ErrNotFound {
+		return UserProgressSummary{}, nil
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Database error",
-		})
-		return
+		return UserProgressSummary{}, err
 	}
 
 	contentType := progress.ContentType
-	summary := UserProgressSummary{
+	return UserProgressSummary{
 		HasProgress:      true,
 		LastChapterID:    &progress.ChapterID,
 		LastContentType:  &contentType,
@@ -217,27 +194,18 @@ func GetUserProgress(c *gin.Context) {
 		LastQuizQuestion: progress.QuizQuestionIndex,
 		ChapterTitle:     &chapterTitle,
 		LastUpdated:      &progress.LastUpdated,
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"progress": summary,
-	})
+	}, nil
 }
 
 // GetChapterProgressRaw - Get progress for a specific chapter
-func GetChapterProgress(c *gin.Context) {
+func (h *Handler) GetChapterProgress(c *gin.Context) {
 	userID := c.Param("userId")
-	chapterID := c.Param("chapterId")
-	sqlDB, _ := database.DB.DB()
-
-	query := `SELECT id, user_id, chapter_id, content_type, video_timestamp,
-			  quiz_question_index, is_completed, last_updated, created_at, updated_at
-			  FROM progresses
-			  WHERE user_id = $1 AND chapter_id = $2 AND deleted_at IS NULL
-			  ORDER BY last_updated DESC`
+	chapterID, ok := parseID(c, "chapterId", "chapter ID")
+	if !ok {
+		return
+	}
 
-	rows, err := sqlDB.Query(query, userID, chapterID)
+	progressRecords, err := h.Progress.ListChapterProgress(userID, chapterID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -245,18 +213,6 @@ func GetChapterProgress(c *gin.Context) {
 		})
 		return
 	}
-	defer rows.Close()
-
-	var progressRecords []Progress
-	for rows.Next() {
-		var p Progress
-		err := rows.Scan(&p.ID, &p.UserID, &p.ChapterID, &p.ContentType,
-			&p.VideoTimestamp, &p.QuizQuestionIndex, &p.IsCompleted,
-			&p.LastUpdated, &p.CreatedAt, &p.UpdatedAt)
-		if err == nil {
-			progressRecords = append(progressRecords, p)
-		}
-	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success":  true,
@@ -265,17 +221,10 @@ func GetChapterProgress(c *gin.Context) {
 }
 
 // GetAllUserProgressRaw - Get all progress for a user
-func GetAllUserProgress(c *gin.Context) {
+func (h *Handler) GetAllUserProgress(c *gin.Context) {
 	userID := c.Param("userId")
-	sqlDB, _ := database.DB.DB()
 
-	query := `SELECT id, user_id, chapter_id, content_type, video_timestamp,
-			  quiz_question_index, is_completed, last_updated, created_at, updated_at
-			  FROM progresses
-			  WHERE user_id = $1 AND deleted_at IS NULL
-			  ORDER BY chapter_id ASC, content_type ASC`
-
-	rows, err := sqlDB.Query(query, userID)
+	progressRecords, err := h.Progress.ListUserProgress(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -283,34 +232,34 @@ func GetAllUserProgress(c *gin.Context) {
 		})
 		return
 	}
-	defer rows.Close()
-
-	var progressRecords []Progress
-	for rows.Next() {
-		var p Progress
-		err := rows.Scan(&p.ID, &p.UserID, &p.ChapterID, &p.ContentType,
-			&p.VideoTimestamp, &p.QuizQuestionIndex, &p.IsCompleted,
-			&p.LastUpdated, &p.CreatedAt, &p.UpdatedAt)
-		if err == nil {
-			progressRecords = append(progressRecords, p)
-		}
+
+	// Time on task per chapter, across all of the learner's sessions
+	sessions, err := h.Progress.ListLearningSessions(userID, time.Time{}, time.Time{})
+	var activeTime []models.ChapterActiveTime
+	if err == nil {
+		activeTime, err = h.chapterActiveTime(sessions)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch learning time",
+		})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"progress": progressRecords,
+		"success":     true,
+		"progress":    progressRecords,
+		"active_time": activeTime,
 	})
 }
 
 // ResetProgressRaw - Reset all progress for a user
-func ResetProgress(c *gin.Context) {
+func (h *Handler) ResetProgress(c *gin.Context) {
 	userID := c.Param("userId")
-	sqlDB, _ := database.DB.DB()
 
 	// Soft delete
-	query := `UPDATE progresses SET deleted_at = NOW() WHERE user_id = $1 AND deleted_at IS NULL`
-
-	result, err := sqlDB.Exec(query, userID)
+	rowsAffected, err := h.Progress.ResetProgress(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -319,8 +268,6 @@ func ResetProgress(c *gin.Context) {
 		return
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Progress reset successfully",
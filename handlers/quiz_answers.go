@@ -1,54 +1,30 @@
 package handlers
 
 import (
-	"database/sql"
-	"learning-app-backend/database"
+	"encoding/json"
+	"learning-app-backend/middleware"
+	"learning-app-backend/models"
+	"learning-app-backend/realtime"
+	"learning-app-backend/store"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// SubmitQuizAnswerRequest - user_answer's shape depends on the question type (see models.QuizQuestion.NormalizeAnswer);
+// without attempt_id the answer goes into the chapter's in-progress attempt, which is started if needed
 type SubmitQuizAnswerRequest struct {
-	UserID         string `json:"user_id" binding:"required"`
-	ChapterID      uint   `json:"chapter_id" binding:"required"`
-	QuizQuestionID uint   `json:"quiz_question_id" binding:"required"`
-	UserAnswer     string `json:"user_answer" binding:"required"`
-}
-
-type QuizAnswer struct {
-	ID             uint      `json:"id"`
-	UserID         string    `json:"user_id"`
-	ChapterID      uint      `json:"chapter_id"`
-	QuizQuestionID uint      `json:"quiz_question_id"`
-	UserAnswer     string    `json:"user_answer"`
-	IsCorrect      bool      `json:"is_correct"`
-	AnsweredAt     time.Time `json:"answered_at"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-}
-
-type QuizAnswerWithDetails struct {
-	QuizAnswer
-	QuestionText  string `json:"question_text"`
-	CorrectAnswer string `json:"correct_answer"`
-	OptionA       string `json:"option_a"`
-	OptionB       string `json:"option_b"`
-	OptionC       string `json:"option_c"`
-	OptionD       string `json:"option_d"`
-}
-
-type QuizHistorySummary struct {
-	ChapterID     uint   `json:"chapter_id"`
-	ChapterTitle  string `json:"chapter_title"`
-	TotalAnswered int    `json:"total_answered"`
-	TotalCorrect  int    `json:"total_correct"`
-	TotalWrong    int    `json:"total_wrong"`
-	Percentage    float64 `json:"percentage"`
+	UserID         string          `json:"user_id" binding:"required"`
+	ChapterID      uint            `json:"chapter_id" binding:"required"`
+	QuizQuestionID uint            `json:"quiz_question_id" binding:"required"`
+	AttemptID      uint            `json:"attempt_id"`
+	UserAnswer     json.RawMessage `json:"user_answer" binding:"required"`
 }
 
 // SubmitQuizAnswerRaw - Submit a quiz answer and save to history
-func SubmitQuizAnswer(c *gin.Context) {
+func (h *Handler) SubmitQuizAnswer(c *gin.Context) {
 	var req SubmitQuizAnswerRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -59,87 +35,194 @@ func SubmitQuizAnswer(c *gin.Context) {
 		return
 	}
 
-	// Validate answer
-	if req.UserAnswer != "A" && req.UserAnswer != "B" && req.UserAnswer != "C" && req.UserAnswer != "D" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Invalid answer. Must be A, B, C, or D",
-		})
+	if !requireSameUser(c, req.UserID) {
 		return
 	}
 
-	sqlDB, _ := database.DB.DB()
+	result, err := h.submitQuizAnswer(req, middleware.AuthRole(c), nil)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respondResult(c, "Answer submitted successfully", result)
+}
 
-	// Get the correct answer from the question
-	var correctAnswer string
-	questionQuery := `SELECT correct_answer FROM quiz_questions
-					  WHERE id = $1 AND chapter_id = $2 AND deleted_at IS NULL`
+// submitQuizAnswer - Grade and record an answer from a user with the given role, returning the
+// outcome. Offline answers (see SyncEvents) are filed at the time they were given and settled
+// against the attempt's answers by settleOfflineAnswer, but limits, cooldowns and deadlines are
+// checked when they arrive
+func (h *Handler) submitQuizAnswer(req SubmitQuizAnswerRequest, role string, offlineAt *time.Time) (gin.H, error) {
+	// Get the question to grade against
+	question, err := h.Chapters.GetQuizQuestion(req.QuizQuestionID)
+	if err == store.ErrNotFound || (err == nil && question.ChapterID != req.ChapterID) {
+		return nil, refuse(http.StatusNotFound, "Quiz question not found")
+	} else if err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Database error")
+	}
 
-	err := sqlDB.QueryRow(questionQuery, req.QuizQuestionID, req.ChapterID).Scan(&correctAnswer)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"message": "Quiz question not found",
-		})
-		return
+	chapter, err := h.Chapters.GetChapter(req.ChapterID)
+	if err == store.ErrNotFound {
+		return nil, refuse(http.StatusNotFound, "Chapter not found")
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Database error",
-		})
-		return
+		return nil, refuse(http.StatusInternalServerError, "Database error")
+	}
+
+	// On shuffled chapters the learner answers with the option keys they were shown
+	presentation, err := h.presentationFor(req.UserID, chapter)
+	if err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to fetch quiz attempts")
+	}
+	options := presentation.optionsFor(question.ID, question.QuestionType, question.ReviewOptions())
+	shown := options.question(*question)
+
+	// Validate the answer against the question type
+	shownAnswer, err := shown.NormalizeAnswer(req.UserAnswer)
+	if err != nil {
+		return nil, refuse(http.StatusBadRequest, "Invalid answer: "+err.Error())
+	}
+
+	// Answers only count once the chapter's prerequisites are met
+	if err := h.checkUnlocked(req.UserID, role, req.ChapterID); err != nil {
+		return nil, err
+	}
+
+	attempt, err := h.answerAttempt(req.UserID, chapter, req.AttemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attempt limits and cooldowns keep learners from guessing their way through the options
+	limits, err := h.answerLimitsFor(req.UserID, chapter)
+	if err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to fetch answer history")
+	}
+	if err := limits.checkAnswer(question.ID); err != nil {
+		return nil, err
+	}
+
+	// Pooled chapters only take answers to the questions drawn for the attempt
+	questions, drawn, err := h.quizQuestions(chapter.ID, presentation, attempt)
+	if err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to fetch quiz questions")
+	}
+	if drawn && !containsQuestion(questions, question.ID) {
+		return nil, refuse(http.StatusBadRequest, "Quiz question was not drawn for this attempt")
+	}
+
+	// Past the deadline of a timed quiz the answer is either refused or flagged late
+	isLate := false
+	if attemptExpired(attempt) {
+		if chapter.QuizLatePolicy != models.LatePolicyFlag {
+			if err := h.closeAttempt(attempt); err != nil {
+				return nil, refuse(http.StatusInternalServerError, "Failed to finish expired quiz attempt")
+			}
+			return nil, refuse(http.StatusConflict, "Time limit for this quiz attempt has expired").
+				with(gin.H{"attempt": attempt})
+		}
+		isLate = true
 	}
 
 	// Check if answer is correct
-	isCorrect := req.UserAnswer == correctAnswer
-
-	// Save the answer to history
-	var answer QuizAnswer
-	insertQuery := `INSERT INTO quiz_answers (user_id, chapter_id, quiz_question_id, user_answer,
-				   is_correct, answered_at, created_at, updated_at)
-				   VALUES ($1, $2, $3, $4, $5, NOW(), NOW(), NOW())
-				   RETURNING id, user_id, chapter_id, quiz_question_id, user_answer,
-				   is_correct, answered_at, created_at, updated_at`
-
-	err = sqlDB.QueryRow(insertQuery, req.UserID, req.ChapterID, req.QuizQuestionID,
-		req.UserAnswer, isCorrect).Scan(
-		&answer.ID, &answer.UserID, &answer.ChapterID, &answer.QuizQuestionID,
-		&answer.UserAnswer, &answer.IsCorrect, &answer.AnsweredAt,
-		&answer.CreatedAt, &answer.UpdatedAt,
-	)
+	isCorrect := shown.IsCorrect(shownAnswer)
+
+	// Save the answer to history, always in the stored option keys
+	answer := models.QuizAnswer{
+		UserID:         req.UserID,
+		ChapterID:      req.ChapterID,
+		QuizQuestionID: req.QuizQuestionID,
+		AttemptID:      &attempt.ID,
+		UserAnswer:     options.stored(*question, shownAnswer),
+		IsCorrect:      isCorrect,
+		IsLate:         isLate,
+	}
+	if offlineAt != nil {
+		// Offline answers cannot predate the attempt they are filed under
+		answer.AnsweredAt = *offlineAt
+		if answer.AnsweredAt.Before(attempt.StartedAt) {
+			answer.AnsweredAt = attempt.StartedAt
+		}
+		if err := h.settleOfflineAnswer(&answer); err != nil {
+			return nil, err
+		}
+	}
+	if err := h.Quiz.CreateAnswer(&answer); err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to save answer")
+	}
+
+	// The correct answer stays hidden while the learner can still try again
+	limits.record(question.ID, isCorrect, answer.CreatedAt)
+	revealed := limits.revealed(question.ID)
+
+	// Questions join the review queue once the learner knows their answer
+	if revealed {
+		if err := h.scheduleReview(answer); err != nil {
+			return nil, refuse(http.StatusInternalServerError, "Failed to schedule review")
+		}
+	}
 
+	status, err := h.refreshChapterStatus(req.UserID, chapter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to save answer",
-		})
-		return
+		return nil, refuse(http.StatusInternalServerError, "Failed to update chapter status")
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success":        true,
-		"message":        "Answer submitted successfully",
-		"is_correct":     isCorrect,
-		"correct_answer": correctAnswer,
-		"answer":         answer,
-	})
+	if err := h.recordActivity(req.UserID, req.ChapterID, offlineAt, 0); err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to record learning time")
+	}
+
+	// The response speaks in the keys the learner was shown
+	answer.UserAnswer = shownAnswer
+
+	result := gin.H{
+		"is_correct":         isCorrect,
+		"feedback":           shown.AnswerFeedback(shownAnswer),
+		"attempts_remaining": limits.remaining(question.ID),
+		"attempt_id":         attempt.ID,
+		"is_late":            isLate,
+		"answer":             answer,
+		"chapter_status":     status,
+	}
+	if revealed {
+		result["correct_answer"] = shown.CorrectAnswerText()
+		result["explanation"] = question.Explanation
+	}
+	h.publish(req.UserID, realtime.EventAnswer, result)
+	return result, nil
+}
+
+// settleOfflineAnswer - The earliest answer to a question within an attempt wins: an offline
+// answer given after one the attempt already holds is refused, and one given before replaces
+// the later answers. Replaced answers still count toward the attempt limit
+func (h *Handler) settleOfflineAnswer(answer *models.QuizAnswer) error {
+	recorded, err := h.Quiz.ListAnswers(store.AnswerFilter{UserID: answer.UserID,
+		QuestionID: answer.QuizQuestionID, AttemptID: *answer.AttemptID})
+	if err != nil {
+		return refuse(http.StatusInternalServerError, "Failed to fetch answer history")
+	}
+	for _, earlier := range recorded {
+		if !earlier.AnsweredAt.After(answer.AnsweredAt) {
+			return refuse(http.StatusConflict, "An earlier answer to this quiz question was already recorded")
+		}
+	}
+	if len(recorded) > 0 {
+		if _, err := h.Quiz.ClearAttemptAnswers(*answer.AttemptID, answer.QuizQuestionID); err != nil {
+			return refuse(http.StatusInternalServerError, "Failed to replace later answers")
+		}
+	}
+	return nil
 }
 
 // GetQuizHistoryRaw - Get all quiz answers for a user in a specific chapter
-func GetQuizHistory(c *gin.Context) {
+func (h *Handler) GetQuizHistory(c *gin.Context) {
 	userID := c.Param("userId")
-	chapterID := c.Param("chapterId")
-	sqlDB, _ := database.DB.DB()
-
-	query := `SELECT qa.id, qa.user_id, qa.chapter_id, qa.quiz_question_id, qa.user_answer,
-			  qa.is_correct, qa.answered_at, qa.created_at, qa.updated_at,
-			  qq.question_text, qq.correct_answer, qq.option_a, qq.option_b, qq.option_c, qq.option_d
-			  FROM quiz_answers qa
-			  JOIN quiz_questions qq ON qa.quiz_question_id = qq.id
-			  WHERE qa.user_id = $1 AND qa.chapter_id = $2 AND qa.deleted_at IS NULL
-			  ORDER BY qa.answered_at DESC`
-
-	rows, err := sqlDB.Query(query, userID, chapterID)
+	chapterID, ok := parseID(c, "chapterId", "chapter ID")
+	if !ok {
+		return
+	}
+
+	answers, err := h.Quiz.ListAnswers(store.AnswerFilter{UserID: userID, ChapterID: chapterID})
+	if err == nil {
+		err = h.withholdAnswers(userID, answers)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -147,18 +230,6 @@ func GetQuizHistory(c *gin.Context) {
 		})
 		return
 	}
-	defer rows.Close()
-
-	var answers []QuizAnswerWithDetails
-	for rows.Next() {
-		var a QuizAnswerWithDetails
-		err := rows.Scan(&a.ID, &a.UserID, &a.ChapterID, &a.QuizQuestionID, &a.UserAnswer,
-			&a.IsCorrect, &a.AnsweredAt, &a.CreatedAt, &a.UpdatedAt,
-			&a.QuestionText, &a.CorrectAnswer, &a.OptionA, &a.OptionB, &a.OptionC, &a.OptionD)
-		if err == nil {
-			answers = append(answers, a)
-		}
-	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -167,19 +238,13 @@ func GetQuizHistory(c *gin.Context) {
 }
 
 // GetAllQuizHistoryRaw - Get all quiz answers for a user across all chapters
-func GetAllQuizHistory(c *gin.Context) {
+func (h *Handler) GetAllQuizHistory(c *gin.Context) {
 	userID := c.Param("userId")
-	sqlDB, _ := database.DB.DB()
 
-	query := `SELECT qa.id, qa.user_id, qa.chapter_id, qa.quiz_question_id, qa.user_answer,
-			  qa.is_correct, qa.answered_at, qa.created_at, qa.updated_at,
-			  qq.question_text, qq.correct_answer, qq.option_a, qq.option_b, qq.option_c, qq.option_d
-			  FROM quiz_answers qa
-			  JOIN quiz_questions qq ON qa.quiz_question_id = qq.id
-			  WHERE qa.user_id = $1 AND qa.deleted_at IS NULL
-			  ORDER BY qa.chapter_id ASC, qa.answered_at DESC`
-
-	rows, err := sqlDB.Query(query, userID)
+	answers, err := h.Quiz.ListAnswers(store.AnswerFilter{UserID: userID})
+	if err == nil {
+		err = h.withholdAnswers(userID, answers)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -187,18 +252,6 @@ func GetAllQuizHistory(c *gin.Context) {
 		})
 		return
 	}
-	defer rows.Close()
-
-	var answers []QuizAnswerWithDetails
-	for rows.Next() {
-		var a QuizAnswerWithDetails
-		err := rows.Scan(&a.ID, &a.UserID, &a.ChapterID, &a.QuizQuestionID, &a.UserAnswer,
-			&a.IsCorrect, &a.AnsweredAt, &a.CreatedAt, &a.UpdatedAt,
-			&a.QuestionText, &a.CorrectAnswer, &a.OptionA, &a.OptionB, &a.OptionC, &a.OptionD)
-		if err == nil {
-			answers = append(answers, a)
-		}
-	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -207,21 +260,27 @@ func GetAllQuizHistory(c *gin.Context) {
 }
 
 // GetQuizScoreRaw - Get quiz score summary for a user
-func GetQuizScore(c *gin.Context) {
-	userID := c.Param("userId")
-	sqlDB, _ := database.DB.DB()
-
-	query := `SELECT qa.chapter_id, ch.title,
-			  COUNT(*) as total_answered,
-			  SUM(CASE WHEN qa.is_correct = true THEN 1 ELSE 0 END) as total_correct,
-			  SUM(CASE WHEN qa.is_correct = false THEN 1 ELSE 0 END) as total_wrong
-			  FROM quiz_answers qa
-			  JOIN chapters ch ON qa.chapter_id = ch.id
-			  WHERE qa.user_id = $1 AND qa.deleted_at IS NULL
-			  GROUP BY qa.chapter_id, ch.title
-			  ORDER BY qa.chapter_id ASC`
-
-	rows, err := sqlDB.Query(query, userID)
+func (h *Handler) GetQuizScore(c *gin.Context) {
+	h.respondQuizScores(c, c.Param("userId"), 0)
+}
+
+// GetCourseQuizScore - Get quiz score summary for a user within one course
+func (h *Handler) GetCourseQuizScore(c *gin.Context) {
+	courseID, ok := parseID(c, "courseId", "course ID")
+	if !ok {
+		return
+	}
+
+	if !h.courseExists(c, courseID) {
+		return
+	}
+
+	h.respondQuizScores(c, c.Param("userId"), courseID)
+}
+
+// respondQuizScores - Write per-chapter quiz scores (courseID 0 = every course)
+func (h *Handler) respondQuizScores(c *gin.Context, userID string, courseID uint) {
+	summaries, err := h.Quiz.ChapterScores(userID, courseID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -229,19 +288,6 @@ func GetQuizScore(c *gin.Context) {
 		})
 		return
 	}
-	defer rows.Close()
-
-	var summaries []QuizHistorySummary
-	for rows.Next() {
-		var s QuizHistorySummary
-		err := rows.Scan(&s.ChapterID, &s.ChapterTitle, &s.TotalAnswered, &s.TotalCorrect, &s.TotalWrong)
-		if err == nil {
-			if s.TotalAnswered > 0 {
-				s.Percentage = (float64(s.TotalCorrect) / float64(s.TotalAnswered)) * 100
-			}
-			summaries = append(summaries, s)
-		}
-	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -250,20 +296,17 @@ func GetQuizScore(c *gin.Context) {
 }
 
 // GetQuestionAnswerHistoryRaw - Get all attempts for a specific question by a user
-func GetQuestionAnswerHistory(c *gin.Context) {
+func (h *Handler) GetQuestionAnswerHistory(c *gin.Context) {
 	userID := c.Param("userId")
-	questionID := c.Param("questionId")
-	sqlDB, _ := database.DB.DB()
-
-	query := `SELECT qa.id, qa.user_id, qa.chapter_id, qa.quiz_question_id, qa.user_answer,
-			  qa.is_correct, qa.answered_at, qa.created_at, qa.updated_at,
-			  qq.question_text, qq.correct_answer, qq.option_a, qq.option_b, qq.option_c, qq.option_d
-			  FROM quiz_answers qa
-			  JOIN quiz_questions qq ON qa.quiz_question_id = qq.id
-			  WHERE qa.user_id = $1 AND qa.quiz_question_id = $2 AND qa.deleted_at IS NULL
-			  ORDER BY qa.answered_at DESC`
-
-	rows, err := sqlDB.Query(query, userID, questionID)
+	questionID, ok := parseID(c, "questionId", "question ID")
+	if !ok {
+		return
+	}
+
+	answers, err := h.Quiz.ListAnswers(store.AnswerFilter{UserID: userID, QuestionID: questionID})
+	if err == nil {
+		err = h.withholdAnswers(userID, answers)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -271,18 +314,6 @@ func GetQuestionAnswerHistory(c *gin.Context) {
 		})
 		return
 	}
-	defer rows.Close()
-
-	var answers []QuizAnswerWithDetails
-	for rows.Next() {
-		var a QuizAnswerWithDetails
-		err := rows.Scan(&a.ID, &a.UserID, &a.ChapterID, &a.QuizQuestionID, &a.UserAnswer,
-			&a.IsCorrect, &a.AnsweredAt, &a.CreatedAt, &a.UpdatedAt,
-			&a.QuestionText, &a.CorrectAnswer, &a.OptionA, &a.OptionB, &a.OptionC, &a.OptionD)
-		if err == nil {
-			answers = append(answers, a)
-		}
-	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -291,27 +322,24 @@ func GetQuestionAnswerHistory(c *gin.Context) {
 }
 
 // ClearQuizHistoryRaw - Clear quiz history for a user (optional)
-func ClearQuizHistory(c *gin.Context) {
+func (h *Handler) ClearQuizHistory(c *gin.Context) {
 	userID := c.Param("userId")
-	chapterID := c.Query("chapter_id") // Optional: clear only for specific chapter
-	sqlDB, _ := database.DB.DB()
 
-	var query string
-	var result sql.Result
-	var err error
-
-	if chapterID != "" {
-		// Clear for specific chapter
-		query = `UPDATE quiz_answers SET deleted_at = NOW()
-				 WHERE user_id = $1 AND chapter_id = $2 AND deleted_at IS NULL`
-		result, err = sqlDB.Exec(query, userID, chapterID)
-	} else {
-		// Clear all history
-		query = `UPDATE quiz_answers SET deleted_at = NOW()
-				 WHERE user_id = $1 AND deleted_at IS NULL`
-		result, err = sqlDB.Exec(query, userID)
+	// Optional: clear only for specific chapter
+	var chapterID uint
+	if raw := c.Query("chapter_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid chapter ID",
+			})
+			return
+		}
+		chapterID = uint(id)
 	}
 
+	rowsAffected, err := h.Quiz.ClearAnswers(userID, chapterID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -320,8 +348,6 @@ func ClearQuizHistory(c *gin.Context) {
 		return
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Quiz history cleared successfully",
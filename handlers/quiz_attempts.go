@@ -0,0 +1,445 @@
+package handlers
+
+import (
+	"learning-app-backend/models"
+	"learning-app-backend/store"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimedQuizAttempt - An attempt plus how long is left before its deadline (timed quizzes only)
+type TimedQuizAttempt struct {
+	models.QuizAttempt
+	TimeRemainingSeconds *int `json:"time_remaining_seconds,omitempty"`
+	TimeExpired          bool `json:"time_expired"`
+}
+
+// StartQuizAttempt - Start a quiz attempt for a chapter, or resume the one in progress
+func (h *Handler) StartQuizAttempt(c *gin.Context) {
+	userID := c.Param("userId")
+	chapterID, ok := parseID(c, "chapterId", "chapter ID")
+	if !ok {
+		return
+	}
+
+	chapter, err := h.Chapters.GetChapter(chapterID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Chapter not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+
+	if !h.requireUnlocked(c, chapterID) {
+		return
+	}
+
+	attempt, created, err := h.startOrResumeAttempt(userID, chapter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// An attempt that ran out of time cannot be resumed when late answers are rejected
+	if !created && attemptExpired(attempt) && chapter.QuizLatePolicy != models.LatePolicyFlag {
+		if err := h.closeAttempt(attempt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Failed to finish expired quiz attempt",
+			})
+			return
+		}
+		if attempt, created, err = h.startOrResumeAttempt(userID, chapter); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	if !created {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Resuming quiz attempt in progress",
+			"attempt": timedAttempt(attempt),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Quiz attempt started",
+		"attempt": timedAttempt(attempt),
+	})
+}
+
+// FinishQuizAttempt - Score an attempt and close it; unanswered questions count as wrong
+func (h *Handler) FinishQuizAttempt(c *gin.Context) {
+	userID := c.Param("userId")
+	attemptID, ok := parseID(c, "attemptId", "attempt ID")
+	if !ok {
+		return
+	}
+
+	attempt, ok := h.userAttempt(c, userID, attemptID)
+	if !ok {
+		return
+	}
+
+	if attempt.Status != models.AttemptInProgress {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Quiz attempt is already finished",
+			"attempt": attempt,
+		})
+		return
+	}
+
+	if err := h.scoreAttempt(attempt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to score quiz attempt",
+		})
+		return
+	}
+
+	err := h.Quiz.FinishAttempt(attempt)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Quiz attempt is already finished",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to finish quiz attempt",
+		})
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"message": "Quiz attempt finished",
+		"attempt": attempt,
+	}
+
+	// A passing attempt can complete the chapter; deleted chapters have no status to update
+	chapter, err := h.Chapters.GetChapter(attempt.ChapterID)
+	if err == nil {
+		var status models.ChapterStatus
+		status, err = h.refreshChapterStatus(userID, chapter)
+		response["chapter_status"] = status
+	} else if err == store.ErrNotFound {
+		err = nil
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update chapter status",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetQuizAttempts - List a user's attempts for a chapter with the best and latest finished ones
+func (h *Handler) GetQuizAttempts(c *gin.Context) {
+	userID := c.Param("userId")
+	chapterID, ok := parseID(c, "chapterId", "chapter ID")
+	if !ok {
+		return
+	}
+
+	attempts, err := h.Quiz.ListAttempts(userID, chapterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch quiz attempts",
+		})
+		return
+	}
+
+	// Attempts come newest first, so the first finished one is the latest
+	var best, latest, active *models.QuizAttempt
+	for i := range attempts {
+		attempt := &attempts[i]
+		if attempt.Status == models.AttemptInProgress {
+			if active == nil {
+				active = attempt
+			}
+			continue
+		}
+		if latest == nil {
+			latest = attempt
+		}
+		// >= keeps the earliest attempt that reached the best score
+		if best == nil || attempt.ScorePercent >= best.ScorePercent {
+			best = attempt
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"attempts":       attempts,
+		"best_attempt":   best,
+		"latest_attempt": latest,
+		"active_attempt": active,
+	})
+}
+
+// GetQuizAttempt - Get one attempt with every answer submitted into it
+func (h *Handler) GetQuizAttempt(c *gin.Context) {
+	userID := c.Param("userId")
+	attemptID, ok := parseID(c, "attemptId", "attempt ID")
+	if !ok {
+		return
+	}
+
+	attempt, ok := h.userAttempt(c, userID, attemptID)
+	if !ok {
+		return
+	}
+
+	answers, err := h.Quiz.ListAnswers(store.AnswerFilter{UserID: userID, AttemptID: attemptID})
+	if err == nil {
+		err = h.withholdAnswers(userID, answers)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch quiz history",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"attempt": models.QuizAttemptWithAnswers{QuizAttempt: *attempt, Answers: answers},
+	})
+}
+
+// GetQuizHistoryByAttempt - Get a user's answers for a chapter grouped by attempt, newest attempt first
+func (h *Handler) GetQuizHistoryByAttempt(c *gin.Context) {
+	userID := c.Param("userId")
+	chapterID, ok := parseID(c, "chapterId", "chapter ID")
+	if !ok {
+		return
+	}
+
+	attempts, err := h.Quiz.ListAttempts(userID, chapterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch quiz attempts",
+		})
+		return
+	}
+
+	answers, err := h.Quiz.ListAnswers(store.AnswerFilter{UserID: userID, ChapterID: chapterID})
+	if err == nil {
+		err = h.withholdAnswers(userID, answers)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch quiz history",
+		})
+		return
+	}
+
+	// Answers from before attempts existed are listed on their own
+	byAttempt := map[uint][]models.QuizAnswerWithDetails{}
+	var unattempted []models.QuizAnswerWithDetails
+	for _, answer := range answers {
+		if answer.AttemptID == nil {
+			unattempted = append(unattempted, answer)
+			continue
+		}
+		byAttempt[*answer.AttemptID] = append(byAttempt[*answer.AttemptID], answer)
+	}
+
+	grouped := make([]models.QuizAttemptWithAnswers, 0, len(attempts))
+	for _, attempt := range attempts {
+		grouped = append(grouped, models.QuizAttemptWithAnswers{QuizAttempt: attempt, Answers: byAttempt[attempt.ID]})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":             true,
+		"attempts":            grouped,
+		"unattempted_answers": unattempted,
+	})
+}
+
+// userAttempt - Load an attempt owned by userID; writes 404 otherwise
+func (h *Handler) userAttempt(c *gin.Context, userID string, attemptID uint) (*models.QuizAttempt, bool) {
+	attempt, err := h.findAttempt(userID, attemptID)
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	return attempt, true
+}
+
+// findAttempt - Load an attempt owned by userID; refuses with 404 otherwise
+func (h *Handler) findAttempt(userID string, attemptID uint) (*models.QuizAttempt, error) {
+	attempt, err := h.Quiz.GetAttempt(attemptID)
+	if err == store.ErrNotFound || (err == nil && attempt.UserID != userID) {
+		return nil, refuse(http.StatusNotFound, "Quiz attempt not found")
+	} else if err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Database error")
+	}
+	return attempt, nil
+}
+
+// startOrResumeAttempt - The user's in-progress attempt for the chapter, starting one when
+// there is none (created); the clock of a timed quiz starts here, on the server
+func (h *Handler) startOrResumeAttempt(userID string, chapter *models.Chapter) (attempt *models.QuizAttempt, created bool, err error) {
+	chapterID := chapter.ID
+	attempt, err = h.Quiz.ActiveAttempt(userID, chapterID)
+	if err == nil {
+		return attempt, false, nil
+	} else if err != store.ErrNotFound {
+		return nil, false, refuse(http.StatusInternalServerError, "Database error")
+	}
+
+	// Chapters with draw rules freeze the drawn questions for the whole attempt
+	presentation, err := h.presentationFor(userID, chapter)
+	if err != nil {
+		return nil, false, refuse(http.StatusInternalServerError, "Failed to fetch quiz attempts")
+	}
+	questions, drawn, err := h.quizQuestions(chapterID, presentation, nil)
+	if err != nil {
+		return nil, false, refuse(http.StatusInternalServerError, "Failed to fetch quiz questions")
+	}
+
+	if len(questions) == 0 {
+		return nil, false, refuse(http.StatusNotFound, "No quiz questions found for this chapter")
+	}
+
+	// Cleared attempts still count, so clearing the history never hands out fresh attempts
+	started, err := h.Quiz.StartedAttempts(userID, chapterID)
+	if err != nil {
+		return nil, false, refuse(http.StatusInternalServerError, "Failed to fetch quiz attempts")
+	}
+	if left := chapter.QuizAttemptsLeft(started[chapterID]); left != nil && *left == 0 {
+		return nil, false, refuse(http.StatusConflict, "No attempts left for this quiz").
+			with(gin.H{"max_quiz_attempts": chapter.MaxQuizAttempts})
+	}
+
+	attempt = &models.QuizAttempt{
+		UserID:         userID,
+		ChapterID:      chapterID,
+		Status:         models.AttemptInProgress,
+		TotalQuestions: len(questions),
+	}
+	if chapter.QuizTimeLimitSeconds > 0 {
+		deadline := time.Now().UTC().Add(time.Duration(chapter.QuizTimeLimitSeconds) * time.Second)
+		attempt.DeadlineAt = &deadline
+	}
+	var questionIDs []uint
+	if drawn {
+		for _, q := range questions {
+			questionIDs = append(questionIDs, q.ID)
+		}
+	}
+	if err := h.Quiz.CreateAttempt(attempt, questionIDs); err != nil {
+		return nil, false, refuse(http.StatusInternalServerError, "Failed to start quiz attempt")
+	}
+	return attempt, true, nil
+}
+
+// answerAttempt - The in-progress attempt an answer goes into: the requested one
+// (attemptID != 0) or the chapter's active attempt, started on demand
+func (h *Handler) answerAttempt(userID string, chapter *models.Chapter, attemptID uint) (*models.QuizAttempt, error) {
+	if attemptID == 0 {
+		attempt, _, err := h.startOrResumeAttempt(userID, chapter)
+		return attempt, err
+	}
+
+	attempt, err := h.findAttempt(userID, attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if attempt.ChapterID != chapter.ID {
+		return nil, refuse(http.StatusBadRequest, "Quiz attempt belongs to a different chapter")
+	}
+
+	if attempt.Status != models.AttemptInProgress {
+		return nil, refuse(http.StatusConflict, "Quiz attempt is already finished")
+	}
+	return attempt, nil
+}
+
+// scoreAttempt - Count the latest answer to each of the attempt's questions within the attempt
+func (h *Handler) scoreAttempt(attempt *models.QuizAttempt) error {
+	questions, _, err := h.quizQuestions(attempt.ChapterID, quizPresentation{}, attempt)
+	if err != nil {
+		return err
+	}
+
+	answers, err := h.Quiz.ListAnswers(store.AnswerFilter{UserID: attempt.UserID, AttemptID: attempt.ID})
+	if err != nil {
+		return err
+	}
+
+	// Answers come newest first
+	latestCorrect := map[uint]bool{}
+	for _, answer := range answers {
+		if _, seen := latestCorrect[answer.QuizQuestionID]; !seen {
+			latestCorrect[answer.QuizQuestionID] = answer.IsCorrect
+		}
+	}
+
+	attempt.TotalQuestions = len(questions)
+	attempt.CorrectAnswers = 0
+	for _, q := range questions {
+		if latestCorrect[q.ID] {
+			attempt.CorrectAnswers++
+		}
+	}
+	attempt.ScorePercent = 0
+	if attempt.TotalQuestions > 0 {
+		attempt.ScorePercent = float64(attempt.CorrectAnswers) / float64(attempt.TotalQuestions) * 100
+	}
+	return nil
+}
+
+// closeAttempt - Score and finish an attempt; one finished concurrently is left as is
+func (h *Handler) closeAttempt(attempt *models.QuizAttempt) error {
+	if err := h.scoreAttempt(attempt); err != nil {
+		return err
+	}
+	if err := h.Quiz.FinishAttempt(attempt); err != nil && err != store.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// attemptExpired - Whether a timed attempt is past its deadline
+func attemptExpired(attempt *models.QuizAttempt) bool {
+	return attempt.DeadlineAt != nil && time.Now().After(*attempt.DeadlineAt)
+}
+
+// timedAttempt - Attach the remaining time to an attempt
+func timedAttempt(attempt *models.QuizAttempt) TimedQuizAttempt {
+	timed := TimedQuizAttempt{QuizAttempt: *attempt, TimeExpired: attemptExpired(attempt)}
+	if attempt.DeadlineAt != nil {
+		remaining := int(math.Ceil(time.Until(*attempt.DeadlineAt).Seconds()))
+		if remaining < 0 {
+			remaining = 0
+		}
+		timed.TimeRemainingSeconds = &remaining
+	}
+	return timed
+}
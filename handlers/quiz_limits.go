@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"learning-app-backend/models"
+	"learning-app-backend/store"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// answerLimits - A learner's answer counts for one chapter's questions, checked against the
+// chapter's (or question's) attempt limit, its quiz attempt limit and its cooldown
+type answerLimits struct {
+	chapter      models.Chapter
+	questions    map[uint]models.QuizQuestion
+	active       *models.QuizAttempt // The quiz attempt in progress, nil between attempts
+	attemptsLeft *int                // Quiz attempts the learner may still start (nil = unlimited)
+	attempts     map[uint]int        // Answers within the active attempt
+	correct      map[uint]bool       // Answered correctly at least once
+	latest       map[uint]time.Time  // When the server last received an answer
+}
+
+// answerLimitsFor - Load the learner's answers to the chapter's questions. Each quiz attempt gets
+// the full answer limit; cleared attempts still count against the quiz attempt limit, so clearing
+// the quiz history never hands out fresh attempts
+func (h *Handler) answerLimitsFor(userID string, chapter *models.Chapter) (*answerLimits, error) {
+	questions, err := h.Chapters.ListQuizQuestions(chapter.ID)
+	if err != nil {
+		return nil, err
+	}
+	active, err := h.Quiz.ActiveAttempt(userID, chapter.ID)
+	if err == store.ErrNotFound {
+		active = nil
+	} else if err != nil {
+		return nil, err
+	}
+	started, err := h.Quiz.StartedAttempts(userID, chapter.ID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := h.Quiz.ListAnswers(store.AnswerFilter{UserID: userID, ChapterID: chapter.ID, IncludeCleared: true})
+	if err != nil {
+		return nil, err
+	}
+
+	limits := &answerLimits{
+		chapter:      *chapter,
+		questions:    map[uint]models.QuizQuestion{},
+		active:       active,
+		attemptsLeft: chapter.QuizAttemptsLeft(started[chapter.ID]),
+		attempts:     map[uint]int{},
+		correct:      map[uint]bool{},
+		latest:       map[uint]time.Time{},
+	}
+	for _, q := range questions {
+		limits.questions[q.ID] = q
+	}
+	for _, a := range answers {
+		if active != nil && a.AttemptID != nil && *a.AttemptID == active.ID {
+			limits.attempts[a.QuizQuestionID]++
+		}
+		if a.IsCorrect {
+			limits.correct[a.QuizQuestionID] = true
+		}
+		if a.CreatedAt.After(limits.latest[a.QuizQuestionID]) {
+			limits.latest[a.QuizQuestionID] = a.CreatedAt
+		}
+	}
+	return limits, nil
+}
+
+// limit - How many answers the question takes per quiz attempt (0 = unlimited, as for deleted questions)
+func (l *answerLimits) limit(questionID uint) int {
+	q, ok := l.questions[questionID]
+	if !ok {
+		return 0
+	}
+	return l.chapter.AnswerLimit(q)
+}
+
+// canStart - Whether the learner may start another quiz attempt
+func (l *answerLimits) canStart() bool {
+	return l.attemptsLeft == nil || *l.attemptsLeft > 0
+}
+
+// remaining - Answers left for the question in the active attempt, or in the next one between
+// attempts (nil when unlimited)
+func (l *answerLimits) remaining(questionID uint) *int {
+	limit := l.limit(questionID)
+	if limit == 0 {
+		return nil
+	}
+	remaining := 0
+	if l.active != nil {
+		remaining = max(limit-l.attempts[questionID], 0)
+	} else if l.canStart() {
+		remaining = limit
+	}
+	return &remaining
+}
+
+// revealed - Whether the learner may see the question's correct answer and explanation
+func (l *answerLimits) revealed(questionID uint) bool {
+	limit := l.limit(questionID)
+	canAnswer := l.canStart() || (l.active != nil && l.attempts[questionID] < limit)
+	return models.AnswerRevealed(limit, l.correct[questionID], canAnswer)
+}
+
+// record - Count a new answer to the question in the active attempt, received at receivedAt
+func (l *answerLimits) record(questionID uint, isCorrect bool, receivedAt time.Time) {
+	l.attempts[questionID]++
+	l.correct[questionID] = l.correct[questionID] || isCorrect
+	l.latest[questionID] = receivedAt
+}
+
+// checkAnswer - Refuse with 409 once the question's answers in the active attempt are used up, or 429 while its
+// cooldown is running. Cooldowns run on the server's clock, from when answers were received,
+// so offline answers cannot dodge them with an earlier occurred_at
+func (l *answerLimits) checkAnswer(questionID uint) error {
+	if limit := l.limit(questionID); limit > 0 && l.attempts[questionID] >= limit {
+		return refuse(http.StatusConflict, "No attempts left for this quiz question").with(gin.H{"max_attempts": limit})
+	}
+
+	latest, answered := l.latest[questionID]
+	cooldown := time.Duration(l.chapter.AnswerCooldownSeconds) * time.Second
+	if wait := time.Until(latest.Add(cooldown)); answered && wait > 0 {
+		retryAfter := int(math.Ceil(wait.Seconds()))
+		return refuse(http.StatusTooManyRequests, "Please wait before answering this quiz question again").
+			with(gin.H{"retry_after_seconds": retryAfter})
+	}
+	return nil
+}
+
+// applyTo - Add the question's attempt limit to a learner view and withhold what is not revealed yet
+func (l *answerLimits) applyTo(q *models.QuizQuestionWithUserAnswer) {
+	q.MaxAttempts = l.limit(q.ID)
+	q.AttemptsRemaining = l.remaining(q.ID)
+	if q.HasAnswered && !l.revealed(q.ID) {
+		q.CorrectAnswer = nil
+		q.Explanation = ""
+		q.Options = models.WithoutFeedback(q.Options)
+	}
+}
+
+// withholdAnswers - Blank the correct answer, explanation and option feedback in answer history
+// for questions the learner can still answer without having got them right
+func (h *Handler) withholdAnswers(userID string, answers []models.QuizAnswerWithDetails) error {
+	byChapter := map[uint]*answerLimits{}
+	for i := range answers {
+		a := &answers[i]
+		limits, ok := byChapter[a.ChapterID]
+		if !ok {
+			chapter, err := h.Chapters.GetChapter(a.ChapterID)
+			if err != nil && err != store.ErrNotFound {
+				return err
+			}
+			// Deleted chapters take no more answers, so nothing is held back
+			if err == nil {
+				if limits, err = h.answerLimitsFor(userID, chapter); err != nil {
+					return err
+				}
+			}
+			byChapter[a.ChapterID] = limits
+		}
+
+		if limits != nil && !limits.revealed(a.QuizQuestionID) {
+			a.CorrectAnswer = ""
+			a.Explanation = ""
+			a.Options = models.WithoutFeedback(a.Options)
+		}
+	}
+	return nil
+}
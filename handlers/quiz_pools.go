@@ -0,0 +1,425 @@
+package handlers
+
+import (
+	"learning-app-backend/models"
+	"learning-app-backend/store"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuestionPoolRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+}
+
+type DrawRuleRequest struct {
+	PoolID     uint   `json:"pool_id" binding:"required"`
+	DrawCount  int    `json:"draw_count" binding:"required"`
+	Topic      string `json:"topic"`
+	Difficulty string `json:"difficulty"`
+}
+
+// DrawRulesRequest - The chapter's complete set of draw rules; an empty list shows every question again
+type DrawRulesRequest struct {
+	Rules []DrawRuleRequest `json:"rules"`
+}
+
+// GetQuestionPools - List a chapter's question pools
+func (h *Handler) GetQuestionPools(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
+
+	if !h.chapterExists(c, chapterID) {
+		return
+	}
+
+	pools, err := h.Chapters.ListPools(chapterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch question pools",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"pools":   pools,
+	})
+}
+
+// CreateQuestionPool - Add a question pool to a chapter
+func (h *Handler) CreateQuestionPool(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
+	var req QuestionPoolRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if strings.TrimSpace(req.Title) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "title cannot be empty",
+		})
+		return
+	}
+
+	if !h.chapterExists(c, chapterID) {
+		return
+	}
+
+	pool := models.QuestionPool{
+		ChapterID:   chapterID,
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+	}
+	if err := h.Chapters.CreatePool(&pool); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create question pool",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Question pool created successfully",
+		"pool":    pool,
+	})
+}
+
+// UpdateQuestionPool - Rename or redescribe a question pool
+func (h *Handler) UpdateQuestionPool(c *gin.Context) {
+	poolID, ok := parseID(c, "poolId", "pool ID")
+	if !ok {
+		return
+	}
+	var req QuestionPoolRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if strings.TrimSpace(req.Title) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "title cannot be empty",
+		})
+		return
+	}
+
+	pool := models.QuestionPool{
+		ID:          poolID,
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+	}
+	err := h.Chapters.UpdatePool(&pool)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Question pool not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update question pool",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Question pool updated successfully",
+		"pool":    pool,
+	})
+}
+
+// DeleteQuestionPool - Soft delete a pool and the draw rules using it; its questions stay in the chapter
+func (h *Handler) DeleteQuestionPool(c *gin.Context) {
+	poolID, ok := parseID(c, "poolId", "pool ID")
+	if !ok {
+		return
+	}
+
+	err := h.Chapters.DeletePool(poolID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Question pool not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to delete question pool",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Question pool deleted successfully",
+	})
+}
+
+// GetDrawRules - List the draw rules that make up a chapter's quiz
+func (h *Handler) GetDrawRules(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
+
+	if !h.chapterExists(c, chapterID) {
+		return
+	}
+
+	rules, err := h.Chapters.ListDrawRules(chapterID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch draw rules",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"rules":   rules,
+	})
+}
+
+// ReplaceDrawRules - Define a chapter's quiz as "draw N from pool X" rules; attempts already
+// in progress keep the questions they drew
+func (h *Handler) ReplaceDrawRules(c *gin.Context) {
+	chapterID, ok := parseID(c, "id", "chapter ID")
+	if !ok {
+		return
+	}
+	var req DrawRulesRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	rules := make([]models.QuizDrawRule, 0, len(req.Rules))
+	for _, r := range req.Rules {
+		rule := models.QuizDrawRule{
+			PoolID:     r.PoolID,
+			DrawCount:  r.DrawCount,
+			Topic:      strings.TrimSpace(r.Topic),
+			Difficulty: strings.ToLower(strings.TrimSpace(r.Difficulty)),
+		}
+		msg := ""
+		if rule.DrawCount < 1 {
+			msg = "draw_count must be at least 1"
+		} else if !models.IsValidDifficulty(rule.Difficulty) {
+			msg = "difficulty must be one of: easy, medium, hard"
+		}
+		if msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": msg,
+			})
+			return
+		}
+		rules = append(rules, rule)
+	}
+
+	if !h.chapterExists(c, chapterID) {
+		return
+	}
+	for _, rule := range rules {
+		if !h.poolInChapter(c, rule.PoolID, chapterID) {
+			return
+		}
+	}
+
+	if err := h.Chapters.ReplaceDrawRules(chapterID, rules); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to save draw rules",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Draw rules saved successfully",
+		"rules":   rules,
+	})
+}
+
+// poolInChapter - Respond 404/400 and return false unless the pool exists and belongs to the chapter
+func (h *Handler) poolInChapter(c *gin.Context, poolID, chapterID uint) bool {
+	pool, err := h.Chapters.GetPool(poolID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Question pool not found",
+		})
+		return false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return false
+	}
+	if pool.ChapterID != chapterID {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Question pool belongs to a different chapter",
+		})
+		return false
+	}
+	return true
+}
+
+// learnerQuiz - The quiz one learner is taking in a chapter
+type learnerQuiz struct {
+	presentation quizPresentation
+	attempt      *models.QuizAttempt   // In progress, nil if none
+	questions    []models.QuizQuestion // In the learner's order
+	drawn        bool                  // Questions were drawn from pools rather than being every chapter question
+}
+
+// learnerQuizFor - The questions userID sees for chapter, in order
+func (h *Handler) learnerQuizFor(userID string, chapter *models.Chapter) (*learnerQuiz, error) {
+	presentation, err := h.presentationFor(userID, chapter)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt, err := h.Quiz.ActiveAttempt(userID, chapter.ID)
+	if err == store.ErrNotFound {
+		attempt = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	questions, drawn, err := h.quizQuestions(chapter.ID, presentation, attempt)
+	if err != nil {
+		return nil, err
+	}
+	presentation.orderQuestions(questions)
+
+	return &learnerQuiz{presentation: presentation, attempt: attempt, questions: questions, drawn: drawn}, nil
+}
+
+// quizQuestions - The questions an attempt is made of: those frozen for it, every chapter
+// question, or for a chapter with draw rules and no attempt yet, those the next one will draw
+func (h *Handler) quizQuestions(chapterID uint, p quizPresentation, attempt *models.QuizAttempt) ([]models.QuizQuestion, bool, error) {
+	questions, err := h.Chapters.ListQuizQuestions(chapterID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Attempts started before the chapter had draw rules have nothing frozen and keep every question
+	if attempt != nil {
+		ids, err := h.Quiz.AttemptQuestionIDs(attempt.ID)
+		if err != nil || len(ids) == 0 {
+			return questions, false, err
+		}
+		return pickQuestions(questions, ids), true, nil
+	}
+
+	rules, err := h.Chapters.ListDrawRules(chapterID)
+	if err != nil || len(rules) == 0 {
+		return questions, false, err
+	}
+	return p.drawQuestions(rules, questions), true, nil
+}
+
+// drawQuestions - Apply each rule in turn, taking its draw_count best-ranked matches not drawn by an
+// earlier rule (fewer if the pool runs short); the result keeps the chapter's question order
+func (p quizPresentation) drawQuestions(rules []models.QuizDrawRule, questions []models.QuizQuestion) []models.QuizQuestion {
+	drawn := map[uint]bool{}
+	for _, rule := range rules {
+		var candidates []models.QuizQuestion
+		for _, q := range questions {
+			if !drawn[q.ID] && rule.Matches(q) {
+				candidates = append(candidates, q)
+			}
+		}
+		ranks := make(map[uint]uint64, len(candidates))
+		for _, q := range candidates {
+			ranks[q.ID] = p.rank("draw", strconv.FormatUint(uint64(q.ID), 10))
+		}
+		sort.SliceStable(candidates, func(i, j int) bool { return ranks[candidates[i].ID] < ranks[candidates[j].ID] })
+
+		for i := 0; i < len(candidates) && i < rule.DrawCount; i++ {
+			drawn[candidates[i].ID] = true
+		}
+	}
+
+	var result []models.QuizQuestion
+	for _, q := range questions {
+		if drawn[q.ID] {
+			result = append(result, q)
+		}
+	}
+	return result
+}
+
+// pickQuestions - The questions with the given IDs, in that order; deleted ones are skipped
+func pickQuestions(questions []models.QuizQuestion, ids []uint) []models.QuizQuestion {
+	byID := make(map[uint]models.QuizQuestion, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+	var picked []models.QuizQuestion
+	for _, id := range ids {
+		if q, ok := byID[id]; ok {
+			picked = append(picked, q)
+		}
+	}
+	return picked
+}
+
+// containsQuestion - Whether questionID is among questions
+func containsQuestion(questions []models.QuizQuestion, questionID uint) bool {
+	for _, q := range questions {
+		if q.ID == questionID {
+			return true
+		}
+	}
+	return false
+}
+
+// withUserAnswers - The learner's questions out of every chapter question with its latest answer, in order
+func (q *learnerQuiz) withUserAnswers(all []models.QuizQuestionWithUserAnswer) []models.QuizQuestionWithUserAnswer {
+	byID := make(map[uint]models.QuizQuestionWithUserAnswer, len(all))
+	for _, question := range all {
+		byID[question.ID] = question
+	}
+	var result []models.QuizQuestionWithUserAnswer
+	for _, question := range q.questions {
+		if withAnswer, ok := byID[question.ID]; ok {
+			result = append(result, withAnswer)
+		}
+	}
+	return q.presentation.withUserAnswers(result)
+}
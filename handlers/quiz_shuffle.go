@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"learning-app-backend/models"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// quizPresentation - How one learner sees a chapter's quiz during their current attempt
+//
+// Shuffled orders are ranked by a hash of the learner, the chapter and the attempt number,
+// so a reload shows the same order while every new attempt gets a fresh one. Ranking by
+// question ID and stored option key keeps the order stable when content is edited.
+type quizPresentation struct {
+	shuffleQuestions bool
+	shuffleOptions   bool
+	seed             string
+}
+
+// presentationFor - The presentation of chapter's quiz for userID
+//
+// The attempt number is the count of finished attempts, so the order (and pool draw) seen
+// before the first answer auto-starts an attempt is the one used within it.
+func (h *Handler) presentationFor(userID string, chapter *models.Chapter) (quizPresentation, error) {
+	attempts, err := h.Quiz.ListAttempts(userID, chapter.ID)
+	if err != nil {
+		return quizPresentation{}, err
+	}
+	finished := 0
+	for _, attempt := range attempts {
+		if attempt.Status == models.AttemptFinished {
+			finished++
+		}
+	}
+	return newPresentation(userID, chapter, finished), nil
+}
+
+// newPresentation - The presentation for userID's attempt number `attempt` at chapter's quiz
+func newPresentation(userID string, chapter *models.Chapter, attempt int) quizPresentation {
+	return quizPresentation{
+		shuffleQuestions: chapter.ShuffleQuestions,
+		shuffleOptions:   chapter.ShuffleOptions,
+		seed:             userID + "\x00" + strconv.FormatUint(uint64(chapter.ID), 10) + "\x00" + strconv.Itoa(attempt),
+	}
+}
+
+// rank - Sort key for one question or option; a cryptographic hash so neighbouring IDs land far apart
+func (p quizPresentation) rank(parts ...string) uint64 {
+	hash := sha256.New()
+	hash.Write([]byte(p.seed))
+	for _, part := range parts {
+		hash.Write([]byte{0})
+		hash.Write([]byte(part))
+	}
+	return binary.BigEndian.Uint64(hash.Sum(nil))
+}
+
+// orderQuestions - Sort questions into the learner's order (left as is unless questions are shuffled)
+func (p quizPresentation) orderQuestions(questions []models.QuizQuestion) {
+	if !p.shuffleQuestions {
+		return
+	}
+	ranks := make(map[uint]uint64, len(questions))
+	for _, q := range questions {
+		ranks[q.ID] = p.rank("question", strconv.FormatUint(uint64(q.ID), 10))
+	}
+	sort.SliceStable(questions, func(i, j int) bool { return ranks[questions[i].ID] < ranks[questions[j].ID] })
+}
+
+// shownOptions - One question's options as the learner sees them
+//
+// Shuffled options are relabelled A, B, C... by position, so a key alone says nothing about
+// which option it stands for; the maps translate keys between the two labellings.
+type shownOptions struct {
+	options  []models.QuestionOption
+	toStored map[string]string // shown key -> stored key
+	toShown  map[string]string // stored key -> shown key
+}
+
+// optionsFor - The shown options of one question; options is the unshuffled display order
+func (p quizPresentation) optionsFor(questionID uint, questionType string, options []models.QuestionOption) shownOptions {
+	switch questionType {
+	case models.QuestionMultipleChoice, models.QuestionMultiSelect, models.QuestionOrdering:
+	default:
+		// True/false keys are the answers themselves, and free-form questions have no options
+		return shownOptions{options: options}
+	}
+	if !p.shuffleOptions {
+		return shownOptions{options: options}
+	}
+
+	id := strconv.FormatUint(uint64(questionID), 10)
+	ranks := make(map[string]uint64, len(options))
+	for _, option := range options {
+		ranks[option.Key] = p.rank("option", id, option.Key)
+	}
+	shuffled := append([]models.QuestionOption(nil), options...)
+	sort.SliceStable(shuffled, func(i, j int) bool { return ranks[shuffled[i].Key] < ranks[shuffled[j].Key] })
+
+	shown := shownOptions{toStored: map[string]string{}, toShown: map[string]string{}}
+	for i, option := range shuffled {
+		key := string(rune('A' + i))
+		shown.toStored[key] = option.Key
+		shown.toShown[option.Key] = key
+		option.Key = key
+		shown.options = append(shown.options, option)
+	}
+	return shown
+}
+
+// question - q as presented, with its answer key in shown keys, ready for NormalizeAnswer and IsCorrect
+func (s shownOptions) question(q models.QuizQuestion) models.QuizQuestion {
+	if s.toShown == nil {
+		return q
+	}
+	q.Options = s.options
+	q.AnswerKey.OptionKeys = relabelKeys(q.QuestionType, q.AnswerKey.OptionKeys, s.toShown, s.options)
+	return q
+}
+
+// stored - Translate an answer normalized against question() back into stored keys
+func (s shownOptions) stored(q models.QuizQuestion, answer string) string {
+	if s.toStored == nil {
+		return answer
+	}
+	return strings.Join(relabelKeys(q.QuestionType, strings.Split(answer, ","), s.toStored, q.Options), ",")
+}
+
+// shown - Translate a stored answer (user_answer or correct_answer) into shown keys
+func (s shownOptions) shown(questionType string, answer string) string {
+	if s.toShown == nil {
+		return answer
+	}
+	return strings.Join(relabelKeys(questionType, strings.Split(answer, ","), s.toShown, s.options), ",")
+}
+
+// relabelKeys - Rewrite option keys through mapping; multi-select keys are re-sorted into the order of options
+func relabelKeys(questionType string, keys []string, mapping map[string]string, options []models.QuestionOption) []string {
+	relabelled := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if mapped, ok := mapping[key]; ok {
+			key = mapped
+		}
+		relabelled = append(relabelled, key)
+	}
+	if questionType == models.QuestionMultiSelect {
+		position := map[string]int{}
+		for i, option := range options {
+			position[option.Key] = i
+		}
+		sort.SliceStable(relabelled, func(i, j int) bool { return position[relabelled[i]] < position[relabelled[j]] })
+	}
+	return relabelled
+}
+
+// learnerViews - Learner views of questions, with their shown options
+func (p quizPresentation) learnerViews(questions []models.QuizQuestion) []models.LearnerQuizQuestion {
+	var views []models.LearnerQuizQuestion
+	for _, q := range questions {
+		view := q.LearnerView()
+		view.Options = p.optionsFor(q.ID, q.QuestionType, view.Options).options
+		views = append(views, view)
+	}
+	return views
+}
+
+// withUserAnswers - Show with-history questions with their shown options, answers translated into shown keys
+func (p quizPresentation) withUserAnswers(questions []models.QuizQuestionWithUserAnswer) []models.QuizQuestionWithUserAnswer {
+	for i := range questions {
+		q := &questions[i]
+		shown := p.optionsFor(q.ID, q.QuestionType, q.Options)
+		q.Options = shown.options
+		if q.UserAnswer != nil {
+			answer := shown.shown(q.QuestionType, *q.UserAnswer)
+			q.UserAnswer = &answer
+		}
+		if q.CorrectAnswer != nil {
+			correct := shown.shown(q.QuestionType, *q.CorrectAnswer)
+			q.CorrectAnswer = &correct
+		}
+	}
+	return questions
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"learning-app-backend/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var shuffledChapter = &models.Chapter{ID: 7, ShuffleQuestions: true, ShuffleOptions: true}
+
+// sixOptions - Options A-F, enough that two shuffles rarely agree by chance
+func sixOptions() []models.QuestionOption {
+	var options []models.QuestionOption
+	for _, key := range []string{"A", "B", "C", "D", "E", "F"} {
+		options = append(options, models.QuestionOption{Key: key, Text: "Option " + key})
+	}
+	return options
+}
+
+// storedOrder - The stored keys of shown options, in the order they are shown
+func storedOrder(shown shownOptions) string {
+	var keys []string
+	for _, option := range shown.options {
+		keys = append(keys, shown.toStored[option.Key])
+	}
+	return strings.Join(keys, ",")
+}
+
+func TestShuffleIsStableWithinAnAttempt(t *testing.T) {
+	first := newPresentation("learner1", shuffledChapter, 0)
+	again := newPresentation("learner1", shuffledChapter, 0)
+
+	order := storedOrder(first.optionsFor(1, models.QuestionMultipleChoice, sixOptions()))
+	if got := storedOrder(again.optionsFor(1, models.QuestionMultipleChoice, sixOptions())); got != order {
+		t.Errorf("options reloaded as %s, first shown as %s", got, order)
+	}
+
+	questions := []models.QuizQuestion{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+	reloaded := append([]models.QuizQuestion(nil), questions...)
+	first.orderQuestions(questions)
+	again.orderQuestions(reloaded)
+	if !reflect.DeepEqual(questions, reloaded) {
+		t.Errorf("questions reloaded as %v, first shown as %v", reloaded, questions)
+	}
+}
+
+func TestShuffleChangesBetweenAttemptsAndLearners(t *testing.T) {
+	order := storedOrder(newPresentation("learner1", shuffledChapter, 0).optionsFor(1, models.QuestionMultipleChoice, sixOptions()))
+	if order == "A,B,C,D,E,F" {
+		t.Errorf("options were not shuffled")
+	}
+
+	next := storedOrder(newPresentation("learner1", shuffledChapter, 1).optionsFor(1, models.QuestionMultipleChoice, sixOptions()))
+	if next == order {
+		t.Errorf("the next attempt shows the options in the same order %s", order)
+	}
+	other := storedOrder(newPresentation("learner2", shuffledChapter, 0).optionsFor(1, models.QuestionMultipleChoice, sixOptions()))
+	if other == order {
+		t.Errorf("another learner sees the options in the same order %s", order)
+	}
+}
+
+func TestShuffledOptionsAreRelabelledByPosition(t *testing.T) {
+	shown := newPresentation("learner1", shuffledChapter, 0).optionsFor(1, models.QuestionMultiSelect, sixOptions())
+	for i, option := range shown.options {
+		if want := string(rune('A' + i)); option.Key != want {
+			t.Errorf("option %d shown as %s, want %s", i, option.Key, want)
+		}
+		if stored := shown.toStored[option.Key]; option.Text != "Option "+stored || shown.toShown[stored] != option.Key {
+			t.Errorf("shown key %s maps to stored %s but shows %q", option.Key, stored, option.Text)
+		}
+	}
+}
+
+func TestUnshuffledOptionsKeepTheirKeys(t *testing.T) {
+	unshuffled := newPresentation("learner1", &models.Chapter{ID: 7}, 0)
+	if shown := unshuffled.optionsFor(1, models.QuestionMultipleChoice, sixOptions()); !reflect.DeepEqual(shown.options, sixOptions()) {
+		t.Errorf("unshuffled options shown as %v", shown.options)
+	}
+
+	// True/false keys are the answers themselves
+	shuffled := newPresentation("learner1", shuffledChapter, 0)
+	shown := shuffled.optionsFor(1, models.QuestionTrueFalse, models.TrueFalseOptions())
+	if !reflect.DeepEqual(shown.options, models.TrueFalseOptions()) || shown.toShown != nil {
+		t.Errorf("true/false options were shuffled: %v", shown.options)
+	}
+}
+
+func TestShuffledAnswersGradeAgainstTheStoredKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		qType  string
+		stored []string // Correct answer in stored keys
+	}{
+		{"multiple choice", models.QuestionMultipleChoice, []string{"D"}},
+		{"multi-select", models.QuestionMultiSelect, []string{"B", "E", "F"}},
+		{"ordering", models.QuestionOrdering, []string{"F", "A", "E", "B", "D", "C"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := models.QuizQuestion{ID: 3, QuestionType: tt.qType, Options: sixOptions(),
+				AnswerKey: models.AnswerKey{OptionKeys: tt.stored}}
+			for attempt := 0; attempt < 3; attempt++ {
+				options := newPresentation("learner1", shuffledChapter, attempt).optionsFor(q.ID, q.QuestionType, q.ReviewOptions())
+				shownQuestion := options.question(q)
+
+				// The learner answers with the keys they see for the correct options
+				var keys []string
+				for _, key := range tt.stored {
+					keys = append(keys, options.toShown[key])
+				}
+				raw, _ := json.Marshal(keys)
+				if tt.qType == models.QuestionMultipleChoice {
+					raw, _ = json.Marshal(keys[0])
+				}
+
+				answer, err := shownQuestion.NormalizeAnswer(raw)
+				if err != nil {
+					t.Fatalf("attempt %d: NormalizeAnswer(%s) failed: %v", attempt, raw, err)
+				}
+				if !shownQuestion.IsCorrect(answer) {
+					t.Errorf("attempt %d: shown answer %s graded wrong", attempt, answer)
+				}
+				if got := options.stored(q, answer); !q.IsCorrect(got) {
+					t.Errorf("attempt %d: stored answer %s does not match the answer key %v", attempt, got, tt.stored)
+				}
+				if got := options.shown(q.QuestionType, strings.Join(tt.stored, ",")); got != answer {
+					t.Errorf("attempt %d: correct answer shown as %s, want %s", attempt, got, answer)
+				}
+			}
+		})
+	}
+}
+
+func TestRelabelKeys(t *testing.T) {
+	options := []models.QuestionOption{{Key: "A"}, {Key: "B"}, {Key: "C"}}
+	mapping := map[string]string{"X": "C", "Y": "A", "Z": "B"}
+	tests := []struct {
+		name  string
+		qType string
+		keys  []string
+		want  []string
+	}{
+		{"multiple choice", models.QuestionMultipleChoice, []string{"Z"}, []string{"B"}},
+		{"multi-select re-sorted into option order", models.QuestionMultiSelect, []string{"X", "Y"}, []string{"A", "C"}},
+		{"ordering keeps the sequence", models.QuestionOrdering, []string{"X", "Y", "Z"}, []string{"C", "A", "B"}},
+		{"unknown keys pass through", models.QuestionMultipleChoice, []string{"Q"}, []string{"Q"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := relabelKeys(tt.qType, tt.keys, mapping, options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("relabelKeys(%v) = %v, want %v", tt.keys, got, tt.want)
+			}
+		})
+	}
+}
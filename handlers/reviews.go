@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/json"
+	"learning-app-backend/models"
+	"learning-app-backend/store"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubmitReviewRequest - user_answer is given in the question's own option keys (reviews are never
+// shuffled); quality optionally rates how well the learner remembered (0-5, SM-2 scale)
+type SubmitReviewRequest struct {
+	UserID         string          `json:"user_id" binding:"required"`
+	QuizQuestionID uint            `json:"quiz_question_id" binding:"required"`
+	UserAnswer     json.RawMessage `json:"user_answer" binding:"required"`
+	Quality        *int            `json:"quality"`
+}
+
+// scheduleReview - Put an answered quiz question into the learner's review queue. The first answer
+// starts its schedule; later quiz answers only reset it when they are wrong, so retaking a quiz
+// does not push reviews further out
+func (h *Handler) scheduleReview(answer models.QuizAnswer) error {
+	item, err := h.Reviews.GetReviewItem(answer.UserID, answer.QuizQuestionID)
+	if err == store.ErrNotFound {
+		fresh := models.NewReviewItem(answer.UserID, answer.ChapterID, answer.QuizQuestionID)
+		item = &fresh
+	} else if err != nil {
+		return err
+	} else if answer.IsCorrect {
+		return nil
+	}
+
+	item.Schedule(models.RecallQuality(answer.IsCorrect, nil), answer.AnsweredAt)
+	return h.Reviews.SaveReviewItem(item)
+}
+
+// reviewsDueBy - When "today" ends in the learner's time zone: reviews due before then are due
+func reviewsDueBy(loc *time.Location) time.Time {
+	year, month, day := time.Now().In(loc).Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+}
+
+// GetDueReviews - Get the reviews due by the end of today across all chapters
+func (h *Handler) GetDueReviews(c *gin.Context) {
+	userID := c.Param("userId")
+
+	// "Today" ends at midnight in the learner's time zone (UTC unless ?tz= names one)
+	loc, err := time.LoadLocation(c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid time zone",
+		})
+		return
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid limit",
+			})
+			return
+		}
+	}
+
+	dayEnd := reviewsDueBy(loc)
+
+	reviews, err := h.Reviews.ListDueReviews(userID, dayEnd, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch due reviews",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"reviews":  reviews,
+		"count":    len(reviews),
+		"due_by":   dayEnd,
+		"timezone": loc.String(),
+	})
+}
+
+// SubmitReview - Answer a due review item and reschedule it; review answers never count towards
+// quiz scores. Items are due as in GetDueReviews (by the end of today, ?tz=)
+func (h *Handler) SubmitReview(c *gin.Context) {
+	var req SubmitReviewRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if !requireSameUser(c, req.UserID) {
+		return
+	}
+
+	loc, err := time.LoadLocation(c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid time zone",
+		})
+		return
+	}
+
+	if req.Quality != nil && (*req.Quality < 0 || *req.Quality > 5) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "quality must be between 0 and 5",
+		})
+		return
+	}
+
+	question, err := h.Chapters.GetQuizQuestion(req.QuizQuestionID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Quiz question not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+
+	// Reviewing must not become a way around the quiz's attempt limit
+	chapter, err := h.Chapters.GetChapter(question.ChapterID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Quiz question not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+	limits, err := h.answerLimitsFor(req.UserID, chapter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch answer history",
+		})
+		return
+	}
+	if !limits.revealed(question.ID) {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Finish this question in its quiz before reviewing it",
+		})
+		return
+	}
+
+	item, err := h.Reviews.GetReviewItem(req.UserID, question.ID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "No review scheduled for this question",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch review",
+		})
+		return
+	}
+
+	// Reviewing early would push the next review out before the item was due to be recalled
+	if !item.DueAt.Before(reviewsDueBy(loc)) {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Review is not due yet",
+			"due_at":  item.DueAt,
+		})
+		return
+	}
+
+	userAnswer, err := question.NormalizeAnswer(req.UserAnswer)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid answer: " + err.Error(),
+		})
+		return
+	}
+
+	isCorrect := question.IsCorrect(userAnswer)
+	answer := models.ReviewAnswer{
+		UserID:         req.UserID,
+		QuizQuestionID: question.ID,
+		UserAnswer:     userAnswer,
+		IsCorrect:      isCorrect,
+		Quality:        models.RecallQuality(isCorrect, req.Quality),
+	}
+	item.Schedule(answer.Quality, time.Now().UTC())
+
+	if err := h.Reviews.RecordReview(item, &answer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to save review",
+		})
+		return
+	}
+	if err := h.refreshStreakRecord(req.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update streak",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"message":        "Review submitted successfully",
+		"is_correct":     isCorrect,
+		"correct_answer": question.CorrectAnswerText(),
+		"explanation":    question.Explanation,
+		"feedback":       question.AnswerFeedback(userAnswer),
+		"answer":         answer,
+		"review":         item,
+	})
+}
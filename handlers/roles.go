@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"learning-app-backend/auth"
+	"learning-app-backend/middleware"
+	"learning-app-backend/store"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type RoleInfo struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+// GetRoles - List roles and the permissions they grant
+func (h *Handler) GetRoles(c *gin.Context) {
+	var roles []RoleInfo
+	for _, role := range auth.Roles() {
+		roles = append(roles, RoleInfo{Role: role, Permissions: auth.Permissions(role)})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"roles":   roles,
+	})
+}
+
+// ListUsers - List all users with their roles (optionally filtered by ?role=)
+func (h *Handler) ListUsers(c *gin.Context) {
+	role := c.Query("role")
+
+	users, err := h.Users.ListUsers(role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch users",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"users":   users,
+	})
+}
+
+// UpdateUserRole - Grant a role to a user (admin only)
+func (h *Handler) UpdateUserRole(c *gin.Context) {
+	userID := c.Param("userId")
+	var req UpdateRoleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	req.Role = strings.ToLower(strings.TrimSpace(req.Role))
+	if !auth.IsValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid role. Must be one of: " + strings.Join(auth.Roles(), ", "),
+		})
+		return
+	}
+
+	// Prevent admins from locking themselves out
+	if userID == middleware.AuthUserID(c) && req.Role != auth.RoleAdmin {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "You cannot remove your own admin role",
+		})
+		return
+	}
+
+	user, err := h.Users.UpdateUserRole(userID, req.Role)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "User not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update role",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Role updated successfully",
+		"user":    user,
+	})
+}
@@ -0,0 +1,467 @@
+package handlers
+
+import (
+	"learning-app-backend/models"
+	"learning-app-backend/store"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Days of history GetStreak returns unless ?days= asks for another number
+const (
+	defaultStreakDays = 30
+	maxStreakDays     = 366
+)
+
+type StreakGoalRequest struct {
+	GoalType string `json:"goal_type" binding:"required"`
+	Target   int    `json:"target" binding:"required"`
+	Timezone string `json:"timezone"` // IANA name; keeps the current time zone when empty
+}
+
+type StreakFreezeRequest struct {
+	Date string `json:"date" binding:"required"` // YYYY-MM-DD in the learner's time zone
+}
+
+// FreezeAllowance - The learner's streak freezes for the current month and the days frozen ahead
+type FreezeAllowance struct {
+	PerMonth      int      `json:"per_month"`
+	UsedThisMonth int      `json:"used_this_month"`
+	LeftThisMonth int      `json:"left_this_month"`
+	Scheduled     []string `json:"scheduled"` // Frozen days after today
+}
+
+// streakState - Everything a learner's streak is computed from, as of today in their time zone
+type streakState struct {
+	goal     models.StreakGoal // In effect today
+	loc      *time.Location
+	today    string
+	freezes  []models.StreakFreeze
+	calendar []models.StreakDay // Consecutive days ending today
+	streak   models.Streak
+	record   *models.StreakRecord // The streak record as of today; nil when the stored one is current
+}
+
+// streakToday - The learner's goals, oldest first, and a state holding just today's goal, time
+// zone and date
+func (h *Handler) streakToday(userID string) (*streakState, []models.StreakGoal, error) {
+	goals, err := h.Streaks.ListStreakGoals(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	state := &streakState{goal: models.DefaultStreakGoal(userID)}
+	if len(goals) > 0 {
+		state.goal = goals[len(goals)-1]
+	}
+	// Goals are validated when saved; an unknown zone falls back to UTC
+	if state.loc, err = time.LoadLocation(state.goal.Timezone); err != nil {
+		state.loc = time.UTC
+	}
+	state.today = time.Now().In(state.loc).Format(models.DateLayout)
+	return state, goals, nil
+}
+
+// loadStreak - Roll the learner's watched time and answers up by day in their time zone and
+// judge each day against its goal. The calendar covers at least the last days days, the days
+// since the streak was last worked out and the whole streak running into them; the learner's
+// streak record stands in for the days before. Nothing is saved: see saveStreakRecord
+func (h *Handler) loadStreak(userID string, days int) (*streakState, error) {
+	state, goals, err := h.streakToday(userID)
+	if err != nil {
+		return nil, err
+	}
+	if state.freezes, err = h.Streaks.ListStreakFreezes(userID); err != nil {
+		return nil, err
+	}
+	record, err := h.Streaks.GetStreakRecord(userID)
+	if err == store.ErrNotFound {
+		record = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// From yesterday at the latest, so a streak running into today shows on the first day. The
+	// first time, the whole history is read
+	from := models.AddDays(state.today, min(1-days, -1))
+	if record != nil {
+		from = min(from, record.CountedThrough)
+	}
+	for {
+		if state.calendar, err = h.streakCalendar(userID, state, goals, from, record == nil); err != nil {
+			return nil, err
+		}
+		if first := state.calendar[0]; record == nil || !first.GoalMet && !first.Frozen {
+			break
+		}
+		// The first day continues a streak from before it: look twice as far back
+		from = models.AddDays(from, -len(state.calendar))
+	}
+
+	state.streak = models.ComputeStreak(state.calendar)
+	if record != nil {
+		state.streak = record.Merge(state.streak)
+	}
+	updated := models.StreakRecord{UserID: userID, LongestStreak: state.streak.Longest,
+		LastGoalDate: state.streak.LastGoalDate, CountedThrough: state.today}
+	if record == nil || record.LongestStreak != updated.LongestStreak ||
+		record.LastGoalDate != updated.LastGoalDate || record.CountedThrough != updated.CountedThrough {
+		state.record = &updated
+	}
+	return state, nil
+}
+
+// saveStreakRecord - Keep the streak loadStreak worked out, when it changed the learner's record.
+// Only writes save it, so reading a streak never changes anything
+func (h *Handler) saveStreakRecord(state *streakState) error {
+	if state.record == nil {
+		return nil
+	}
+	return h.Streaks.SaveStreakRecord(state.record)
+}
+
+// refreshStreakRecord - Work the learner's streak out through today after new activity, the
+// first time each day; later activity the same day is read back on every request anyway
+func (h *Handler) refreshStreakRecord(userID string) error {
+	state, _, err := h.streakToday(userID)
+	if err != nil {
+		return err
+	}
+	if record, err := h.Streaks.GetStreakRecord(userID); err == nil && record.CountedThrough >= state.today {
+		return nil
+	} else if err != nil && err != store.ErrNotFound {
+		return err
+	}
+	if state, err = h.loadStreak(userID, 1); err != nil {
+		return err
+	}
+	return h.saveStreakRecord(state)
+}
+
+// streakCalendar - The learner's days from from through today, loading only the activity since,
+// or from their first active or frozen day if earlier and all is set
+func (h *Handler) streakCalendar(userID string, state *streakState, goals []models.StreakGoal, from string, all bool) ([]models.StreakDay, error) {
+	var since time.Time
+	if !all {
+		start, err := time.ParseInLocation(models.DateLayout, from, state.loc)
+		if err != nil {
+			return nil, err
+		}
+		since = start
+	}
+	sessions, err := h.Progress.ListLearningSessions(userID, since, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	answerTimes, err := h.Streaks.ListAnswerTimes(userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	// A session counts toward the day it started on, as in GetActivitySummary
+	activity := map[string]models.StreakDay{}
+	for _, session := range sessions {
+		date := session.StartedAt.In(state.loc).Format(models.DateLayout)
+		day := activity[date]
+		day.ActiveSeconds += session.ActiveSeconds
+		day.WatchedSeconds += session.WatchedSeconds
+		activity[date] = day
+	}
+	for _, answeredAt := range answerTimes {
+		date := answeredAt.In(state.loc).Format(models.DateLayout)
+		day := activity[date]
+		day.QuestionsAnswered++
+		activity[date] = day
+	}
+	frozen := map[string]bool{}
+	for _, freeze := range state.freezes {
+		if all || freeze.Date >= from {
+			frozen[freeze.Date] = true
+		}
+	}
+	return models.StreakCalendar(activity, frozen, goals, from, state.today), nil
+}
+
+// day - The calendar entry for a date up to today
+func (s *streakState) day(date string) (models.StreakDay, bool) {
+	for _, day := range s.calendar {
+		if day.Date == date {
+			return day, true
+		}
+	}
+	return models.StreakDay{}, false
+}
+
+// freezesInMonth - How many days the learner froze in date's calendar month
+func (s *streakState) freezesInMonth(date string) int {
+	count := 0
+	for _, freeze := range s.freezes {
+		if freeze.Date[:7] == date[:7] {
+			count++
+		}
+	}
+	return count
+}
+
+// allowance - The learner's freezes this month and the days frozen ahead
+func (s *streakState) allowance() FreezeAllowance {
+	allowance := FreezeAllowance{
+		PerMonth:      models.StreakFreezesPerMonth,
+		UsedThisMonth: s.freezesInMonth(s.today),
+		Scheduled:     []string{},
+	}
+	allowance.LeftThisMonth = max(allowance.PerMonth-allowance.UsedThisMonth, 0)
+	for _, freeze := range s.freezes {
+		if freeze.Date > s.today {
+			allowance.Scheduled = append(allowance.Scheduled, freeze.Date)
+		}
+	}
+	return allowance
+}
+
+// respondStreak - Respond with the learner's streak, goal, freezes and the last days days,
+// saving their streak record after a change to their goal or freezes
+func (h *Handler) respondStreak(c *gin.Context, userID string, status, days int, changed bool) {
+	state, err := h.loadStreak(userID, days)
+	if err == nil && changed {
+		err = h.saveStreakRecord(state)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch streak",
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"success":  true,
+		"timezone": state.loc.String(),
+		"today":    state.today,
+		"goal":     state.goal,
+		"streak":   state.streak,
+		"freezes":  state.allowance(),
+		"days":     state.calendar[max(len(state.calendar)-days, 0):],
+	})
+}
+
+// GetStreak - Get the learner's current and longest streaks, daily goal, streak freezes and
+// their daily activity for the last 30 days (?days= for up to a year)
+func (h *Handler) GetStreak(c *gin.Context) {
+	userID := c.Param("userId")
+
+	days := defaultStreakDays
+	if raw := c.Query("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxStreakDays {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid days: use 1 to " + strconv.Itoa(maxStreakDays),
+			})
+			return
+		}
+		days = n
+	}
+
+	h.respondStreak(c, userID, http.StatusOK, days, false)
+}
+
+// UpdateStreakGoal - Set the learner's daily goal and time zone from today on. Days already over
+// keep the goal they had
+func (h *Handler) UpdateStreakGoal(c *gin.Context) {
+	userID := c.Param("userId")
+	var req StreakGoalRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	var maxTarget int
+	switch req.GoalType {
+	case models.GoalMinutes:
+		maxTarget = models.MaxGoalMinutes
+	case models.GoalQuestions:
+		maxTarget = models.MaxGoalQuestions
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "goal_type must be " + models.GoalMinutes + " or " + models.GoalQuestions,
+		})
+		return
+	}
+	if req.Target < 1 || req.Target > maxTarget {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "target must be between 1 and " + strconv.Itoa(maxTarget),
+		})
+		return
+	}
+
+	goals, err := h.Streaks.ListStreakGoals(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch daily goal",
+		})
+		return
+	}
+	current := models.DefaultStreakGoal(userID)
+	if len(goals) > 0 {
+		current = goals[len(goals)-1]
+	}
+
+	timezone := current.Timezone
+	if req.Timezone != "" {
+		timezone = req.Timezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "Local" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid time zone",
+		})
+		return
+	}
+
+	// From today in the goal's time zone, but never before the goal it replaces
+	goal := models.StreakGoal{UserID: userID, GoalType: req.GoalType, Target: req.Target, Timezone: loc.String(),
+		EffectiveFrom: max(time.Now().In(loc).Format(models.DateLayout), current.EffectiveFrom)}
+
+	if err := h.Streaks.SaveStreakGoal(&goal); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to save daily goal",
+		})
+		return
+	}
+
+	h.respondStreak(c, userID, http.StatusOK, defaultStreakDays, true)
+}
+
+// CreateStreakFreeze - Freeze a day so missing the goal on it keeps the learner's streak going:
+// a missed day up to a week back, today, or a day up to 30 days ahead. Each calendar month
+// allows StreakFreezesPerMonth frozen days
+func (h *Handler) CreateStreakFreeze(c *gin.Context) {
+	userID := c.Param("userId")
+	var req StreakFreezeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+	if _, err := time.Parse(models.DateLayout, req.Date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid date: use YYYY-MM-DD",
+		})
+		return
+	}
+
+	state, err := h.loadStreak(userID, models.StreakFreezeRepairDays+1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch streak",
+		})
+		return
+	}
+
+	if req.Date < models.AddDays(state.today, -models.StreakFreezeRepairDays) ||
+		req.Date > models.AddDays(state.today, models.StreakFreezeAheadDays) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Only days from " + strconv.Itoa(models.StreakFreezeRepairDays) + " days ago to " +
+				strconv.Itoa(models.StreakFreezeAheadDays) + " days ahead can be frozen",
+		})
+		return
+	}
+	for _, freeze := range state.freezes {
+		if freeze.Date == req.Date {
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"message": "Day already frozen",
+			})
+			return
+		}
+	}
+	if day, ok := state.day(req.Date); ok && day.GoalMet {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Daily goal already met on that day",
+		})
+		return
+	}
+	if state.freezesInMonth(req.Date) >= models.StreakFreezesPerMonth {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "No streak freezes left for " + req.Date[:7],
+		})
+		return
+	}
+
+	freeze := models.StreakFreeze{UserID: userID, Date: req.Date}
+	if err := h.Streaks.CreateStreakFreeze(&freeze); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to freeze day",
+		})
+		return
+	}
+
+	h.respondStreak(c, userID, http.StatusCreated, defaultStreakDays, true)
+}
+
+// DeleteStreakFreeze - Unfreeze today or a day ahead, giving the freeze back. Past freezes
+// are spent and stay
+func (h *Handler) DeleteStreakFreeze(c *gin.Context) {
+	userID := c.Param("userId")
+	date := c.Param("date")
+	if _, err := time.Parse(models.DateLayout, date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid date: use YYYY-MM-DD",
+		})
+		return
+	}
+
+	state, _, err := h.streakToday(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch streak",
+		})
+		return
+	}
+	if date < state.today {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"message": "Freezes on past days cannot be removed",
+		})
+		return
+	}
+
+	if err := h.Streaks.DeleteStreakFreeze(userID, date); err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Day is not frozen",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to unfreeze day",
+		})
+		return
+	}
+
+	h.respondStreak(c, userID, http.StatusOK, defaultStreakDays, true)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"learning-app-backend/middleware"
+	"learning-app-backend/realtime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamKeepAlive - How often an idle stream sends a comment so proxies keep it open
+const streamKeepAlive = 20 * time.Second
+
+// StreamUserEvents - Push the learner's progress and answer events to this client over
+// Server-Sent Events, so every device they are signed in on stays in step. The stream ends
+// once the session that opened it is logged out or expires
+func (h *Handler) StreamUserEvents(c *gin.Context) {
+	userID := c.Param("userId")
+
+	// The session is checked before every event and keep-alive, as RequireAuth checks it per request
+	sessionID, authUserID := middleware.AuthSessionID(c), middleware.AuthUserID(c)
+	signedIn := func() bool {
+		_, err := h.Users.ActiveSessionRole(sessionID, authUserID)
+		return err == nil
+	}
+
+	events, cancel := h.Events.Subscribe(userID)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	c.Status(http.StatusOK)
+
+	// Events are not replayed, so clients refetch their state after (re)connecting
+	fmt.Fprint(c.Writer, "retry: 3000\n: connected\n\n")
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				// Dropped for falling behind; the client reconnects
+				return false
+			}
+			if !signedIn() {
+				return false
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+			return true
+		case <-keepAlive.C:
+			if !signedIn() {
+				return false
+			}
+			fmt.Fprint(w, ": keep-alive\n\n")
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// publish - Send an event to the learner's connected clients. Delivery is best effort: the
+// request that caused it has already succeeded
+func (h *Handler) publish(userID, eventType string, data gin.H) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	h.Events.Publish(userID, realtime.Event{Type: eventType, Data: payload})
+}
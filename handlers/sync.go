@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"encoding/json"
+	"learning-app-backend/middleware"
+	"learning-app-backend/models"
+	"learning-app-backend/store"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SyncRequest - A batch of events recorded while the client was offline
+type SyncRequest struct {
+	UserID string             `json:"user_id" binding:"required"`
+	Events []SyncEventRequest `json:"events" binding:"required,max=500,dive"`
+}
+
+// SyncEventRequest - One offline event. type is progress, heartbeat or answer and picks the
+// fields that apply, as in SaveProgressRequest, HeartbeatRequest and SubmitQuizAnswerRequest
+type SyncEventRequest struct {
+	ClientEventID string    `json:"client_event_id" binding:"required,max=255"`
+	Type          string    `json:"type" binding:"required"`
+	OccurredAt    time.Time `json:"occurred_at" binding:"required"`
+	ChapterID     uint      `json:"chapter_id" binding:"required"`
+
+	ContentType       string `json:"content_type"`
+	VideoTimestamp    *int   `json:"video_timestamp"`
+	QuizQuestionIndex *int   `json:"quiz_question_index"`
+	IsCompleted       bool   `json:"is_completed"`
+
+	Intervals []models.WatchRange `json:"intervals"`
+	Position  *float64            `json:"position"`
+
+	QuizQuestionID uint            `json:"quiz_question_id"`
+	AttemptID      uint            `json:"attempt_id"`
+	UserAnswer     json.RawMessage `json:"user_answer"`
+}
+
+// SyncResult - What became of one offline event
+type SyncResult struct {
+	ClientEventID string `json:"client_event_id"`
+	Type          string `json:"type"`
+	Status        string `json:"status"`
+	Message       string `json:"message,omitempty"`
+	Duplicate     bool   `json:"duplicate"` // Synced before; the earlier outcome is repeated
+}
+
+// SyncEvents - Apply a batch of offline progress, heartbeat and answer events in the order they
+// happened, skipping events already synced, and return the merged state
+func (h *Handler) SyncEvents(c *gin.Context) {
+	var req SyncRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if !requireSameUser(c, req.UserID) {
+		return
+	}
+
+	events := append([]SyncEventRequest(nil), req.Events...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].OccurredAt.Before(events[j].OccurredAt) })
+
+	now := time.Now()
+	results := make([]SyncResult, len(events))
+	touched := map[uint]bool{}
+	for i, event := range events {
+		// Client clocks run fast at times; nothing can have happened after now
+		occurredAt := event.OccurredAt
+		if occurredAt.After(now) {
+			occurredAt = now
+		}
+
+		// Claiming the event ID first lets only one of two overlapping retries apply the event
+		synced := &models.SyncEvent{
+			UserID:        req.UserID,
+			ClientEventID: event.ClientEventID,
+			EventType:     event.Type,
+			Status:        models.SyncPending,
+			OccurredAt:    occurredAt,
+		}
+		claimed, err := h.Progress.ClaimSyncEvent(synced)
+		if err == nil && !claimed {
+			if synced, err = h.Progress.GetSyncEvent(req.UserID, event.ClientEventID); err == nil {
+				results[i] = SyncResult{ClientEventID: synced.ClientEventID, Type: synced.EventType,
+					Status: synced.Status, Message: synced.Message, Duplicate: true}
+				continue
+			}
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Failed to fetch synced events",
+			})
+			return
+		}
+
+		result := SyncResult{ClientEventID: event.ClientEventID, Type: event.Type, Status: models.SyncApplied}
+		if err := h.applySyncEvent(req.UserID, middleware.AuthRole(c), event, occurredAt); err != nil {
+			refused, ok := err.(*requestError)
+			if !ok || refused.status >= http.StatusInternalServerError {
+				// Server errors are not remembered either, so the client can retry the event
+				h.Progress.ReleaseSyncEvent(req.UserID, event.ClientEventID)
+				respondError(c, err)
+				return
+			}
+			result.Message = refused.message
+			result.Status = models.SyncRejected
+			if refused.status == http.StatusConflict || refused.status == http.StatusTooManyRequests {
+				// A used-up attempt limit, a running cooldown, a finished attempt: the server's state wins
+				result.Status = models.SyncConflict
+			}
+		}
+		results[i] = result
+
+		// Rejected events are not remembered, so the client can fix and resend them
+		if result.Status == models.SyncRejected {
+			err = h.Progress.ReleaseSyncEvent(req.UserID, event.ClientEventID)
+		} else {
+			touched[event.ChapterID] = true
+			synced.Status = result.Status
+			synced.Message = result.Message
+			err = h.Progress.FinishSyncEvent(synced)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Failed to record synced event",
+			})
+			return
+		}
+	}
+
+	progress, err := h.Progress.ListUserProgress(req.UserID)
+	var watches []models.VideoWatch
+	if err == nil {
+		watches, err = h.Progress.ListVideoWatches(req.UserID, 0)
+	}
+	var statuses []models.ChapterStatus
+	if err == nil {
+		statuses, err = h.touchedChapterStatuses(req.UserID, touched)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch synced state",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":          true,
+		"message":          "Events synced",
+		"results":          results,
+		"progress":         progress,
+		"watches":          watches,
+		"chapter_statuses": statuses,
+	})
+}
+
+// applySyncEvent - Run an offline event through the same checks as its online endpoint
+func (h *Handler) applySyncEvent(userID, role string, event SyncEventRequest, at time.Time) error {
+	var err error
+	switch event.Type {
+	case models.SyncProgress:
+		_, err = h.saveProgress(SaveProgressRequest{
+			UserID:            userID,
+			ChapterID:         event.ChapterID,
+			ContentType:       event.ContentType,
+			VideoTimestamp:    event.VideoTimestamp,
+			QuizQuestionIndex: event.QuizQuestionIndex,
+			IsCompleted:       event.IsCompleted,
+		}, &at)
+
+	case models.SyncHeartbeat:
+		_, err = h.recordHeartbeat(HeartbeatRequest{
+			UserID:    userID,
+			ChapterID: event.ChapterID,
+			Intervals: event.Intervals,
+			Position:  event.Position,
+		}, &at)
+
+	case models.SyncAnswer:
+		if event.QuizQuestionID == 0 || len(event.UserAnswer) == 0 {
+			return refuse(http.StatusBadRequest, "quiz_question_id and user_answer are required for answer events")
+		}
+		_, err = h.submitQuizAnswer(SubmitQuizAnswerRequest{
+			UserID:         userID,
+			ChapterID:      event.ChapterID,
+			QuizQuestionID: event.QuizQuestionID,
+			AttemptID:      event.AttemptID,
+			UserAnswer:     event.UserAnswer,
+		}, role, &at)
+
+	default:
+		err = refuse(http.StatusBadRequest, "Invalid event type. Must be 'progress', 'heartbeat' or 'answer'")
+	}
+	return err
+}
+
+// mergeOfflineProgress - Fold an offline event into the saved progress row: the furthest video
+// position and quiz question win, and last_updated never moves back
+func (h *Handler) mergeOfflineProgress(progress *models.Progress, at time.Time) error {
+	records, err := h.Progress.ListChapterProgress(progress.UserID, progress.ChapterID)
+	if err != nil {
+		return err
+	}
+
+	progress.LastUpdated = at
+	for _, record := range records {
+		if record.ContentType != progress.ContentType {
+			continue
+		}
+		progress.VideoTimestamp = furthest(progress.VideoTimestamp, record.VideoTimestamp)
+		progress.QuizQuestionIndex = furthest(progress.QuizQuestionIndex, record.QuizQuestionIndex)
+		if record.LastUpdated.After(at) {
+			progress.LastUpdated = record.LastUpdated
+		}
+	}
+	return nil
+}
+
+// furthest - The larger of two optional positions
+func furthest(a, b *int) *int {
+	if a == nil || (b != nil && *b > *a) {
+		return b
+	}
+	return a
+}
+
+// touchedChapterStatuses - The learner's status for each chapter an offline batch touched
+func (h *Handler) touchedChapterStatuses(userID string, touched map[uint]bool) ([]models.ChapterStatus, error) {
+	activity, err := h.learnerActivityFor(userID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	chapterIDs := make([]uint, 0, len(touched))
+	for chapterID := range touched {
+		chapterIDs = append(chapterIDs, chapterID)
+	}
+	sort.Slice(chapterIDs, func(i, j int) bool { return chapterIDs[i] < chapterIDs[j] })
+
+	statuses := []models.ChapterStatus{}
+	for _, chapterID := range chapterIDs {
+		chapter, err := h.Chapters.GetChapter(chapterID)
+		if err == store.ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, chapterStatus(chapter, activity))
+	}
+	return statuses, nil
+}
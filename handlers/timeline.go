@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"learning-app-backend/models"
+	"learning-app-backend/store"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProgressTimeline - Replay the learner's progress log, optionally for one chapter
+// (?chapter_id=) and within a date range (?from=&to=)
+func (h *Handler) GetProgressTimeline(c *gin.Context) {
+	var chapterID uint
+	if raw := c.Query("chapter_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid chapter ID",
+			})
+			return
+		}
+		chapterID = uint(id)
+	}
+
+	h.respondProgressTimeline(c, c.Param("userId"), chapterID)
+}
+
+// GetChapterProgressTimeline - Replay the learner's progress log for one chapter
+func (h *Handler) GetChapterProgressTimeline(c *gin.Context) {
+	chapterID, ok := parseID(c, "chapterId", "chapter ID")
+	if !ok {
+		return
+	}
+
+	if _, err := h.Chapters.GetChapter(chapterID); err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Chapter not found",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+
+	h.respondProgressTimeline(c, c.Param("userId"), chapterID)
+}
+
+// respondProgressTimeline - Respond with the log events that occurred in the requested range,
+// oldest first, and the progress rows replayed from every event recorded before the end of the
+// range. Offline events are recorded after they occurred, already merged with the rows as they
+// were then, so the replay goes by when events were recorded, the order the rows were written in
+func (h *Handler) respondProgressTimeline(c *gin.Context, userID string, chapterID uint) {
+	// Dates are whole days in the learner's time zone (UTC unless ?tz= names one)
+	loc, err := time.LoadLocation(c.Query("tz"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid time zone",
+		})
+		return
+	}
+	from, ok := parseTimelineBound(c, "from", loc, false)
+	if !ok {
+		return
+	}
+	to, ok := parseTimelineBound(c, "to", loc, true)
+	if !ok {
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "from must be before to",
+		})
+		return
+	}
+
+	timeline, err := h.Progress.ListProgressEvents(store.ProgressEventFilter{UserID: userID, ChapterID: chapterID,
+		From: from, To: to})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch progress timeline",
+		})
+		return
+	}
+	recorded, err := h.Progress.ListProgressEvents(store.ProgressEventFilter{UserID: userID, ChapterID: chapterID,
+		RecordedTo: to})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch progress timeline",
+		})
+		return
+	}
+	if timeline == nil {
+		timeline = []models.ProgressEvent{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"events":   timeline,
+		"progress": models.ReplayProgress(recorded),
+	})
+}
+
+// parseTimelineBound - Read a range bound given as RFC 3339 or a date. A date starts the range
+// at its midnight and, as the end of the range, takes in the whole day
+func parseTimelineBound(c *gin.Context, param string, loc *time.Location, end bool) (time.Time, bool) {
+	raw := c.Query(param)
+	if raw == "" {
+		return time.Time{}, true
+	}
+
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, true
+	}
+	day, err := time.ParseInLocation("2006-01-02", raw, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid " + param + ": use RFC 3339 or YYYY-MM-DD",
+		})
+		return time.Time{}, false
+	}
+	if end {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, true
+}
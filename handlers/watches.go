@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"learning-app-backend/models"
+	"learning-app-backend/realtime"
+	"learning-app-backend/store"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeartbeatRequest - intervals are the stretches of the video played since the last heartbeat,
+// in seconds; position is where playback is now (defaults to the end of the last interval)
+type HeartbeatRequest struct {
+	UserID    string              `json:"user_id" binding:"required"`
+	ChapterID uint                `json:"chapter_id" binding:"required"`
+	Intervals []models.WatchRange `json:"intervals"`
+	Position  *float64            `json:"position"`
+}
+
+// RecordHeartbeat - Merge watched intervals into the learner's covered ranges for the chapter's
+// video, save the resume position and re-evaluate the chapter
+func (h *Handler) RecordHeartbeat(c *gin.Context) {
+	var req HeartbeatRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if !requireSameUser(c, req.UserID) {
+		return
+	}
+
+	result, err := h.recordHeartbeat(req, nil)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	respondResult(c, "Heartbeat recorded", result)
+}
+
+// recordHeartbeat - Merge a heartbeat's intervals and save the position, returning the watch,
+// progress and chapter status. Offline heartbeats (see SyncEvents) never move the position back
+func (h *Handler) recordHeartbeat(req HeartbeatRequest, offlineAt *time.Time) (gin.H, error) {
+	for _, r := range req.Intervals {
+		if r.Start < 0 || r.End < r.Start {
+			return nil, refuse(http.StatusBadRequest, "Each interval needs 0 <= start <= end")
+		}
+	}
+	if req.Position != nil && *req.Position < 0 {
+		return nil, refuse(http.StatusBadRequest, "position cannot be negative")
+	}
+
+	userExists, err := h.Users.UserExists(req.UserID)
+	if err != nil || !userExists {
+		return nil, refuse(http.StatusNotFound, "User not found")
+	}
+
+	chapter, err := h.Chapters.GetChapter(req.ChapterID)
+	if err != nil {
+		return nil, refuse(http.StatusNotFound, "Chapter not found")
+	}
+
+	video, err := h.Chapters.GetChapterVideo(chapter.ID)
+	if err == store.ErrNotFound {
+		return nil, refuse(http.StatusNotFound, "Video not found for this chapter")
+	} else if err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Database error")
+	}
+
+	// Merged under the watch's lock, so heartbeats arriving together each add their intervals
+	var allowance float64
+	watch, err := h.Progress.UpdateVideoWatch(req.UserID, chapter.ID, video.ID, func(watch *models.VideoWatch) {
+		// Offline heartbeats count from when they happened, but never before the previous heartbeat,
+		// so a batch cannot claim more video than the time since the server last saw one
+		heartbeatAt := time.Now()
+		if offlineAt != nil {
+			heartbeatAt = *offlineAt
+			if watch.LastHeartbeatAt != nil && heartbeatAt.Before(*watch.LastHeartbeatAt) {
+				heartbeatAt = *watch.LastHeartbeatAt
+			}
+		}
+
+		previousPosition := watch.LastPosition
+		allowance = watch.HeartbeatAllowance(heartbeatAt)
+		watch.Watch(req.Intervals, video.DurationSeconds, allowance)
+		watch.LastHeartbeatAt = &heartbeatAt
+		if req.Position != nil {
+			watch.LastPosition = *req.Position
+		} else if n := len(req.Intervals); n > 0 {
+			watch.LastPosition = req.Intervals[n-1].End
+		}
+		if video.DurationSeconds > 0 {
+			watch.LastPosition = min(watch.LastPosition, float64(video.DurationSeconds))
+		}
+		if offlineAt != nil {
+			watch.LastPosition = max(watch.LastPosition, previousPosition)
+		}
+	})
+	if err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to save watched ranges")
+	}
+
+	// The heartbeat doubles as the video's resume point
+	timestamp := int(watch.LastPosition)
+	progress := models.Progress{
+		UserID:         req.UserID,
+		ChapterID:      chapter.ID,
+		ContentType:    "video",
+		VideoTimestamp: &timestamp,
+	}
+	if offlineAt != nil {
+		if err := h.mergeOfflineProgress(&progress, *offlineAt); err != nil {
+			return nil, refuse(http.StatusInternalServerError, "Failed to fetch progress")
+		}
+	}
+	progress.IsCompleted, err = h.progressCompleted(chapter, &progress, false)
+	if err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to evaluate chapter status")
+	}
+	if err := h.Progress.SaveProgress(&progress, progressEvent(models.ProgressSourceHeartbeat, offlineAt)); err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to save progress")
+	}
+
+	status, err := h.refreshChapterStatus(req.UserID, chapter)
+	if err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to update chapter status")
+	}
+
+	played := models.PlayedSeconds(req.Intervals, video.DurationSeconds, allowance)
+	if err := h.recordActivity(req.UserID, chapter.ID, offlineAt, played); err != nil {
+		return nil, refuse(http.StatusInternalServerError, "Failed to record learning time")
+	}
+
+	result := gin.H{
+		"watch":          watch,
+		"progress":       progress,
+		"chapter_status": status,
+	}
+	h.publish(req.UserID, realtime.EventProgress, result)
+	return result, nil
+}
+
+// GetVideoWatches - Get the learner's watched ranges and percent watched for every video
+func (h *Handler) GetVideoWatches(c *gin.Context) {
+	userID := c.Param("userId")
+
+	watches, err := h.Progress.ListVideoWatches(userID, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch watched ranges",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"watches": watches,
+	})
+}
+
+// GetChapterVideoWatch - Get the learner's watched ranges for a chapter's video
+func (h *Handler) GetChapterVideoWatch(c *gin.Context) {
+	userID := c.Param("userId")
+	chapterID, ok := parseID(c, "chapterId", "chapter ID")
+	if !ok {
+		return
+	}
+
+	video, err := h.Chapters.GetChapterVideo(chapterID)
+	if err == store.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Video not found for this chapter",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Database error",
+		})
+		return
+	}
+
+	watch, err := h.Progress.GetVideoWatch(userID, video.ID)
+	if err == store.ErrNotFound {
+		// Nothing played yet
+		watch = &models.VideoWatch{UserID: userID, ChapterID: chapterID, VideoID: video.ID,
+			Ranges: []models.WatchRange{}}
+		watch.SetDuration(video.DurationSeconds)
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to fetch watched ranges",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"watch":   watch,
+	})
+}
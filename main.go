@@ -1,13 +1,14 @@
 package main
 
 import (
+	"learning-app-backend/auth"
 	"learning-app-backend/config"
 	"learning-app-backend/database"
-	"learning-app-backend/handlers"
-	"learning-app-backend/middleware"
+	"learning-app-backend/store"
 	"log"
-
-	"github.com/gin-gonic/gin"
+	"os"
+	"strconv"
+	_ "time/tzdata" // Time zones for learner-local days, even on hosts without zoneinfo
 )
 
 func main() {
@@ -22,68 +23,29 @@ func main() {
 	database.InitDatabase(cfg)
 	log.Println("Database initialized successfully")
 
-	// Create Gin router
-	router := gin.Default()
-
-	// Setup CORS
-	router.Use(middleware.SetupCORS())
-
-	// API Routes
-	api := router.Group("/api")
-	{
-		// Auth routes (Raw SQL)
-		auth := api.Group("/auth")
-		{
-			auth.POST("/login", handlers.Login)
-			auth.POST("/logout", handlers.Logout)
-			auth.GET("/user/:userId", handlers.GetUser)
-		}
-
-		// Chapter routes (Raw SQL)
-		chapters := api.Group("/chapters")
-		{
-			chapters.GET("", handlers.GetAllChapters)
-			chapters.GET("/:id", handlers.GetChapterByID)
-			chapters.GET("/:id/video", handlers.GetChapterVideo)
-			chapters.GET("/:id/quiz", handlers.GetChapterQuiz)
-			chapters.GET("/:id/content", handlers.GetChapterContent)
-		}
+	// `learnhub-server migrate <up|down [n]|status>` manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(cfg, os.Args[2:])
+		return
+	}
 
-		// Progress routes (Raw SQL)
-		progress := api.Group("/progress")
-		{
-			progress.POST("", handlers.SaveProgress)
-			progress.GET("/user/:userId", handlers.GetUserProgress)
-			progress.GET("/user/:userId/all", handlers.GetAllUserProgress)
-			progress.GET("/user/:userId/chapter/:chapterId", handlers.GetChapterProgress)
-			progress.DELETE("/user/:userId/reset", handlers.ResetProgress)
+	// Apply pending schema migrations
+	if cfg.AutoMigrate {
+		applied, err := database.MigrateUp(cfg.DatabaseType)
+		if err != nil {
+			log.Fatal("Failed to apply migrations:", err)
 		}
+		log.Printf("Schema up to date (%d migrations applied)", applied)
+	}
 
-		// Quiz Answer routes (Raw SQL) - Track quiz question history
-		quiz := api.Group("/quiz")
-		{
-			quiz.POST("/submit", handlers.SubmitQuizAnswer)
-			quiz.GET("/history/user/:userId/chapter/:chapterId", handlers.GetQuizHistory)
-			quiz.GET("/history/user/:userId", handlers.GetAllQuizHistory)
-			quiz.GET("/history/user/:userId/question/:questionId", handlers.GetQuestionAnswerHistory)
-			quiz.GET("/score/user/:userId", handlers.GetQuizScore)
-			quiz.DELETE("/history/user/:userId/clear", handlers.ClearQuizHistory)
-
-			// Get quiz with user's answer history (preserves state on reopen)
-			quiz.GET("/chapter/:id/with-history", handlers.GetChapterQuizWithHistory)
-			quiz.GET("/resume/user/:userId/chapter/:chapterId", handlers.GetQuizResumePoint)
-		}
+	// Initialize token signing
+	if cfg.JWTSecret == "" {
+		log.Fatal("JWT_SECRET must be set in production")
 	}
+	auth.InitTokens(cfg)
 
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "healthy",
-			"message": "Learning App API is running",
-			"environment": cfg.Environment,
-			"database": cfg.DatabaseType,
-		})
-	})
+	// Wire handlers to the SQL-backed stores
+	router := setupRouter(cfg, store.NewSQLStores(database.SQL))
 
 	// Start server
 	serverAddr := ":" + cfg.Port
@@ -92,3 +54,51 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// runMigrateCommand - Handle the `migrate` subcommand
+func runMigrateCommand(cfg *config.Config, args []string) {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := database.MigrateUp(cfg.DatabaseType)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Applied %d migration(s)", applied)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatal("Usage: migrate down [steps]")
+			}
+			steps = n
+		}
+		rolledBack, err := database.MigrateDown(cfg.DatabaseType, steps)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Rolled back %d migration(s)", rolledBack)
+
+	case "status":
+		statuses, err := database.GetMigrationStatus(cfg.DatabaseType)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			log.Printf("%04d_%s: %s", s.Version, s.Name, state)
+		}
+
+	default:
+		log.Fatal("Usage: migrate <up|down [steps]|status>")
+	}
+}
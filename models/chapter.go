@@ -0,0 +1,123 @@
+package models
+
+import "time"
+
+// Late answer policies for timed quizzes
+const (
+	LatePolicyReject = "reject" // Answers after the deadline are refused and the attempt is closed
+	LatePolicyFlag   = "flag"   // Answers after the deadline are graded but marked is_late
+)
+
+type Chapter struct {
+	ID                    uint      `json:"id"`
+	CourseID              uint      `json:"course_id"`
+	Title                 string    `json:"title"`
+	Description           string    `json:"description"`
+	OrderIndex            int       `json:"order_index"`
+	QuizTimeLimitSeconds  int       `json:"quiz_time_limit_seconds"` // 0 = untimed
+	QuizLatePolicy        string    `json:"quiz_late_policy"`
+	ShuffleQuestions      bool      `json:"shuffle_questions"`
+	ShuffleOptions        bool      `json:"shuffle_options"`
+	MaxAnswerAttempts     int       `json:"max_answer_attempts"`     // Per question; 0 = unlimited
+	AnswerCooldownSeconds int       `json:"answer_cooldown_seconds"` // Between answers to the same question
+	MaxQuizAttempts       int       `json:"max_quiz_attempts"`       // Quiz attempts per learner; 0 = unlimited
+	MinVideoPercent       int       `json:"min_video_percent"`       // Share of the video to watch for completion
+	PassScorePercent      int       `json:"pass_score_percent"`      // Quiz score needed to pass
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// AnswerLimit - How many times q may be answered (0 = unlimited); the question's own limit wins
+func (ch Chapter) AnswerLimit(q QuizQuestion) int {
+	if q.MaxAttempts > 0 {
+		return q.MaxAttempts
+	}
+	return ch.MaxAnswerAttempts
+}
+
+// QuizAttemptsLeft - How many more quiz attempts a learner who started `started` may begin (nil = unlimited)
+func (ch Chapter) QuizAttemptsLeft(started int) *int {
+	if ch.MaxQuizAttempts == 0 {
+		return nil
+	}
+	left := max(ch.MaxQuizAttempts-started, 0)
+	return &left
+}
+
+// AnswerRevealed - Whether a learner may see a question's correct answer and explanation: always
+// without a limit, otherwise once they answered it correctly or can no longer answer it, neither
+// in the current quiz attempt nor in one they may still start
+func AnswerRevealed(limit int, answeredCorrectly, canAnswer bool) bool {
+	return limit == 0 || answeredCorrectly || !canAnswer
+}
+
+type Video struct {
+	ID              uint      `json:"id"`
+	ChapterID       uint      `json:"chapter_id"`
+	Title           string    `json:"title"`
+	VideoURL        string    `json:"video_url"`
+	DurationSeconds int       `json:"duration_seconds"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type QuizQuestion struct {
+	ID           uint             `json:"id"`
+	ChapterID    uint             `json:"chapter_id"`
+	QuestionType string           `json:"question_type"`
+	QuestionText string           `json:"question_text"`
+	Options      []QuestionOption `json:"options,omitempty"`
+	AnswerKey    AnswerKey        `json:"answer_key"`
+	Explanation  string           `json:"explanation,omitempty"`
+	MaxAttempts  int              `json:"max_attempts,omitempty"` // 0 = the chapter's limit
+	PoolID       *uint            `json:"pool_id,omitempty"`
+	Topic        string           `json:"topic,omitempty"`
+	Difficulty   string           `json:"difficulty,omitempty"`
+	OrderIndex   int              `json:"order_index"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// LearnerQuizQuestion - Learner-facing view of a quiz question (no answer key)
+type LearnerQuizQuestion struct {
+	ID           uint             `json:"id"`
+	ChapterID    uint             `json:"chapter_id"`
+	QuestionType string           `json:"question_type"`
+	QuestionText string           `json:"question_text"`
+	Options      []QuestionOption `json:"options,omitempty"`
+	OrderIndex   int              `json:"order_index"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// LearnerView - Strip the answer key from a question
+func (q QuizQuestion) LearnerView() LearnerQuizQuestion {
+	return LearnerQuizQuestion{
+		ID:           q.ID,
+		ChapterID:    q.ChapterID,
+		QuestionType: q.QuestionType,
+		QuestionText: q.QuestionText,
+		Options:      q.DisplayOptions(),
+		OrderIndex:   q.OrderIndex,
+		CreatedAt:    q.CreatedAt,
+		UpdatedAt:    q.UpdatedAt,
+	}
+}
+
+// Prerequisite requirement types
+const (
+	PrerequisiteQuizPassed = "quiz_passed" // Latest answers to the required chapter's quiz score at least MinScorePercent
+	PrerequisiteCompleted  = "completed"   // The required chapter has been passed (see ChapterStatus)
+)
+
+// ChapterPrerequisite - ChapterID stays locked until RequiredChapterID meets the requirement
+type ChapterPrerequisite struct {
+	ID                   uint      `json:"id"`
+	ChapterID            uint      `json:"chapter_id"`
+	RequiredChapterID    uint      `json:"required_chapter_id"`
+	RequiredChapterTitle string    `json:"required_chapter_title"`
+	Type                 string    `json:"type"`
+	MinScorePercent      int       `json:"min_score_percent"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
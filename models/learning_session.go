@@ -0,0 +1,80 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// SessionIdleTimeout - A gap in activity longer than this ends a learning session. Gaps up to
+// it count as time on task
+const SessionIdleTimeout = 5 * time.Minute
+
+// LearningSession - A stretch of continuous activity by a learner on one chapter
+type LearningSession struct {
+	ID             uint      `json:"id"`
+	UserID         string    `json:"user_id"`
+	ChapterID      uint      `json:"chapter_id"`
+	StartedAt      time.Time `json:"started_at"`
+	LastActivityAt time.Time `json:"last_activity_at"`
+	ActiveSeconds  float64   `json:"active_seconds"`
+	WatchedSeconds float64   `json:"watched_seconds"` // Video played, as the heartbeats allowed it
+	ActivityCount  int       `json:"activity_count"`  // Saves, heartbeats and answers folded in
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Record - Fold a write at `at` into the session, which it must be within SessionIdleTimeout of.
+// The time since the session's last write counts as active, except for any part after since
+// (the learner's last write on any chapter), which another session already counted
+func (s *LearningSession) Record(at, since time.Time) {
+	s.ActivityCount++
+	if s.StartedAt.IsZero() {
+		s.StartedAt = at
+		s.LastActivityAt = at
+		return
+	}
+
+	from := s.LastActivityAt
+	if since.After(from) {
+		from = since
+	}
+	if at.After(from) {
+		s.ActiveSeconds += at.Sub(from).Seconds()
+	}
+	if at.After(s.LastActivityAt) {
+		s.LastActivityAt = at
+	}
+}
+
+// ChapterActiveTime - A learner's total time on task for a chapter
+type ChapterActiveTime struct {
+	ChapterID     uint      `json:"chapter_id"`
+	ChapterTitle  string    `json:"chapter_title,omitempty"`
+	ActiveSeconds float64   `json:"active_seconds"`
+	SessionCount  int       `json:"session_count"`
+	LastActiveAt  time.Time `json:"last_active_at"`
+}
+
+// SummarizeSessions - Total the sessions' active time per chapter, in chapter order
+func SummarizeSessions(sessions []LearningSession) []ChapterActiveTime {
+	byChapter := map[uint]*ChapterActiveTime{}
+	for _, session := range sessions {
+		total, ok := byChapter[session.ChapterID]
+		if !ok {
+			total = &ChapterActiveTime{ChapterID: session.ChapterID}
+			byChapter[session.ChapterID] = total
+		}
+		total.ActiveSeconds += session.ActiveSeconds
+		total.SessionCount++
+		if session.LastActivityAt.After(total.LastActiveAt) {
+			total.LastActiveAt = session.LastActivityAt
+		}
+	}
+
+	totals := make([]ChapterActiveTime, 0, len(byChapter))
+	for _, total := range byChapter {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].ChapterID < totals[j].ChapterID })
+	return totals
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Question difficulty tags ("" = untagged)
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
+
+// IsValidDifficulty - Whether d is a difficulty tag, or empty
+func IsValidDifficulty(d string) bool {
+	switch d {
+	case "", DifficultyEasy, DifficultyMedium, DifficultyHard:
+		return true
+	}
+	return false
+}
+
+// QuestionPool - A named bank of a chapter's questions that draw rules pick from
+type QuestionPool struct {
+	ID            uint      `json:"id"`
+	ChapterID     uint      `json:"chapter_id"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	QuestionCount int       `json:"question_count"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// QuizDrawRule - Draw DrawCount questions from PoolID for each attempt, optionally only
+// those tagged Topic and/or Difficulty
+type QuizDrawRule struct {
+	ID         uint      `json:"id"`
+	ChapterID  uint      `json:"chapter_id"`
+	PoolID     uint      `json:"pool_id"`
+	DrawCount  int       `json:"draw_count"`
+	Topic      string    `json:"topic,omitempty"`
+	Difficulty string    `json:"difficulty,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Matches - Whether q is a candidate for this rule
+func (r QuizDrawRule) Matches(q QuizQuestion) bool {
+	if q.PoolID == nil || *q.PoolID != r.PoolID {
+		return false
+	}
+	if r.Topic != "" && !strings.EqualFold(r.Topic, q.Topic) {
+		return false
+	}
+	return r.Difficulty == "" || r.Difficulty == q.Difficulty
+}
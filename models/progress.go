@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+type Progress struct {
+	ID                uint      `json:"id"`
+	UserID            string    `json:"user_id"`
+	ChapterID         uint      `json:"chapter_id"`
+	ContentType       string    `json:"content_type"`
+	VideoTimestamp    *int      `json:"video_timestamp,omitempty"`
+	QuizQuestionIndex *int      `json:"quiz_question_index,omitempty"`
+	IsCompleted       bool      `json:"is_completed"`
+	LastUpdated       time.Time `json:"last_updated"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// Default completion rules for chapters that do not set their own
+const (
+	DefaultMinVideoPercent  = 90
+	DefaultPassScorePercent = 70
+)
+
+// Chapter statuses, computed from progresses, quiz answers and attempts
+const (
+	ChapterNotStarted = "not_started"
+	ChapterInProgress = "in_progress"
+	ChapterPassed     = "passed"
+	ChapterFailed     = "failed"           // The quiz was finished below the pass score
+	ChapterNoAttempts = "no_attempts_left" // Failed, and every quiz attempt allowed is used up
+)
+
+// ChapterStatus - Where a learner stands against a chapter's completion rules. Percentages
+// are nil when the chapter has no video (or no quiz) to measure
+type ChapterStatus struct {
+	ChapterID        uint       `json:"chapter_id"`
+	ChapterTitle     string     `json:"chapter_title"`
+	Status           string     `json:"status"`
+	VideoPercent     *float64   `json:"video_percent,omitempty"`
+	MinVideoPercent  int        `json:"min_video_percent"`
+	VideoCompleted   bool       `json:"video_completed"`
+	QuizScorePercent *float64   `json:"quiz_score_percent,omitempty"`
+	PassScorePercent int        `json:"pass_score_percent"`
+	QuizPassed       bool       `json:"quiz_passed"`
+	QuizAttemptsLeft *int       `json:"quiz_attempts_left,omitempty"` // When the chapter limits quiz attempts
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+}
+
+// ChapterCompletion - The moment a learner passed a chapter; it stays passed afterwards
+type ChapterCompletion struct {
+	ID               uint      `json:"id"`
+	UserID           string    `json:"user_id"`
+	ChapterID        uint      `json:"chapter_id"`
+	VideoPercent     *float64  `json:"video_percent,omitempty"`
+	QuizScorePercent *float64  `json:"quiz_score_percent,omitempty"`
+	CompletedAt      time.Time `json:"completed_at"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Progress event types
+const (
+	ProgressSaved = "saved" // A progress row was written
+	ProgressReset = "reset" // The learner's progress was reset
+)
+
+// Where a saved progress event came from
+const (
+	ProgressSourceSave      = "progress"  // SaveProgress
+	ProgressSourceHeartbeat = "heartbeat" // A video heartbeat's resume point
+	ProgressSourceSync      = "sync"      // An offline event applied by SyncEvents
+)
+
+// ProgressEvent - One entry in a learner's append-only progress log. Saved events carry the
+// row as written to progresses; reset events clear every row and have no chapter
+type ProgressEvent struct {
+	ID                uint      `json:"id"`
+	UserID            string    `json:"user_id"`
+	EventType         string    `json:"event_type"`
+	Source            string    `json:"source,omitempty"`
+	ChapterID         uint      `json:"chapter_id,omitempty"`
+	ContentType       string    `json:"content_type,omitempty"`
+	VideoTimestamp    *int      `json:"video_timestamp,omitempty"`
+	QuizQuestionIndex *int      `json:"quiz_question_index,omitempty"`
+	IsCompleted       bool      `json:"is_completed"`
+	OccurredAt        time.Time `json:"occurred_at"` // When the learner did it (earlier than created_at for offline events)
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// ReplayProgress - Rebuild progress rows from log events in the order they were recorded, the
+// way progresses is kept: each saved event overwrites its (chapter, content type) row and a
+// reset clears them all. Rows come back by chapter, then content type
+func ReplayProgress(events []ProgressEvent) []Progress {
+	ordered := append([]ProgressEvent(nil), events...)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ID < ordered[j].ID })
+
+	type key struct {
+		chapterID   uint
+		contentType string
+	}
+	rows := map[key]*Progress{}
+	for _, event := range ordered {
+		if event.EventType == ProgressReset {
+			rows = map[key]*Progress{}
+			continue
+		}
+
+		k := key{event.ChapterID, event.ContentType}
+		row, ok := rows[k]
+		if !ok {
+			row = &Progress{UserID: event.UserID, ChapterID: event.ChapterID, ContentType: event.ContentType,
+				CreatedAt: event.CreatedAt}
+			rows[k] = row
+		}
+		row.VideoTimestamp = event.VideoTimestamp
+		row.QuizQuestionIndex = event.QuizQuestionIndex
+		row.IsCompleted = event.IsCompleted
+		if event.OccurredAt.After(row.LastUpdated) {
+			row.LastUpdated = event.OccurredAt
+		}
+		row.UpdatedAt = event.CreatedAt
+	}
+
+	progress := make([]Progress, 0, len(rows))
+	for _, row := range rows {
+		progress = append(progress, *row)
+	}
+	sort.Slice(progress, func(i, j int) bool {
+		if progress[i].ChapterID != progress[j].ChapterID {
+			return progress[i].ChapterID < progress[j].ChapterID
+		}
+		return progress[i].ContentType < progress[j].ContentType
+	})
+	return progress
+}
@@ -0,0 +1,369 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Question types
+const (
+	QuestionMultipleChoice = "multiple_choice" // Exactly one option is correct
+	QuestionTrueFalse      = "true_false"      // Options "true" and "false", one of them correct
+	QuestionMultiSelect    = "multi_select"    // Every correct option must be chosen, and nothing else
+	QuestionNumeric        = "numeric"         // Any number within Tolerance of Number
+	QuestionShortText      = "short_text"      // Any accepted answer, ignoring case and extra whitespace
+	QuestionOrdering       = "ordering"        // Every option, in the correct sequence
+)
+
+// MaxQuestionOptions - Upper bound on options per question
+const MaxQuestionOptions = 26
+
+// QuestionOption - One option of a choice or ordering question; learners submit the key.
+// Feedback explains the option and is only shown once the question has been answered.
+type QuestionOption struct {
+	Key      string `json:"key"`
+	Text     string `json:"text"`
+	Feedback string `json:"feedback,omitempty"`
+}
+
+// AnswerKey - What counts as correct; the fields used depend on the question type
+type AnswerKey struct {
+	OptionKeys      []string `json:"option_keys,omitempty"`      // multiple_choice, true_false, multi_select, ordering
+	Number          *float64 `json:"number,omitempty"`           // numeric
+	Tolerance       float64  `json:"tolerance,omitempty"`        // numeric
+	AcceptedAnswers []string `json:"accepted_answers,omitempty"` // short_text
+}
+
+// TrueFalseOptions - The fixed options of a true/false question
+func TrueFalseOptions() []QuestionOption {
+	return []QuestionOption{{Key: "true", Text: "True"}, {Key: "false", Text: "False"}}
+}
+
+// IsValidQuestionType - Whether t is one of the supported question types
+func IsValidQuestionType(t string) bool {
+	switch t {
+	case QuestionMultipleChoice, QuestionTrueFalse, QuestionMultiSelect,
+		QuestionNumeric, QuestionShortText, QuestionOrdering:
+		return true
+	}
+	return false
+}
+
+// usesOptions - Whether answers to this question are option keys
+func (q QuizQuestion) usesOptions() bool {
+	return q.QuestionType != QuestionNumeric && q.QuestionType != QuestionShortText
+}
+
+// optionKey - The stored spelling of key, matched case-insensitively
+func (q QuizQuestion) optionKey(key string) (string, bool) {
+	key = strings.TrimSpace(key)
+	for _, option := range q.Options {
+		if strings.EqualFold(option.Key, key) {
+			return option.Key, true
+		}
+	}
+	return "", false
+}
+
+// optionPosition - Index of key in Options (-1 when missing)
+func (q QuizQuestion) optionPosition(key string) int {
+	for i, option := range q.Options {
+		if option.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// DisplayOptions - Options as shown to learners before answering, without feedback; ordering
+// questions are sorted by text so the stored order gives nothing away
+func (q QuizQuestion) DisplayOptions() []QuestionOption {
+	return WithoutFeedback(q.ReviewOptions())
+}
+
+// WithoutFeedback - A copy of options with their feedback removed
+func WithoutFeedback(options []QuestionOption) []QuestionOption {
+	stripped := append([]QuestionOption(nil), options...)
+	for i := range stripped {
+		stripped[i].Feedback = ""
+	}
+	return stripped
+}
+
+// ReviewOptions - Options as shown once the learner has answered: the display order, with feedback
+func (q QuizQuestion) ReviewOptions() []QuestionOption {
+	options := append([]QuestionOption(nil), q.Options...)
+	if q.QuestionType == QuestionOrdering {
+		sort.SliceStable(options, func(i, j int) bool { return options[i].Text < options[j].Text })
+	}
+	return options
+}
+
+// AnswerFeedback - The chosen options of a choice answer (stored form) that carry feedback;
+// ordering and free-form questions have none
+func (q QuizQuestion) AnswerFeedback(answer string) []QuestionOption {
+	feedback := []QuestionOption{}
+	switch q.QuestionType {
+	case QuestionMultipleChoice, QuestionTrueFalse, QuestionMultiSelect:
+	default:
+		return feedback
+	}
+	for _, key := range strings.Split(answer, ",") {
+		if i := q.optionPosition(key); i >= 0 && q.Options[i].Feedback != "" {
+			feedback = append(feedback, q.Options[i])
+		}
+	}
+	return feedback
+}
+
+// Validate - Check the options and answer key fit the question type ("" when valid)
+func (q QuizQuestion) Validate() string {
+	if !IsValidQuestionType(q.QuestionType) {
+		return "question_type must be one of: multiple_choice, true_false, multi_select, numeric, short_text, ordering"
+	}
+
+	if !q.usesOptions() {
+		if len(q.Options) > 0 {
+			return q.QuestionType + " questions do not take options"
+		}
+		if q.QuestionType == QuestionNumeric {
+			if q.AnswerKey.Number == nil {
+				return "correct_answer must be a number"
+			}
+			if q.AnswerKey.Tolerance < 0 {
+				return "tolerance cannot be negative"
+			}
+			return ""
+		}
+		if len(q.AnswerKey.AcceptedAnswers) == 0 {
+			return "correct_answer must list at least one accepted answer"
+		}
+		for _, accepted := range q.AnswerKey.AcceptedAnswers {
+			if normalizeText(accepted) == "" {
+				return "accepted answers cannot be empty"
+			}
+		}
+		return ""
+	}
+
+	if msg := q.ValidateOptions(); msg != "" {
+		return msg
+	}
+
+	keys := q.AnswerKey.OptionKeys
+	for _, key := range keys {
+		if q.optionPosition(key) < 0 {
+			return "correct_answer must only use the question's option keys"
+		}
+	}
+	switch q.QuestionType {
+	case QuestionMultipleChoice, QuestionTrueFalse:
+		if len(keys) != 1 {
+			return "correct_answer must be exactly one option key"
+		}
+	case QuestionMultiSelect:
+		if len(keys) == 0 {
+			return "correct_answer must list at least one option key"
+		}
+	case QuestionOrdering:
+		if len(keys) != len(q.Options) {
+			return "correct_answer must list every option key in order"
+		}
+	}
+	return ""
+}
+
+// ValidateOptions - Check a choice or ordering question's options ("" when valid)
+func (q QuizQuestion) ValidateOptions() string {
+	if len(q.Options) < 2 {
+		return "at least two options are required"
+	}
+	if len(q.Options) > MaxQuestionOptions {
+		return fmt.Sprintf("at most %d options are allowed", MaxQuestionOptions)
+	}
+	seen := map[string]bool{}
+	for _, option := range q.Options {
+		key := strings.ToLower(option.Key)
+		if option.Key == "" || strings.Contains(option.Key, ",") {
+			return "option keys must be non-empty and cannot contain commas"
+		}
+		if seen[key] {
+			return "option keys must be unique"
+		}
+		if strings.TrimSpace(option.Text) == "" {
+			return "option text cannot be empty"
+		}
+		seen[key] = true
+	}
+	return ""
+}
+
+// NormalizeAnswer - Parse a submitted answer into the form stored in user_answer
+//
+// Choice answers are option keys ("B"), multi-select and ordering answers are lists of
+// keys (["A","C"]), numeric answers are numbers and short-text answers are strings.
+// Multi-select keys are stored in option order, ordering keys in submitted order, both
+// comma-joined.
+func (q QuizQuestion) NormalizeAnswer(raw json.RawMessage) (string, error) {
+	switch q.QuestionType {
+	case QuestionMultipleChoice:
+		var key string
+		if err := json.Unmarshal(raw, &key); err != nil {
+			return "", errors.New("answer must be an option key")
+		}
+		return q.normalizeKey(key)
+
+	case QuestionTrueFalse:
+		var value bool
+		if err := json.Unmarshal(raw, &value); err == nil {
+			return strconv.FormatBool(value), nil
+		}
+		var key string
+		if err := json.Unmarshal(raw, &key); err != nil {
+			return "", errors.New("answer must be true or false")
+		}
+		return q.normalizeKey(key)
+
+	case QuestionMultiSelect, QuestionOrdering:
+		var keys []string
+		if err := json.Unmarshal(raw, &keys); err != nil || len(keys) == 0 {
+			return "", errors.New("answer must be a non-empty list of option keys")
+		}
+		normalized := make([]string, 0, len(keys))
+		seen := map[string]bool{}
+		for _, key := range keys {
+			k, err := q.normalizeKey(key)
+			if err != nil {
+				return "", err
+			}
+			if seen[k] {
+				return "", errors.New("answer lists an option more than once")
+			}
+			seen[k] = true
+			normalized = append(normalized, k)
+		}
+		if q.QuestionType == QuestionOrdering {
+			if len(normalized) != len(q.Options) {
+				return "", errors.New("answer must list every option")
+			}
+		} else {
+			sort.Slice(normalized, func(i, j int) bool {
+				return q.optionPosition(normalized[i]) < q.optionPosition(normalized[j])
+			})
+		}
+		return strings.Join(normalized, ","), nil
+
+	case QuestionNumeric:
+		var value float64
+		if err := json.Unmarshal(raw, &value); err != nil {
+			var text string
+			if err := json.Unmarshal(raw, &text); err != nil {
+				return "", errors.New("answer must be a number")
+			}
+			if value, err = strconv.ParseFloat(strings.TrimSpace(text), 64); err != nil {
+				return "", errors.New("answer must be a number")
+			}
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return "", errors.New("answer must be a number")
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+
+	case QuestionShortText:
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return "", errors.New("answer must be text")
+		}
+		text = normalizeText(text)
+		if text == "" {
+			return "", errors.New("answer cannot be empty")
+		}
+		return text, nil
+	}
+	return "", errors.New("unsupported question type")
+}
+
+// normalizeKey - Resolve one submitted option key
+func (q QuizQuestion) normalizeKey(key string) (string, error) {
+	k, ok := q.optionKey(key)
+	if !ok {
+		keys := make([]string, 0, len(q.Options))
+		for _, option := range q.Options {
+			keys = append(keys, option.Key)
+		}
+		return "", errors.New("must be one of: " + strings.Join(keys, ", "))
+	}
+	return k, nil
+}
+
+// IsCorrect - Grade an answer already passed through NormalizeAnswer
+func (q QuizQuestion) IsCorrect(answer string) bool {
+	switch q.QuestionType {
+	case QuestionMultipleChoice, QuestionTrueFalse, QuestionOrdering:
+		return answer == strings.Join(q.AnswerKey.OptionKeys, ",")
+
+	case QuestionMultiSelect:
+		chosen := strings.Split(answer, ",")
+		if len(chosen) != len(q.AnswerKey.OptionKeys) {
+			return false
+		}
+		correct := map[string]bool{}
+		for _, key := range q.AnswerKey.OptionKeys {
+			correct[key] = true
+		}
+		// Each correct key counts once, so a repeated key cannot stand in for a missing one
+		for _, key := range chosen {
+			if !correct[key] {
+				return false
+			}
+			delete(correct, key)
+		}
+		return true
+
+	case QuestionNumeric:
+		value, err := strconv.ParseFloat(answer, 64)
+		if err != nil || q.AnswerKey.Number == nil {
+			return false
+		}
+		// Small epsilon so e.g. 0.1+0.2 style rounding never fails an exact answer
+		return math.Abs(value-*q.AnswerKey.Number) <= q.AnswerKey.Tolerance+1e-9
+
+	case QuestionShortText:
+		for _, accepted := range q.AnswerKey.AcceptedAnswers {
+			if strings.EqualFold(normalizeText(accepted), normalizeText(answer)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CorrectAnswerText - The answer key in the same form as user_answer, for display
+func (q QuizQuestion) CorrectAnswerText() string {
+	switch q.QuestionType {
+	case QuestionNumeric:
+		if q.AnswerKey.Number == nil {
+			return ""
+		}
+		text := strconv.FormatFloat(*q.AnswerKey.Number, 'f', -1, 64)
+		if q.AnswerKey.Tolerance > 0 {
+			text += " ± " + strconv.FormatFloat(q.AnswerKey.Tolerance, 'f', -1, 64)
+		}
+		return text
+	case QuestionShortText:
+		if len(q.AnswerKey.AcceptedAnswers) == 0 {
+			return ""
+		}
+		return q.AnswerKey.AcceptedAnswers[0]
+	}
+	return strings.Join(q.AnswerKey.OptionKeys, ",")
+}
+
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
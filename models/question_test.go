@@ -0,0 +1,118 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func choiceQuestion(questionType string, keys ...string) QuizQuestion {
+	return QuizQuestion{
+		QuestionType: questionType,
+		Options:      []QuestionOption{{Key: "A", Text: "One"}, {Key: "B", Text: "Two"}, {Key: "C", Text: "Three"}},
+		AnswerKey:    AnswerKey{OptionKeys: keys},
+	}
+}
+
+func TestNormalizeAndGradeAnswers(t *testing.T) {
+	pi := 3.14
+	numeric := QuizQuestion{QuestionType: QuestionNumeric, AnswerKey: AnswerKey{Number: &pi, Tolerance: 0.01}}
+	shortText := QuizQuestion{QuestionType: QuestionShortText,
+		AnswerKey: AnswerKey{AcceptedAnswers: []string{"Hello  World", "hi"}}}
+	trueFalse := QuizQuestion{QuestionType: QuestionTrueFalse, Options: TrueFalseOptions(),
+		AnswerKey: AnswerKey{OptionKeys: []string{"true"}}}
+
+	tests := []struct {
+		name     string
+		question QuizQuestion
+		raw      string
+		stored   string // NormalizeAnswer's result
+		correct  bool
+		wantErr  bool
+	}{
+		{"choice", choiceQuestion(QuestionMultipleChoice, "B"), `"B"`, "B", true, false},
+		{"choice ignores case and spaces", choiceQuestion(QuestionMultipleChoice, "B"), `" b "`, "B", true, false},
+		{"choice wrong", choiceQuestion(QuestionMultipleChoice, "B"), `"A"`, "A", false, false},
+		{"choice unknown key", choiceQuestion(QuestionMultipleChoice, "B"), `"D"`, "", false, true},
+		{"choice not a string", choiceQuestion(QuestionMultipleChoice, "B"), `2`, "", false, true},
+
+		{"true/false bool", trueFalse, `true`, "true", true, false},
+		{"true/false key", trueFalse, `"TRUE"`, "true", true, false},
+		{"true/false wrong", trueFalse, `false`, "false", false, false},
+		{"true/false malformed", trueFalse, `"maybe"`, "", false, true},
+		{"true/false number", trueFalse, `1`, "", false, true},
+
+		{"multi-select", choiceQuestion(QuestionMultiSelect, "A", "C"), `["A","C"]`, "A,C", true, false},
+		{"multi-select in any order", choiceQuestion(QuestionMultiSelect, "A", "C"), `["c","a"]`, "A,C", true, false},
+		{"multi-select missing one", choiceQuestion(QuestionMultiSelect, "A", "C"), `["A"]`, "A", false, false},
+		{"multi-select one too many", choiceQuestion(QuestionMultiSelect, "A", "C"), `["A","B","C"]`, "A,B,C", false, false},
+		{"multi-select empty", choiceQuestion(QuestionMultiSelect, "A", "C"), `[]`, "", false, true},
+		{"multi-select repeated key", choiceQuestion(QuestionMultiSelect, "A", "C"), `["A","a"]`, "", false, true},
+		{"multi-select unknown key", choiceQuestion(QuestionMultiSelect, "A", "C"), `["A","Z"]`, "", false, true},
+		{"multi-select not a list", choiceQuestion(QuestionMultiSelect, "A", "C"), `"A,C"`, "", false, true},
+
+		{"numeric exact", numeric, `3.14`, "3.14", true, false},
+		{"numeric at tolerance", numeric, `3.15`, "3.15", true, false},
+		{"numeric below tolerance", numeric, `3.13`, "3.13", true, false},
+		{"numeric outside tolerance", numeric, `3.16`, "3.16", false, false},
+		{"numeric as text", numeric, `" 3.140 "`, "3.14", true, false},
+		{"numeric malformed text", numeric, `"pi"`, "", false, true},
+		{"numeric bool", numeric, `true`, "", false, true},
+
+		{"short text", shortText, `"hello world"`, "hello world", true, false},
+		{"short text collapses whitespace", shortText, `"  HELLO \t world "`, "HELLO world", true, false},
+		{"short text second answer", shortText, `"Hi"`, "Hi", true, false},
+		{"short text wrong", shortText, `"hello"`, "hello", false, false},
+		{"short text blank", shortText, `"   "`, "", false, true},
+		{"short text not a string", shortText, `42`, "", false, true},
+
+		{"ordering", choiceQuestion(QuestionOrdering, "C", "A", "B"), `["C","A","B"]`, "C,A,B", true, false},
+		{"ordering wrong sequence", choiceQuestion(QuestionOrdering, "C", "A", "B"), `["A","B","C"]`, "A,B,C", false, false},
+		{"ordering missing option", choiceQuestion(QuestionOrdering, "C", "A", "B"), `["C","A"]`, "", false, true},
+		{"ordering repeated option", choiceQuestion(QuestionOrdering, "C", "A", "B"), `["C","A","A"]`, "", false, true},
+
+		{"unknown type", QuizQuestion{QuestionType: "essay"}, `"text"`, "", false, true},
+		{"malformed JSON", choiceQuestion(QuestionMultipleChoice, "B"), `{`, "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, err := tt.question.NormalizeAnswer(json.RawMessage(tt.raw))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NormalizeAnswer(%s) = %q, want an error", tt.raw, stored)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeAnswer(%s) failed: %v", tt.raw, err)
+			}
+			if stored != tt.stored {
+				t.Errorf("NormalizeAnswer(%s) = %q, want %q", tt.raw, stored, tt.stored)
+			}
+			if got := tt.question.IsCorrect(stored); got != tt.correct {
+				t.Errorf("IsCorrect(%q) = %v, want %v", stored, got, tt.correct)
+			}
+		})
+	}
+}
+
+func TestIsCorrectRejectsMalformedStoredAnswers(t *testing.T) {
+	pi := 3.14
+	tests := []struct {
+		name     string
+		question QuizQuestion
+		answer   string
+	}{
+		{"numeric text", QuizQuestion{QuestionType: QuestionNumeric, AnswerKey: AnswerKey{Number: &pi}}, "pi"},
+		{"numeric without key", QuizQuestion{QuestionType: QuestionNumeric}, "3.14"},
+		{"multi-select empty", choiceQuestion(QuestionMultiSelect, "A"), ""},
+		{"multi-select repeated key", choiceQuestion(QuestionMultiSelect, "A", "C"), "A,A"},
+		{"unknown type", QuizQuestion{QuestionType: "essay"}, "anything"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.question.IsCorrect(tt.answer) {
+				t.Errorf("IsCorrect(%q) = true, want false", tt.answer)
+			}
+		})
+	}
+}
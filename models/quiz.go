@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+type QuizAnswer struct {
+	ID             uint      `json:"id"`
+	UserID         string    `json:"user_id"`
+	ChapterID      uint      `json:"chapter_id"`
+	QuizQuestionID uint      `json:"quiz_question_id"`
+	AttemptID      *uint     `json:"attempt_id,omitempty"`
+	UserAnswer     string    `json:"user_answer"`
+	IsCorrect      bool      `json:"is_correct"`
+	IsLate         bool      `json:"is_late"`
+	AnsweredAt     time.Time `json:"answered_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type QuizAnswerWithDetails struct {
+	QuizAnswer
+	QuestionType  string           `json:"question_type"`
+	QuestionText  string           `json:"question_text"`
+	CorrectAnswer string           `json:"correct_answer,omitempty"` // Withheld until the answer is revealed
+	Explanation   string           `json:"explanation,omitempty"`
+	Options       []QuestionOption `json:"options,omitempty"`  // With each option's feedback
+	Feedback      []QuestionOption `json:"feedback,omitempty"` // The chosen options that carry feedback
+}
+
+// QuizHistorySummary - Per-chapter score; totals count only the latest answer to each question
+type QuizHistorySummary struct {
+	ChapterID          uint     `json:"chapter_id"`
+	ChapterTitle       string   `json:"chapter_title"`
+	TotalAnswered      int      `json:"total_answered"`
+	TotalCorrect       int      `json:"total_correct"`
+	TotalWrong         int      `json:"total_wrong"`
+	Percentage         float64  `json:"percentage"`
+	AttemptsFinished   int      `json:"attempts_finished"`
+	BestScorePercent   *float64 `json:"best_score_percent,omitempty"`
+	LatestScorePercent *float64 `json:"latest_score_percent,omitempty"`
+}
+
+// Quiz attempt statuses
+const (
+	AttemptInProgress = "in_progress"
+	AttemptFinished   = "finished"
+)
+
+// QuizAttempt - One sitting of a chapter's quiz; the score is stored when it is finished
+type QuizAttempt struct {
+	ID             uint       `json:"id"`
+	UserID         string     `json:"user_id"`
+	ChapterID      uint       `json:"chapter_id"`
+	Status         string     `json:"status"`
+	TotalQuestions int        `json:"total_questions"`
+	CorrectAnswers int        `json:"correct_answers"`
+	ScorePercent   float64    `json:"score_percent"`
+	StartedAt      time.Time  `json:"started_at"`
+	DeadlineAt     *time.Time `json:"deadline_at,omitempty"` // Only for timed quizzes
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// QuizAttemptWithAnswers - An attempt and every answer submitted into it, newest first
+type QuizAttemptWithAnswers struct {
+	QuizAttempt
+	Answers []QuizAnswerWithDetails `json:"answers"`
+}
+
+type QuizQuestionWithUserAnswer struct {
+	ID           uint             `json:"id"`
+	ChapterID    uint             `json:"chapter_id"`
+	QuestionType string           `json:"question_type"`
+	QuestionText string           `json:"question_text"`
+	Options      []QuestionOption `json:"options,omitempty"`
+	OrderIndex   int              `json:"order_index"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+
+	// User's answer history (if exists)
+	HasAnswered    bool       `json:"has_answered"`
+	UserAnswer     *string    `json:"user_answer,omitempty"`
+	IsCorrect      *bool      `json:"is_correct,omitempty"`
+	AnsweredAt     *time.Time `json:"answered_at,omitempty"`
+	CorrectAnswer  *string    `json:"correct_answer,omitempty"` // Only shown once the answer is revealed
+	Explanation    string     `json:"explanation,omitempty"`    // Only shown once the answer is revealed
+	TimesAttempted int        `json:"times_attempted"`
+
+	// Attempt limit, when the question has one
+	MaxAttempts       int  `json:"max_attempts,omitempty"`
+	AttemptsRemaining *int `json:"attempts_remaining,omitempty"`
+}
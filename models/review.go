@@ -0,0 +1,103 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// SM-2 scheduling constants
+const (
+	DefaultEaseFactor = 2.5
+	MinEaseFactor     = 1.3
+	PassingQuality    = 3 // Recall quality (0-5) from which a review counts as remembered
+	CorrectQuality    = 4 // Quality of a correct answer without a self-rating
+	WrongQuality      = 1 // Quality of a wrong answer without a self-rating
+)
+
+// ReviewItem - A user's spaced-repetition schedule for one answered question
+type ReviewItem struct {
+	ID             uint       `json:"id"`
+	UserID         string     `json:"user_id"`
+	ChapterID      uint       `json:"chapter_id"`
+	QuizQuestionID uint       `json:"quiz_question_id"`
+	EaseFactor     float64    `json:"ease_factor"`
+	IntervalDays   int        `json:"interval_days"`
+	Repetitions    int        `json:"repetitions"` // Successful reviews in a row
+	Lapses         int        `json:"lapses"`      // Times a remembered question was forgotten
+	DueAt          time.Time  `json:"due_at"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// NewReviewItem - A schedule for a question the user has just answered for the first time
+func NewReviewItem(userID string, chapterID, questionID uint) ReviewItem {
+	return ReviewItem{
+		UserID:         userID,
+		ChapterID:      chapterID,
+		QuizQuestionID: questionID,
+		EaseFactor:     DefaultEaseFactor,
+	}
+}
+
+// RecallQuality - SM-2 quality of an answer; rating is the learner's optional self-rating,
+// kept within the passing (3-5) or failing (0-2) range the answer's correctness allows
+func RecallQuality(isCorrect bool, rating *int) int {
+	if isCorrect {
+		if rating == nil {
+			return CorrectQuality
+		}
+		return min(max(*rating, PassingQuality), 5)
+	}
+	if rating == nil {
+		return WrongQuality
+	}
+	return min(max(*rating, 0), PassingQuality-1)
+}
+
+// Schedule - Apply one SM-2 review of the given quality at time at. A forgotten question
+// starts over and is due again right away; a remembered one waits 1, 6, then
+// interval × ease factor days
+func (r *ReviewItem) Schedule(quality int, at time.Time) {
+	if quality >= PassingQuality {
+		switch r.Repetitions {
+		case 0:
+			r.IntervalDays = 1
+		case 1:
+			r.IntervalDays = 6
+		default:
+			r.IntervalDays = int(math.Round(float64(r.IntervalDays) * r.EaseFactor))
+		}
+		r.Repetitions++
+	} else {
+		if r.Repetitions > 0 {
+			r.Lapses++
+		}
+		r.Repetitions = 0
+		r.IntervalDays = 0
+	}
+
+	miss := float64(5 - quality)
+	r.EaseFactor = math.Max(MinEaseFactor, r.EaseFactor+0.1-miss*(0.08+miss*0.02))
+	r.DueAt = at.AddDate(0, 0, r.IntervalDays)
+	r.LastReviewedAt = &at
+}
+
+// ReviewAnswer - An answer given in a review session (never counted in chapter quiz scores)
+type ReviewAnswer struct {
+	ID             uint      `json:"id"`
+	ReviewItemID   uint      `json:"review_item_id"`
+	UserID         string    `json:"user_id"`
+	QuizQuestionID uint      `json:"quiz_question_id"`
+	UserAnswer     string    `json:"user_answer"`
+	IsCorrect      bool      `json:"is_correct"`
+	Quality        int       `json:"quality"`
+	ReviewedAt     time.Time `json:"reviewed_at"`
+}
+
+// DueReview - A due review item with the question to ask (no answer key)
+type DueReview struct {
+	ReviewItem
+	ChapterTitle string              `json:"chapter_title"`
+	Question     LearnerQuizQuestion `json:"question"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRecallQuality(t *testing.T) {
+	rating := func(r int) *int { return &r }
+	tests := []struct {
+		name      string
+		isCorrect bool
+		rating    *int
+		want      int
+	}{
+		{"correct without rating", true, nil, CorrectQuality},
+		{"correct rated perfect", true, rating(5), 5},
+		{"correct rated as a fail", true, rating(1), PassingQuality},
+		{"correct rated above the scale", true, rating(9), 5},
+		{"wrong without rating", false, nil, WrongQuality},
+		{"wrong rated as a pass", false, rating(5), PassingQuality - 1},
+		{"wrong rated below the scale", false, rating(-3), 0},
+		{"wrong rated blackout", false, rating(0), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RecallQuality(tt.isCorrect, tt.rating); got != tt.want {
+				t.Errorf("RecallQuality(%v, %v) = %d, want %d", tt.isCorrect, tt.rating, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleSM2(t *testing.T) {
+	at := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		before   ReviewItem
+		quality  int
+		interval int
+		reps     int
+		lapses   int
+		ease     float64
+	}{
+		{"first success", ReviewItem{EaseFactor: 2.5}, 4, 1, 1, 0, 2.5},
+		{"second success", ReviewItem{EaseFactor: 2.5, IntervalDays: 1, Repetitions: 1}, 4, 6, 2, 0, 2.5},
+		{"third success multiplies by ease", ReviewItem{EaseFactor: 2.5, IntervalDays: 6, Repetitions: 2}, 4, 15, 3, 0, 2.5},
+		{"interval rounds", ReviewItem{EaseFactor: 2.5, IntervalDays: 15, Repetitions: 3}, 5, 38, 4, 0, 2.6},
+		{"perfect recall raises ease", ReviewItem{EaseFactor: 2.5, IntervalDays: 1, Repetitions: 1}, 5, 6, 2, 0, 2.6},
+		{"hard recall lowers ease", ReviewItem{EaseFactor: 2.5, IntervalDays: 6, Repetitions: 2}, 3, 15, 3, 0, 2.36},
+		{"lapse starts over", ReviewItem{EaseFactor: 2.5, IntervalDays: 15, Repetitions: 3}, 1, 0, 0, 1, 1.96},
+		{"fail before any success is no lapse", ReviewItem{EaseFactor: 2.5}, 2, 0, 0, 0, 2.18},
+		{"lapses accumulate", ReviewItem{EaseFactor: 2.0, IntervalDays: 6, Repetitions: 2, Lapses: 2}, 0, 0, 0, 3, 1.3},
+		{"ease never drops below the floor", ReviewItem{EaseFactor: 1.4, IntervalDays: 1, Repetitions: 1}, 3, 6, 2, 0, MinEaseFactor},
+		{"floored ease still grows the interval", ReviewItem{EaseFactor: MinEaseFactor, IntervalDays: 10, Repetitions: 4}, 4, 13, 5, 0, MinEaseFactor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := tt.before
+			item.Schedule(tt.quality, at)
+
+			if item.IntervalDays != tt.interval || item.Repetitions != tt.reps || item.Lapses != tt.lapses {
+				t.Errorf("interval %d, repetitions %d, lapses %d; want %d, %d, %d",
+					item.IntervalDays, item.Repetitions, item.Lapses, tt.interval, tt.reps, tt.lapses)
+			}
+			if math.Abs(item.EaseFactor-tt.ease) > 1e-9 {
+				t.Errorf("ease factor %.4f, want %.4f", item.EaseFactor, tt.ease)
+			}
+			if want := at.AddDate(0, 0, tt.interval); !item.DueAt.Equal(want) {
+				t.Errorf("due %v, want %v", item.DueAt, want)
+			}
+			if item.LastReviewedAt == nil || !item.LastReviewedAt.Equal(at) {
+				t.Errorf("last reviewed %v, want %v", item.LastReviewedAt, at)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package models
+
+import "time"
+
+// Daily goal types
+const (
+	GoalMinutes   = "minutes"   // Minutes of video watched (see LearningSession.WatchedSeconds)
+	GoalQuestions = "questions" // Quiz and review questions answered
+)
+
+// Streak rules
+const (
+	DefaultGoalType        = GoalMinutes
+	DefaultGoalTarget      = 10
+	MaxGoalMinutes         = 24 * 60
+	MaxGoalQuestions       = 1000
+	StreakFreezesPerMonth  = 2  // Freezes a learner can put on days of one calendar month
+	StreakFreezeRepairDays = 7  // How many days back a missed day can still be frozen
+	StreakFreezeAheadDays  = 30 // How far ahead a day can be frozen
+)
+
+// DateLayout - How streak days are written: a calendar date in the learner's time zone
+const DateLayout = "2006-01-02"
+
+// StreakGoal - A learner's daily goal from EffectiveFrom on, and the time zone their days are
+// counted in
+type StreakGoal struct {
+	ID            uint      `json:"-"`
+	UserID        string    `json:"user_id"`
+	GoalType      string    `json:"goal_type"`
+	Target        int       `json:"target"`
+	Timezone      string    `json:"timezone"`
+	EffectiveFrom string    `json:"effective_from,omitempty"` // YYYY-MM-DD
+	CreatedAt     time.Time `json:"-"`
+	UpdatedAt     time.Time `json:"-"`
+}
+
+// DefaultStreakGoal - The goal of a learner who never set one
+func DefaultStreakGoal(userID string) StreakGoal {
+	return StreakGoal{UserID: userID, GoalType: DefaultGoalType, Target: DefaultGoalTarget, Timezone: "UTC"}
+}
+
+// Met - Whether a day's activity reaches the goal
+func (g StreakGoal) Met(day StreakDay) bool {
+	if g.GoalType == GoalQuestions {
+		return day.QuestionsAnswered >= g.Target
+	}
+	return day.WatchedSeconds >= float64(g.Target*60)
+}
+
+// StreakFreeze - A day the learner froze: missing their goal on it keeps the streak going
+type StreakFreeze struct {
+	ID        uint      `json:"id"`
+	UserID    string    `json:"user_id"`
+	Date      string    `json:"date"` // YYYY-MM-DD
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StreakDay - A learner's activity on one day in their time zone, judged against the goal in
+// effect that day
+type StreakDay struct {
+	Date              string  `json:"date"` // YYYY-MM-DD
+	ActiveSeconds     float64 `json:"active_seconds"`
+	WatchedSeconds    float64 `json:"watched_seconds"`
+	QuestionsAnswered int     `json:"questions_answered"`
+	GoalType          string  `json:"goal_type"`
+	Target            int     `json:"target"`
+	GoalMet           bool    `json:"goal_met"`
+	Frozen            bool    `json:"frozen"`
+}
+
+// Streak - Consecutive days the learner met their goal. Frozen days keep a streak going without
+// adding to it, and today only breaks it once it is over
+type Streak struct {
+	Current      int    `json:"current_streak"`
+	Longest      int    `json:"longest_streak"`
+	GoalMetToday bool   `json:"goal_met_today"`
+	AtRisk       bool   `json:"at_risk"` // A streak is running and today is neither met nor frozen yet
+	LastGoalDate string `json:"last_goal_date,omitempty"`
+}
+
+// StreakRecord - A learner's streak as last worked out: the longest one and the last day the goal
+// was met on or before CountedThrough. Later calendars only need the days since, plus the streak
+// running into them
+type StreakRecord struct {
+	UserID         string
+	LongestStreak  int
+	LastGoalDate   string
+	CountedThrough string // YYYY-MM-DD
+	UpdatedAt      time.Time
+}
+
+// Merge - Fold the record into a streak computed over a calendar starting after days the record
+// already counted
+func (r StreakRecord) Merge(streak Streak) Streak {
+	streak.Longest = max(streak.Longest, r.LongestStreak)
+	if streak.LastGoalDate == "" {
+		streak.LastGoalDate = r.LastGoalDate
+	}
+	return streak
+}
+
+// AddDays - The date n days after date (both YYYY-MM-DD)
+func AddDays(date string, n int) string {
+	day, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return date
+	}
+	return day.AddDate(0, 0, n).Format(DateLayout)
+}
+
+// StreakCalendar - Every day from from (or the learner's first active or frozen day, if earlier)
+// through today, each judged against the goal in effect on it. goals are oldest first; days
+// before the first one use the default goal
+func StreakCalendar(activity map[string]StreakDay, frozen map[string]bool, goals []StreakGoal, from, today string) []StreakDay {
+	first := from
+	for date := range activity {
+		if date < first {
+			first = date
+		}
+	}
+	for date := range frozen {
+		if date < first {
+			first = date
+		}
+	}
+
+	var calendar []StreakDay
+	goal := DefaultStreakGoal("")
+	next := 0
+	for date := first; date <= today; date = AddDays(date, 1) {
+		for next < len(goals) && goals[next].EffectiveFrom <= date {
+			goal = goals[next]
+			next++
+		}
+		day := activity[date]
+		day.Date = date
+		day.GoalType = goal.GoalType
+		day.Target = goal.Target
+		day.GoalMet = goal.Met(day)
+		day.Frozen = frozen[date]
+		calendar = append(calendar, day)
+	}
+	return calendar
+}
+
+// ComputeStreak - Current and longest streaks over a calendar of consecutive days ending today
+func ComputeStreak(calendar []StreakDay) Streak {
+	var streak Streak
+	for i, day := range calendar {
+		switch {
+		case day.GoalMet:
+			streak.Current++
+			streak.Longest = max(streak.Longest, streak.Current)
+			streak.LastGoalDate = day.Date
+		case day.Frozen, i == len(calendar)-1:
+			// Frozen days bridge the streak; today is not over yet
+		default:
+			streak.Current = 0
+		}
+	}
+
+	if len(calendar) > 0 {
+		today := calendar[len(calendar)-1]
+		streak.GoalMetToday = today.GoalMet
+		streak.AtRisk = streak.Current > 0 && !today.GoalMet && !today.Frozen
+	}
+	return streak
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Offline sync event types
+const (
+	SyncProgress  = "progress"
+	SyncHeartbeat = "heartbeat"
+	SyncAnswer    = "answer"
+)
+
+// Offline sync outcomes
+const (
+	SyncApplied  = "applied"
+	SyncConflict = "conflict" // Lost to the server's state, e.g. to an earlier answer
+	SyncRejected = "rejected" // Invalid or not allowed; not remembered, so it may be sent again
+	SyncPending  = "pending"  // Claimed by a request that is still applying it
+)
+
+// SyncEvent - An offline event the server has synced, remembered by the client's event ID
+type SyncEvent struct {
+	ID            uint      `json:"id"`
+	UserID        string    `json:"user_id"`
+	ClientEventID string    `json:"client_event_id"`
+	EventType     string    `json:"type"`
+	Status        string    `json:"status"`
+	Message       string    `json:"message,omitempty"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type User struct {
+	ID           uint      `json:"id"`
+	UserID       string    `json:"user_id"`
+	Username     string    `json:"username"`
+	Role         string    `json:"role"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// AuthSession - A login session; access tokens carry its ID, refresh tokens are stored hashed
+type AuthSession struct {
+	ID               uint       `json:"id"`
+	UserID           string     `json:"user_id"`
+	RefreshTokenHash string     `json:"-"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
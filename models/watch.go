@@ -0,0 +1,130 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Heartbeat limits: a heartbeat cannot add more newly watched video than could have played since
+// the heartbeat before it. The playback rate also absorbs network jitter between heartbeats; a
+// fixed allowance per heartbeat would add up over rapid-fire heartbeats
+const (
+	MaxPlaybackRate = 2.0         // Fastest playback speed players offer
+	MaxHeartbeatGap = time.Minute // Longer gaps, and the wait before a watch's first heartbeat, count as this long
+)
+
+// WatchRange - A stretch of a video played without skipping, in seconds
+type WatchRange struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// VideoWatch - The parts of a video a user has played, merged into disjoint ranges
+type VideoWatch struct {
+	ID              uint         `json:"id"`
+	UserID          string       `json:"user_id"`
+	ChapterID       uint         `json:"chapter_id"`
+	VideoID         uint         `json:"video_id"`
+	Ranges          []WatchRange `json:"watched_ranges"`
+	WatchedSeconds  float64      `json:"watched_seconds"`
+	LastPosition    float64      `json:"last_position"`
+	DurationSeconds int          `json:"duration_seconds"`
+	PercentWatched  *float64     `json:"percent_watched"` // nil when the video has no duration
+	LastHeartbeatAt *time.Time   `json:"-"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// MergeRanges - Sort ranges and join the ones that overlap or touch
+func MergeRanges(ranges []WatchRange) []WatchRange {
+	sorted := append([]WatchRange(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	merged := []WatchRange{}
+	for _, r := range sorted {
+		if n := len(merged); n > 0 && r.Start <= merged[n-1].End {
+			merged[n-1].End = max(merged[n-1].End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// HeartbeatAllowance - How many seconds of video not watched before a heartbeat at `at` may add:
+// what could have played since the previous heartbeat at the fastest playback rate
+func (w *VideoWatch) HeartbeatAllowance(at time.Time) float64 {
+	gap := MaxHeartbeatGap
+	if w.LastHeartbeatAt != nil {
+		gap = min(max(at.Sub(*w.LastHeartbeatAt), 0), MaxHeartbeatGap)
+	}
+	return gap.Seconds() * MaxPlaybackRate
+}
+
+// Watch - Merge newly played intervals into the watched ranges, cut off at the end of the
+// video when its duration is known. At most allowance seconds not watched before are added:
+// intervals count in order, and the one that runs over is cut short
+func (w *VideoWatch) Watch(intervals []WatchRange, duration int, allowance float64) {
+	for _, r := range intervals {
+		if duration > 0 {
+			r.End = min(r.End, float64(duration))
+		}
+		for _, gap := range unwatched(w.Ranges, r) {
+			if allowance <= 0 {
+				break
+			}
+			gap.End = min(gap.End, gap.Start+allowance)
+			allowance -= gap.End - gap.Start
+			w.Ranges = append(w.Ranges, gap)
+		}
+		w.Ranges = MergeRanges(w.Ranges)
+	}
+
+	w.WatchedSeconds = 0
+	for _, r := range w.Ranges {
+		w.WatchedSeconds += r.End - r.Start
+	}
+	w.SetDuration(duration)
+}
+
+// PlayedSeconds - How much video the intervals played, rewatched parts included: each cut off at
+// the end of the video when its duration is known, and all of them capped at allowance
+func PlayedSeconds(intervals []WatchRange, duration int, allowance float64) float64 {
+	played := 0.0
+	for _, r := range intervals {
+		if duration > 0 {
+			r.End = min(r.End, float64(duration))
+		}
+		played += max(r.End-r.Start, 0)
+	}
+	return min(played, allowance)
+}
+
+// unwatched - The parts of r outside the merged ranges, in order
+func unwatched(ranges []WatchRange, r WatchRange) []WatchRange {
+	var gaps []WatchRange
+	start := r.Start
+	for _, watched := range ranges {
+		if watched.Start >= r.End {
+			break
+		}
+		if watched.Start > start {
+			gaps = append(gaps, WatchRange{Start: start, End: watched.Start})
+		}
+		start = max(start, watched.End)
+	}
+	if start < r.End {
+		gaps = append(gaps, WatchRange{Start: start, End: r.End})
+	}
+	return gaps
+}
+
+// SetDuration - Measure the watched seconds against the video's duration
+func (w *VideoWatch) SetDuration(duration int) {
+	w.DurationSeconds = duration
+	w.PercentWatched = nil
+	if duration > 0 {
+		percent := min(w.WatchedSeconds/float64(duration)*100, 100)
+		w.PercentWatched = &percent
+	}
+}
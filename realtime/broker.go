@@ -0,0 +1,105 @@
+package realtime
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Event types pushed to a user's connected clients
+const (
+	EventProgress = "progress" // A progress row was saved (progress, heartbeat or offline sync)
+	EventAnswer   = "answer"   // A quiz answer was recorded
+)
+
+// Event - A change to a user's learning state. Data is already JSON so a broker can hand it
+// across processes unchanged
+type Event struct {
+	ID         uint64          `json:"id"`
+	Type       string          `json:"type"`
+	Data       json.RawMessage `json:"data"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// Broker - Fans a user's events out to every client subscribed for that user. The in-process
+// broker only reaches clients connected to this server; a shared broker (Redis, NATS, ...) can
+// take its place behind the same interface when the API runs on several instances
+type Broker interface {
+	// Publish - Deliver the event to the user's current subscribers, without blocking
+	Publish(userID string, event Event)
+
+	// Subscribe - Receive the user's events until cancel is called. The channel is closed when
+	// the subscription ends, including when the subscriber falls too far behind
+	Subscribe(userID string) (events <-chan Event, cancel func())
+}
+
+// subscriberBuffer - Events a slow client may fall behind by before it is dropped
+const subscriberBuffer = 32
+
+// MemoryBroker - Broker for a single server process
+type MemoryBroker struct {
+	mu          sync.Mutex
+	lastID      uint64
+	subscribers map[string]map[chan Event]struct{}
+}
+
+// NewMemoryBroker - Create an in-process broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{subscribers: map[string]map[chan Event]struct{}{}}
+}
+
+// Publish - Deliver the event to the user's subscribers. A subscriber whose buffer is full is
+// dropped rather than skipped, so its client reconnects and refetches instead of missing an update
+func (b *MemoryBroker) Publish(userID string, event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	for ch := range b.subscribers[userID] {
+		select {
+		case ch <- event:
+		default:
+			b.remove(userID, ch)
+		}
+	}
+}
+
+// Subscribe - Register a subscriber for the user's events
+func (b *MemoryBroker) Subscribe(userID string) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, subscriberBuffer)
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = map[chan Event]struct{}{}
+	}
+	b.subscribers[userID][ch] = struct{}{}
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.remove(userID, ch)
+		})
+	}
+	return ch, cancel
+}
+
+// remove - Close and forget a subscriber if it is still registered; callers hold b.mu
+func (b *MemoryBroker) remove(userID string, ch chan Event) {
+	subscribers := b.subscribers[userID]
+	if _, ok := subscribers[ch]; !ok {
+		return
+	}
+	delete(subscribers, ch)
+	close(ch)
+	if len(subscribers) == 0 {
+		delete(b.subscribers, userID)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"learning-app-backend/auth"
+	"learning-app-backend/config"
+	"learning-app-backend/handlers"
+	"learning-app-backend/middleware"
+	"learning-app-backend/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupRouter - Build the API router with handlers backed by the given stores
+func setupRouter(cfg *config.Config, stores *store.Stores) *gin.Engine {
+	h := handlers.New(stores)
+	requireAuth := middleware.RequireAuth(stores.Users)
+
+	// Create Gin router
+	router := gin.Default()
+
+	// Setup CORS
+	router.Use(middleware.SetupCORS())
+
+	// API Routes
+	api := router.Group("/api")
+	{
+		// Auth routes (Raw SQL)
+		authRoutes := api.Group("/auth")
+		{
+			authRoutes.POST("/register", h.Register)
+			authRoutes.POST("/login", h.Login)
+			authRoutes.POST("/refresh", h.RefreshToken)
+			authRoutes.POST("/logout", requireAuth, h.Logout)
+			authRoutes.GET("/user/:userId", requireAuth, middleware.RequireSelf(), h.GetUser)
+		}
+
+		// Course routes (Raw SQL)
+		courses := api.Group("/courses", requireAuth, middleware.RequirePermission(auth.PermContentRead))
+		{
+			courses.GET("", h.GetAllCourses)
+			courses.GET("/:id", h.GetCourseByID)
+			courses.GET("/:id/chapters", h.GetCourseChapters)
+		}
+
+		// Chapter routes (Raw SQL)
+		chapters := api.Group("/chapters", requireAuth, middleware.RequirePermission(auth.PermContentRead))
+		{
+			chapters.GET("", h.GetAllChapters)
+			chapters.GET("/:id", h.GetChapterByID)
+			chapters.GET("/:id/video", h.GetChapterVideo)
+			chapters.GET("/:id/quiz", h.GetChapterQuiz)
+			chapters.GET("/:id/content", h.GetChapterContent)
+			chapters.GET("/:id/prerequisites", h.GetChapterPrerequisites)
+
+			// Full answer key is reserved for instructors and admins
+			chapters.GET("/:id/quiz/answer-key", middleware.RequirePermission(auth.PermAnswerKeyRead), h.GetChapterAnswerKey)
+		}
+
+		// Progress routes (Raw SQL)
+		progress := api.Group("/progress", requireAuth,
+			middleware.RequirePermission(auth.PermProgressOwn), middleware.RequireSelf())
+		{
+			progress.POST("", h.SaveProgress)
+			progress.POST("/heartbeat", h.RecordHeartbeat)
+			progress.POST("/sync", h.SyncEvents)
+			progress.GET("/user/:userId", h.GetUserProgress)
+			progress.GET("/user/:userId/all", h.GetAllUserProgress)
+			progress.GET("/user/:userId/chapter/:chapterId", h.GetChapterProgress)
+			progress.GET("/user/:userId/course/:courseId", h.GetCourseProgress)
+			progress.GET("/user/:userId/status", h.GetChapterStatuses)
+			progress.GET("/user/:userId/chapter/:chapterId/status", h.GetChapterStatus)
+			progress.GET("/user/:userId/videos", h.GetVideoWatches)
+			progress.GET("/user/:userId/chapter/:chapterId/video", h.GetChapterVideoWatch)
+			progress.GET("/user/:userId/stream", h.StreamUserEvents)
+			progress.GET("/user/:userId/timeline", h.GetProgressTimeline)
+			progress.GET("/user/:userId/chapter/:chapterId/timeline", h.GetChapterProgressTimeline)
+			progress.GET("/user/:userId/activity", h.GetActivitySummary)
+			progress.GET("/user/:userId/dashboard", h.GetDashboard)
+			progress.DELETE("/user/:userId/reset", h.ResetProgress)
+		}
+
+		// Quiz Answer routes (Raw SQL) - Track quiz question history
+		quiz := api.Group("/quiz", requireAuth,
+			middleware.RequirePermission(auth.PermProgressOwn), middleware.RequireSelf())
+		{
+			quiz.POST("/submit", h.SubmitQuizAnswer)
+			quiz.GET("/history/user/:userId/chapter/:chapterId", h.GetQuizHistory)
+			quiz.GET("/history/user/:userId", h.GetAllQuizHistory)
+			quiz.GET("/history/user/:userId/question/:questionId", h.GetQuestionAnswerHistory)
+			quiz.GET("/score/user/:userId", h.GetQuizScore)
+			quiz.GET("/score/user/:userId/course/:courseId", h.GetCourseQuizScore)
+			quiz.GET("/history/user/:userId/chapter/:chapterId/attempts", h.GetQuizHistoryByAttempt)
+			quiz.DELETE("/history/user/:userId/clear", h.ClearQuizHistory)
+
+			// Quiz attempts - start, submit answers into, finish with a stored score
+			quiz.POST("/attempts/user/:userId/chapter/:chapterId", h.StartQuizAttempt)
+			quiz.GET("/attempts/user/:userId/chapter/:chapterId", h.GetQuizAttempts)
+			quiz.GET("/attempts/user/:userId/attempt/:attemptId", h.GetQuizAttempt)
+			quiz.POST("/attempts/user/:userId/attempt/:attemptId/finish", h.FinishQuizAttempt)
+
+			// Get quiz with user's answer history (preserves state on reopen)
+			quiz.GET("/chapter/:id/with-history", h.GetChapterQuizWithHistory)
+			quiz.GET("/resume/user/:userId/chapter/:chapterId", h.GetQuizResumePoint)
+		}
+
+		// Review routes (Raw SQL) - Spaced repetition of answered quiz questions
+		reviews := api.Group("/reviews", requireAuth,
+			middleware.RequirePermission(auth.PermProgressOwn), middleware.RequireSelf())
+		{
+			reviews.GET("/user/:userId/due", h.GetDueReviews)
+			reviews.POST("/submit", h.SubmitReview)
+		}
+
+		// Streak routes (Raw SQL) - Daily goals, streaks and streak freezes
+		streaks := api.Group("/streaks", requireAuth,
+			middleware.RequirePermission(auth.PermProgressOwn), middleware.RequireSelf())
+		{
+			streaks.GET("/user/:userId", h.GetStreak)
+			streaks.PUT("/user/:userId/goal", h.UpdateStreakGoal)
+			streaks.POST("/user/:userId/freezes", h.CreateStreakFreeze)
+			streaks.DELETE("/user/:userId/freezes/:date", h.DeleteStreakFreeze)
+		}
+
+		// Admin routes (Raw SQL) - Role management
+		admin := api.Group("/admin", requireAuth, middleware.RequirePermission(auth.PermRolesManage))
+		{
+			admin.GET("/roles", h.GetRoles)
+			admin.GET("/users", h.ListUsers)
+			admin.PUT("/users/:userId/role", h.UpdateUserRole)
+		}
+
+		// Content management routes (Raw SQL) - Courses, chapters, videos and quiz questions
+		content := api.Group("/admin", requireAuth, middleware.RequirePermission(auth.PermContentManage))
+		{
+			content.POST("/courses", h.CreateCourse)
+			content.PUT("/courses/:id", h.UpdateCourse)
+			content.DELETE("/courses/:id", h.DeleteCourse)
+			content.POST("/courses/:id/prerequisites/sequential", h.CreateSequentialPrerequisites)
+
+			content.POST("/chapters", h.CreateChapter)
+			content.PUT("/chapters/:id", h.UpdateChapter)
+			content.DELETE("/chapters/:id", h.DeleteChapter)
+
+			content.POST("/chapters/:id/prerequisites", h.CreatePrerequisite)
+			content.DELETE("/prerequisites/:prerequisiteId", h.DeletePrerequisite)
+
+			content.POST("/chapters/:id/video", h.CreateChapterVideo)
+			content.PUT("/videos/:videoId", h.UpdateVideo)
+			content.DELETE("/videos/:videoId", h.DeleteVideo)
+
+			content.POST("/chapters/:id/questions", h.CreateQuizQuestion)
+			content.PUT("/questions/:questionId", h.UpdateQuizQuestion)
+			content.DELETE("/questions/:questionId", h.DeleteQuizQuestion)
+
+			// Question pools and the "draw N from pool X" rules that build a chapter's quiz
+			content.GET("/chapters/:id/pools", h.GetQuestionPools)
+			content.POST("/chapters/:id/pools", h.CreateQuestionPool)
+			content.PUT("/pools/:poolId", h.UpdateQuestionPool)
+			content.DELETE("/pools/:poolId", h.DeleteQuestionPool)
+			content.GET("/chapters/:id/draw-rules", h.GetDrawRules)
+			content.PUT("/chapters/:id/draw-rules", h.ReplaceDrawRules)
+		}
+	}
+
+	// Health check endpoint
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"status":      "healthy",
+			"message":     "Learning App API is running",
+			"environment": cfg.Environment,
+			"database":    cfg.DatabaseType,
+		})
+	})
+
+	return router
+}
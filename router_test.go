@@ -0,0 +1,1023 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"learning-app-backend/auth"
+	"learning-app-backend/config"
+	"learning-app-backend/database"
+	"learning-app-backend/store"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testBackends - The stores the API tests run against, each built empty for every test
+var testBackends = []struct {
+	name   string
+	stores func(t *testing.T) *store.Stores
+}{
+	{"sqlite", sqliteStores},
+	{"memory", func(t *testing.T) *store.Stores { return store.NewMemoryStores() }},
+}
+
+// sqliteStores - SQL stores on a fresh in-memory SQLite database with every migration applied
+func sqliteStores(t *testing.T) *store.Stores {
+	cfg := &config.Config{DatabaseType: "sqlite", DatabaseURL: ":memory:"}
+	database.InitDatabase(cfg)
+	t.Cleanup(func() { database.SQL.Close() })
+
+	if _, err := database.MigrateUp(cfg.DatabaseType); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return store.NewSQLStores(database.SQL)
+}
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	log.SetOutput(io.Discard)
+	auth.InitTokens(&config.Config{JWTSecret: "test-secret", AccessTokenTTL: 15 * time.Minute,
+		RefreshTokenTTL: time.Hour})
+	os.Exit(m.Run())
+}
+
+// testAPI - The router on one backend, with an admin and a learner signed in
+type testAPI struct {
+	router  *gin.Engine
+	stores  *store.Stores
+	admin   *apiClient
+	learner *apiClient
+	log     *[]string // Every response, to compare backends
+}
+
+// apiClient - Sends requests to the router as one user
+type apiClient struct {
+	t       *testing.T
+	router  http.Handler
+	userID  string
+	token   string
+	refresh string
+	log     *[]string
+}
+
+// forEachBackend - Run test against a fresh router on every backend, then check that every
+// backend answered each request the same way
+func forEachBackend(t *testing.T, test func(t *testing.T, api *testAPI)) {
+	logs := make([][]string, len(testBackends))
+	for i, backend := range testBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			stores := backend.stores(t)
+			api := &testAPI{router: setupRouter(&config.Config{DatabaseType: backend.name}, stores), stores: stores,
+				log: &logs[i]}
+
+			api.admin = api.register(t, "admin1")
+			if _, err := stores.Users.UpdateUserRole("admin1", auth.RoleAdmin); err != nil {
+				t.Fatalf("grant admin: %v", err)
+			}
+			api.learner = api.register(t, "learner1")
+			test(t, api)
+		})
+	}
+
+	for i := 1; i < len(logs); i++ {
+		for j := 0; j < max(len(logs[0]), len(logs[i])); j++ {
+			if j >= len(logs[0]) || j >= len(logs[i]) || logs[0][j] != logs[i][j] {
+				t.Errorf("%s and %s disagree on response %d:\n%s\n%s", testBackends[0].name, testBackends[i].name,
+					j+1, entry(logs[0], j), entry(logs[i], j))
+				break
+			}
+		}
+	}
+}
+
+// entry - A logged response, or a note that the log ended before it
+func entry(log []string, i int) string {
+	if i < len(log) {
+		return log[i]
+	}
+	return "(no response)"
+}
+
+// volatileFields - Response fields that differ between runs or name the backend, left out when
+// comparing backends
+var volatileFields = map[string]bool{
+	"database":             true,
+	"access_token":         true,
+	"refresh_token":        true,
+	"active_seconds":       true,
+	"total_active_seconds": true,
+	"watched_seconds":      true,
+	"retry_after_seconds":  true,
+	"seconds_remaining":    true,
+}
+
+// normalize - A response with timestamps and volatileFields blanked out
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if volatileFields[key] {
+				v[key] = "<volatile>"
+			} else {
+				v[key] = normalize(value)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = normalize(v[i])
+		}
+	case string:
+		if _, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return "<time>"
+		}
+	}
+	return v
+}
+
+// register - Create a user and sign them in
+func (api *testAPI) register(t *testing.T, userID string) *apiClient {
+	client := &apiClient{t: t, router: api.router, userID: userID, log: api.log}
+	resp := client.call(http.MethodPost, "/api/auth/register",
+		gin.H{"user_id": userID, "password": "password123"}, http.StatusCreated)
+	client.token = str(t, resp, "tokens", "access_token")
+	client.refresh = str(t, resp, "tokens", "refresh_token")
+	return client
+}
+
+// call - Send a JSON request and check the status, returning the decoded response
+func (c *apiClient) call(method, path string, body interface{}, want int) map[string]interface{} {
+	c.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatalf("%s %s: encode body: %v", method, path, err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	rec := httptest.NewRecorder()
+	c.router.ServeHTTP(rec, req)
+
+	if rec.Code != want {
+		c.t.Fatalf("%s %s: status %d, want %d: %s", method, path, rec.Code, want, rec.Body.String())
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		c.t.Fatalf("%s %s: decode response: %v", method, path, err)
+	}
+
+	if c.log != nil {
+		var normalized interface{}
+		json.Unmarshal(rec.Body.Bytes(), &normalized)
+		logged, _ := json.Marshal(normalize(normalized))
+		*c.log = append(*c.log, fmt.Sprintf("%s %s %d %s", method, path, rec.Code, logged))
+	}
+	return resp
+}
+
+// field - The value at path (object keys, or indexes into lists) within a decoded response
+func field(t *testing.T, v interface{}, path ...interface{}) interface{} {
+	t.Helper()
+	for _, key := range path {
+		switch k := key.(type) {
+		case string:
+			object, ok := v.(map[string]interface{})
+			if !ok {
+				t.Fatalf("field %v: %q of a non-object", path, k)
+			}
+			if v, ok = object[k]; !ok {
+				t.Fatalf("field %v: no %q", path, k)
+			}
+		case int:
+			list, ok := v.([]interface{})
+			if !ok || k >= len(list) {
+				t.Fatalf("field %v: no item %d", path, k)
+			}
+			v = list[k]
+		}
+	}
+	return v
+}
+
+func str(t *testing.T, v interface{}, path ...interface{}) string {
+	t.Helper()
+	s, ok := field(t, v, path...).(string)
+	if !ok {
+		t.Fatalf("field %v is not a string", path)
+	}
+	return s
+}
+
+func num(t *testing.T, v interface{}, path ...interface{}) float64 {
+	t.Helper()
+	n, ok := field(t, v, path...).(float64)
+	if !ok {
+		t.Fatalf("field %v is not a number", path)
+	}
+	return n
+}
+
+// count - The length of a list, which an empty one may be encoded as null
+func count(t *testing.T, v interface{}, path ...interface{}) int {
+	t.Helper()
+	value := field(t, v, path...)
+	list, ok := value.([]interface{})
+	if !ok && value != nil {
+		t.Fatalf("field %v is not a list", path)
+	}
+	return len(list)
+}
+
+func flag(t *testing.T, v interface{}, path ...interface{}) bool {
+	t.Helper()
+	b, ok := field(t, v, path...).(bool)
+	if !ok {
+		t.Fatalf("field %v is not a bool", path)
+	}
+	return b
+}
+
+// testCourse - What seedCourse created
+type testCourse struct {
+	courseID, chapter1, chapter2 int
+	videoID                      int
+	question1, question2         int // Chapter 1's questions: answers A and B
+}
+
+// seedCourse - A course with two chapters; the first has a 100 second video and two questions
+func (api *testAPI) seedCourse(t *testing.T) testCourse {
+	var course testCourse
+	admin := api.admin
+
+	resp := admin.call(http.MethodPost, "/api/admin/courses",
+		gin.H{"title": "Go", "description": "Basics", "order_index": 1}, http.StatusCreated)
+	course.courseID = int(num(t, resp, "course", "id"))
+
+	for i, title := range []string{"Variables", "Control Flow"} {
+		resp = admin.call(http.MethodPost, "/api/admin/chapters",
+			gin.H{"course_id": course.courseID, "title": title, "order_index": i + 1}, http.StatusCreated)
+		id := int(num(t, resp, "chapter", "id"))
+		if i == 0 {
+			course.chapter1 = id
+		} else {
+			course.chapter2 = id
+		}
+	}
+
+	resp = admin.call(http.MethodPost, fmt.Sprintf("/api/admin/chapters/%d/video", course.chapter1),
+		gin.H{"title": "Intro", "video_url": "https://videos.example/intro", "duration_seconds": 100},
+		http.StatusCreated)
+	course.videoID = int(num(t, resp, "video", "id"))
+
+	for i, correct := range []string{"A", "B"} {
+		resp = admin.call(http.MethodPost, fmt.Sprintf("/api/admin/chapters/%d/questions", course.chapter1), gin.H{
+			"question_text":  fmt.Sprintf("Question %d", i+1),
+			"options":        []gin.H{{"key": "A", "text": "First"}, {"key": "B", "text": "Second"}},
+			"correct_answer": correct,
+			"order_index":    i + 1,
+		}, http.StatusCreated)
+		id := int(num(t, resp, "question", "id"))
+		if i == 0 {
+			course.question1 = id
+		} else {
+			course.question2 = id
+		}
+	}
+	return course
+}
+
+func TestHealth(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		resp := (&apiClient{t: t, router: api.router, log: api.log}).call(http.MethodGet, "/health", nil, http.StatusOK)
+		if str(t, resp, "status") != "healthy" {
+			t.Errorf("status = %v", resp["status"])
+		}
+	})
+}
+
+func TestAuthRoutes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		anonymous := &apiClient{t: t, router: api.router, log: api.log}
+		learner := api.learner
+
+		anonymous.call(http.MethodPost, "/api/auth/register",
+			gin.H{"user_id": "learner1", "password": "password123"}, http.StatusConflict)
+		anonymous.call(http.MethodPost, "/api/auth/login",
+			gin.H{"user_id": "learner1", "password": "wrong-password"}, http.StatusUnauthorized)
+		resp := anonymous.call(http.MethodPost, "/api/auth/login",
+			gin.H{"user_id": "learner1", "password": "password123"}, http.StatusOK)
+		if str(t, resp, "user", "role") != auth.RoleLearner {
+			t.Errorf("role = %v", field(t, resp, "user", "role"))
+		}
+
+		anonymous.call(http.MethodGet, "/api/auth/user/learner1", nil, http.StatusUnauthorized)
+		learner.call(http.MethodGet, "/api/auth/user/learner1", nil, http.StatusOK)
+		learner.call(http.MethodGet, "/api/auth/user/admin1", nil, http.StatusForbidden)
+
+		// Refresh tokens rotate: the old one stops working
+		resp = anonymous.call(http.MethodPost, "/api/auth/refresh", gin.H{"refresh_token": learner.refresh}, http.StatusOK)
+		anonymous.call(http.MethodPost, "/api/auth/refresh", gin.H{"refresh_token": learner.refresh}, http.StatusUnauthorized)
+		learner.token = str(t, resp, "tokens", "access_token")
+		learner.call(http.MethodGet, "/api/auth/user/learner1", nil, http.StatusOK)
+
+		learner.call(http.MethodPost, "/api/auth/logout", nil, http.StatusOK)
+		learner.call(http.MethodGet, "/api/auth/user/learner1", nil, http.StatusUnauthorized)
+	})
+}
+
+func TestAdminRoutes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		resp := api.admin.call(http.MethodGet, "/api/admin/roles", nil, http.StatusOK)
+		if n := count(t, resp, "roles"); n != 3 {
+			t.Errorf("%d roles, want 3", n)
+		}
+		resp = api.admin.call(http.MethodGet, "/api/admin/users", nil, http.StatusOK)
+		if n := count(t, resp, "users"); n != 2 {
+			t.Errorf("%d users, want 2", n)
+		}
+
+		api.learner.call(http.MethodGet, "/api/admin/users", nil, http.StatusForbidden)
+		api.admin.call(http.MethodPut, "/api/admin/users/learner1/role", gin.H{"role": "superuser"}, http.StatusBadRequest)
+		resp = api.admin.call(http.MethodPut, "/api/admin/users/learner1/role",
+			gin.H{"role": auth.RoleInstructor}, http.StatusOK)
+		if str(t, resp, "user", "role") != auth.RoleInstructor {
+			t.Errorf("role = %v", field(t, resp, "user", "role"))
+		}
+
+		// The new role applies to the learner's next request
+		api.learner.call(http.MethodGet, "/api/chapters/1/quiz/answer-key", nil, http.StatusNotFound)
+	})
+}
+
+func TestContentRoutes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		course := api.seedCourse(t)
+		admin, learner := api.admin, api.learner
+
+		learner.call(http.MethodPost, "/api/admin/courses",
+			gin.H{"title": "Rust", "order_index": 2}, http.StatusForbidden)
+		admin.call(http.MethodPost, "/api/admin/courses",
+			gin.H{"title": "Duplicate order", "order_index": 1}, http.StatusConflict)
+
+		resp := admin.call(http.MethodPut, fmt.Sprintf("/api/admin/courses/%d", course.courseID),
+			gin.H{"title": "Go Basics", "order_index": 1}, http.StatusOK)
+		if str(t, resp, "course", "title") != "Go Basics" {
+			t.Errorf("course title = %v", field(t, resp, "course", "title"))
+		}
+		admin.call(http.MethodPut, fmt.Sprintf("/api/admin/chapters/%d", course.chapter2),
+			gin.H{"course_id": course.courseID, "title": "Loops", "order_index": 2}, http.StatusOK)
+		admin.call(http.MethodPut, fmt.Sprintf("/api/admin/videos/%d", course.videoID),
+			gin.H{"title": "Intro", "video_url": "https://videos.example/intro-v2", "duration_seconds": 120}, http.StatusOK)
+		admin.call(http.MethodPut, fmt.Sprintf("/api/admin/questions/%d", course.question2), gin.H{
+			"question_text":  "Question 2, reworded",
+			"options":        []gin.H{{"key": "A", "text": "First"}, {"key": "B", "text": "Second"}},
+			"correct_answer": "B",
+			"order_index":    2,
+		}, http.StatusOK)
+
+		// Chapter 2 opens once chapter 1 is complete
+		admin.call(http.MethodPost, fmt.Sprintf("/api/admin/courses/%d/prerequisites/sequential", course.courseID),
+			gin.H{}, http.StatusCreated)
+		resp = learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/prerequisites", course.chapter2), nil, http.StatusOK)
+		if n := count(t, resp, "prerequisites"); n != 1 {
+			t.Fatalf("%d prerequisites, want 1", n)
+		}
+		prerequisiteID := int(num(t, resp, "prerequisites", 0, "id"))
+		resp = learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/content", course.chapter2), nil, http.StatusOK)
+		if !flag(t, resp, "chapter", "locked") {
+			t.Errorf("chapter 2 not locked: %v", resp["chapter"])
+		}
+		learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/quiz", course.chapter2), nil, http.StatusForbidden)
+		// Past the lock, chapter 2 simply has no questions yet
+		admin.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/quiz", course.chapter2), nil, http.StatusNotFound)
+		admin.call(http.MethodDelete, fmt.Sprintf("/api/admin/prerequisites/%d", prerequisiteID), nil, http.StatusOK)
+		learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/quiz", course.chapter2), nil, http.StatusNotFound)
+		admin.call(http.MethodPost, fmt.Sprintf("/api/admin/chapters/%d/prerequisites", course.chapter2),
+			gin.H{"required_chapter_id": course.chapter2}, http.StatusBadRequest)
+
+		// Question pools and draw rules
+		resp = admin.call(http.MethodPost, fmt.Sprintf("/api/admin/chapters/%d/pools", course.chapter1),
+			gin.H{"title": "Warm-up"}, http.StatusCreated)
+		poolID := int(num(t, resp, "pool", "id"))
+		admin.call(http.MethodPut, fmt.Sprintf("/api/admin/pools/%d", poolID), gin.H{"title": "Warm-up questions"}, http.StatusOK)
+		resp = admin.call(http.MethodGet, fmt.Sprintf("/api/admin/chapters/%d/pools", course.chapter1), nil, http.StatusOK)
+		if n := count(t, resp, "pools"); n != 1 {
+			t.Errorf("%d pools, want 1", n)
+		}
+		admin.call(http.MethodPut, fmt.Sprintf("/api/admin/chapters/%d/draw-rules", course.chapter1),
+			gin.H{"rules": []gin.H{{"pool_id": poolID, "draw_count": 1}}}, http.StatusOK)
+		resp = admin.call(http.MethodGet, fmt.Sprintf("/api/admin/chapters/%d/draw-rules", course.chapter1), nil, http.StatusOK)
+		if n := count(t, resp, "rules"); n != 1 {
+			t.Errorf("%d draw rules, want 1", n)
+		}
+		admin.call(http.MethodPut, fmt.Sprintf("/api/admin/chapters/%d/draw-rules", course.chapter1),
+			gin.H{"rules": []gin.H{}}, http.StatusOK)
+		admin.call(http.MethodDelete, fmt.Sprintf("/api/admin/pools/%d", poolID), nil, http.StatusOK)
+
+		admin.call(http.MethodDelete, fmt.Sprintf("/api/admin/questions/%d", course.question2), nil, http.StatusOK)
+		admin.call(http.MethodDelete, fmt.Sprintf("/api/admin/questions/%d", course.question2), nil, http.StatusNotFound)
+		admin.call(http.MethodDelete, fmt.Sprintf("/api/admin/videos/%d", course.videoID), nil, http.StatusOK)
+		learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/video", course.chapter1), nil, http.StatusNotFound)
+		admin.call(http.MethodDelete, fmt.Sprintf("/api/admin/chapters/%d", course.chapter2), nil, http.StatusOK)
+		learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d", course.chapter2), nil, http.StatusNotFound)
+		admin.call(http.MethodDelete, fmt.Sprintf("/api/admin/courses/%d", course.courseID), nil, http.StatusConflict)
+		admin.call(http.MethodDelete, fmt.Sprintf("/api/admin/chapters/%d", course.chapter1), nil, http.StatusOK)
+		admin.call(http.MethodDelete, fmt.Sprintf("/api/admin/courses/%d", course.courseID), nil, http.StatusOK)
+		learner.call(http.MethodGet, fmt.Sprintf("/api/courses/%d", course.courseID), nil, http.StatusNotFound)
+	})
+}
+
+func TestCourseAndChapterRoutes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		course := api.seedCourse(t)
+		learner := api.learner
+
+		resp := learner.call(http.MethodGet, "/api/courses", nil, http.StatusOK)
+		if n := count(t, resp, "courses"); n != 1 {
+			t.Errorf("%d courses, want 1", n)
+		}
+		resp = learner.call(http.MethodGet, fmt.Sprintf("/api/courses/%d", course.courseID), nil, http.StatusOK)
+		if n := count(t, resp, "course", "chapters"); n != 2 {
+			t.Errorf("%d chapters in course, want 2", n)
+		}
+		learner.call(http.MethodGet, fmt.Sprintf("/api/courses/%d/chapters", course.courseID), nil, http.StatusOK)
+		learner.call(http.MethodGet, "/api/courses/999", nil, http.StatusNotFound)
+
+		resp = learner.call(http.MethodGet, "/api/chapters", nil, http.StatusOK)
+		if n := count(t, resp, "chapters"); n != 2 {
+			t.Errorf("%d chapters, want 2", n)
+		}
+		learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d", course.chapter1), nil, http.StatusOK)
+		learner.call(http.MethodGet, "/api/chapters/abc", nil, http.StatusBadRequest)
+		resp = learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/video", course.chapter1), nil, http.StatusOK)
+		if num(t, resp, "video", "duration_seconds") != 100 {
+			t.Errorf("duration = %v", field(t, resp, "video", "duration_seconds"))
+		}
+		learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/video", course.chapter2), nil, http.StatusNotFound)
+		learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/content", course.chapter1), nil, http.StatusOK)
+		learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/prerequisites", course.chapter1), nil, http.StatusOK)
+
+		// Learners see questions without their answers; the answer key is for staff
+		resp = learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/quiz", course.chapter1), nil, http.StatusOK)
+		if strings.Contains(fmt.Sprint(resp), "correct_answer:") {
+			t.Errorf("learner quiz reveals answers: %v", resp)
+		}
+		learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/quiz/answer-key", course.chapter1), nil, http.StatusForbidden)
+		api.admin.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/quiz/answer-key", course.chapter1), nil, http.StatusOK)
+	})
+}
+
+func TestProgressRoutes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		course := api.seedCourse(t)
+		learner := api.learner
+		base := "/api/progress/user/learner1"
+
+		learner.call(http.MethodPost, "/api/progress", gin.H{"user_id": "learner1", "chapter_id": course.chapter1,
+			"content_type": "video", "video_timestamp": 30}, http.StatusOK)
+		learner.call(http.MethodPost, "/api/progress", gin.H{"user_id": "admin1", "chapter_id": course.chapter1,
+			"content_type": "video", "video_timestamp": 30}, http.StatusForbidden)
+
+		resp := learner.call(http.MethodPost, "/api/progress/heartbeat", gin.H{"user_id": "learner1",
+			"chapter_id": course.chapter1, "intervals": []gin.H{{"start": 0, "end": 95}}}, http.StatusOK)
+		if !flag(t, resp, "chapter_status", "video_completed") {
+			t.Errorf("video not completed after watching 95%%: %v", resp["chapter_status"])
+		}
+		learner.call(http.MethodPost, "/api/progress/heartbeat", gin.H{"user_id": "learner1",
+			"chapter_id": course.chapter2, "intervals": []gin.H{{"start": 0, "end": 10}}}, http.StatusNotFound)
+
+		batch := gin.H{"user_id": "learner1", "events": []gin.H{{
+			"client_event_id": "offline-1", "type": "progress", "occurred_at": time.Now().UTC(),
+			"chapter_id": course.chapter2, "content_type": "video", "video_timestamp": 5,
+		}, {
+			"client_event_id": "offline-2", "type": "bookmark", "occurred_at": time.Now().UTC(),
+			"chapter_id": course.chapter2,
+		}}}
+		resp = learner.call(http.MethodPost, "/api/progress/sync", batch, http.StatusOK)
+		if str(t, resp, "results", 0, "status") != "applied" || str(t, resp, "results", 1, "status") != "rejected" {
+			t.Errorf("sync results = %v", resp["results"])
+		}
+		// Resending repeats what was remembered; rejected events are tried again
+		resp = learner.call(http.MethodPost, "/api/progress/sync", batch, http.StatusOK)
+		if !flag(t, resp, "results", 0, "duplicate") || flag(t, resp, "results", 1, "duplicate") {
+			t.Errorf("resent sync results = %v", resp["results"])
+		}
+
+		resp = learner.call(http.MethodGet, base, nil, http.StatusOK)
+		if !flag(t, resp, "progress", "has_progress") {
+			t.Errorf("no latest progress: %v", resp)
+		}
+		resp = learner.call(http.MethodGet, base+"/all", nil, http.StatusOK)
+		if n := count(t, resp, "progress"); n != 2 {
+			t.Errorf("%d progress rows, want 2", n)
+		}
+		resp = learner.call(http.MethodGet, fmt.Sprintf("%s/chapter/%d", base, course.chapter1), nil, http.StatusOK)
+		if n := count(t, resp, "progress"); n != 1 {
+			t.Errorf("%d progress rows in chapter 1, want 1", n)
+		}
+		learner.call(http.MethodGet, fmt.Sprintf("%s/course/%d", base, course.courseID), nil, http.StatusOK)
+		learner.call(http.MethodGet, base+"/status", nil, http.StatusOK)
+		resp = learner.call(http.MethodGet, fmt.Sprintf("%s/chapter/%d/status", base, course.chapter1), nil, http.StatusOK)
+		if str(t, resp, "status", "status") != "in_progress" {
+			t.Errorf("chapter status = %v", field(t, resp, "status", "status"))
+		}
+		resp = learner.call(http.MethodGet, base+"/videos", nil, http.StatusOK)
+		if n := count(t, resp, "watches"); n != 1 {
+			t.Errorf("%d video watches, want 1", n)
+		}
+		resp = learner.call(http.MethodGet, fmt.Sprintf("%s/chapter/%d/video", base, course.chapter1), nil, http.StatusOK)
+		if num(t, resp, "watch", "watched_seconds") != 95 {
+			t.Errorf("watched seconds = %v", field(t, resp, "watch", "watched_seconds"))
+		}
+		resp = learner.call(http.MethodGet, base+"/timeline", nil, http.StatusOK)
+		if n := count(t, resp, "events"); n != 3 {
+			t.Errorf("%d timeline events, want 3", n)
+		}
+		learner.call(http.MethodGet, fmt.Sprintf("%s/chapter/%d/timeline", base, course.chapter1), nil, http.StatusOK)
+		resp = learner.call(http.MethodGet, base+"/activity", nil, http.StatusOK)
+		if n := count(t, resp, "days"); n != 1 {
+			t.Errorf("%d activity days, want 1", n)
+		}
+		learner.call(http.MethodGet, base+"/activity?tz=Not/A_Zone", nil, http.StatusBadRequest)
+		resp = learner.call(http.MethodGet, base+"/dashboard", nil, http.StatusOK)
+		if n := count(t, resp, "courses"); n != 1 {
+			t.Errorf("%d dashboard courses, want 1", n)
+		}
+		learner.call(http.MethodGet, "/api/progress/user/admin1/all", nil, http.StatusForbidden)
+
+		learner.call(http.MethodDelete, base+"/reset", nil, http.StatusOK)
+		resp = learner.call(http.MethodGet, base+"/all", nil, http.StatusOK)
+		if n := count(t, resp, "progress"); n != 0 {
+			t.Errorf("%d progress rows after reset, want 0", n)
+		}
+	})
+}
+
+func TestProgressTimelineRange(t *testing.T) {
+	// An hour ago the offline event below had happened, but no row had been written yet
+	hourAgo := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		course := api.seedCourse(t)
+		learner := api.learner
+		base := "/api/progress/user/learner1/timeline"
+
+		learner.call(http.MethodPost, "/api/progress", gin.H{"user_id": "learner1", "chapter_id": course.chapter1,
+			"content_type": "video", "video_timestamp": 30}, http.StatusOK)
+		// Happened two hours ago, synced only now
+		learner.call(http.MethodPost, "/api/progress/sync", gin.H{"user_id": "learner1", "events": []gin.H{{
+			"client_event_id": "offline-1", "type": "progress", "occurred_at": time.Now().UTC().Add(-2 * time.Hour),
+			"chapter_id": course.chapter2, "content_type": "video", "video_timestamp": 5,
+		}}}, http.StatusOK)
+
+		resp := learner.call(http.MethodGet, base+"?to="+hourAgo, nil, http.StatusOK)
+		if n := count(t, resp, "events"); n != 1 {
+			t.Errorf("%d events before %s, want the offline one", n, hourAgo)
+		}
+		if n := count(t, resp, "progress"); n != 0 {
+			t.Errorf("%d progress rows before %s, want none", n, hourAgo)
+		}
+
+		resp = learner.call(http.MethodGet, base, nil, http.StatusOK)
+		if count(t, resp, "events") != 2 || count(t, resp, "progress") != 2 {
+			t.Errorf("timeline = %v", resp)
+		}
+		resp = learner.call(http.MethodGet, base+"?from="+hourAgo, nil, http.StatusOK)
+		if count(t, resp, "events") != 1 || count(t, resp, "progress") != 2 {
+			t.Errorf("timeline from %s = %v", hourAgo, resp)
+		}
+	})
+}
+
+func TestProgressStream(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		course := api.seedCourse(t)
+		server := httptest.NewServer(api.router)
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/progress/user/learner1/stream", nil)
+		req.Header.Set("Authorization", "Bearer "+api.learner.token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("open stream: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("stream status %d", resp.StatusCode)
+		}
+
+		lines := bufio.NewScanner(resp.Body)
+		waitFor := func(prefix string) string {
+			for lines.Scan() {
+				if strings.HasPrefix(lines.Text(), prefix) {
+					return lines.Text()
+				}
+			}
+			t.Fatalf("stream ended before %q: %v", prefix, lines.Err())
+			return ""
+		}
+		waitFor(": connected")
+
+		api.learner.call(http.MethodPost, "/api/progress", gin.H{"user_id": "learner1", "chapter_id": course.chapter1,
+			"content_type": "video", "video_timestamp": 12}, http.StatusOK)
+		if event := waitFor("event: "); event != "event: progress" {
+			t.Errorf("got %q, want a progress event", event)
+		}
+	})
+}
+
+func TestQuizRoutes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		course := api.seedCourse(t)
+		learner := api.learner
+		answer := func(questionID int, key string) map[string]interface{} {
+			return learner.call(http.MethodPost, "/api/quiz/submit", gin.H{"user_id": "learner1",
+				"chapter_id": course.chapter1, "quiz_question_id": questionID, "user_answer": key}, http.StatusOK)
+		}
+
+		attemptsPath := fmt.Sprintf("/api/quiz/attempts/user/learner1/chapter/%d", course.chapter1)
+		resp := learner.call(http.MethodPost, attemptsPath, nil, http.StatusCreated)
+		attemptID := int(num(t, resp, "attempt", "id"))
+		resp = learner.call(http.MethodPost, attemptsPath, nil, http.StatusOK)
+		if int(num(t, resp, "attempt", "id")) != attemptID {
+			t.Errorf("starting again did not resume attempt %d", attemptID)
+		}
+
+		if resp = answer(course.question1, "A"); !flag(t, resp, "is_correct") {
+			t.Errorf("correct answer marked wrong: %v", resp)
+		}
+		if resp = answer(course.question2, "A"); flag(t, resp, "is_correct") {
+			t.Errorf("wrong answer marked correct: %v", resp)
+		}
+
+		base := "/api/quiz/history/user/learner1"
+		resp = learner.call(http.MethodGet, fmt.Sprintf("%s/chapter/%d", base, course.chapter1), nil, http.StatusOK)
+		if n := count(t, resp, "answers"); n != 2 {
+			t.Errorf("%d answers in chapter history, want 2", n)
+		}
+		learner.call(http.MethodGet, base, nil, http.StatusOK)
+		learner.call(http.MethodGet, fmt.Sprintf("%s/question/%d", base, course.question1), nil, http.StatusOK)
+		learner.call(http.MethodGet, fmt.Sprintf("%s/chapter/%d/attempts", base, course.chapter1), nil, http.StatusOK)
+		resp = learner.call(http.MethodGet, "/api/quiz/score/user/learner1", nil, http.StatusOK)
+		if num(t, resp, "scores", 0, "total_correct") != 1 {
+			t.Errorf("scores = %v", resp["scores"])
+		}
+		learner.call(http.MethodGet, fmt.Sprintf("/api/quiz/score/user/learner1/course/%d", course.courseID), nil, http.StatusOK)
+
+		attemptPath := fmt.Sprintf("/api/quiz/attempts/user/learner1/attempt/%d", attemptID)
+		resp = learner.call(http.MethodGet, attemptPath, nil, http.StatusOK)
+		if n := count(t, resp, "attempt", "answers"); n != 2 {
+			t.Errorf("%d answers in attempt, want 2", n)
+		}
+		resp = learner.call(http.MethodPost, attemptPath+"/finish", nil, http.StatusOK)
+		if num(t, resp, "attempt", "score_percent") != 50 {
+			t.Errorf("attempt score = %v", field(t, resp, "attempt", "score_percent"))
+		}
+		learner.call(http.MethodPost, attemptPath+"/finish", nil, http.StatusConflict)
+		resp = learner.call(http.MethodGet, attemptsPath, nil, http.StatusOK)
+		if n := count(t, resp, "attempts"); n != 1 {
+			t.Errorf("%d attempts, want 1", n)
+		}
+
+		learner.call(http.MethodGet, fmt.Sprintf("/api/quiz/chapter/%d/with-history?user_id=learner1", course.chapter1),
+			nil, http.StatusOK)
+		learner.call(http.MethodGet, fmt.Sprintf("/api/quiz/resume/user/learner1/chapter/%d", course.chapter1),
+			nil, http.StatusOK)
+
+		learner.call(http.MethodDelete, base+"/clear", nil, http.StatusOK)
+		resp = learner.call(http.MethodGet, fmt.Sprintf("%s/chapter/%d", base, course.chapter1), nil, http.StatusOK)
+		if n := count(t, resp, "answers"); n != 0 {
+			t.Errorf("%d answers after clearing, want 0", n)
+		}
+	})
+}
+
+func TestTimedQuizRoutes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		course := api.seedCourse(t)
+		admin, learner := api.admin, api.learner
+		other := api.register(t, "learner2")
+
+		// One second to answer: chapter 1 rejects late answers, chapter 2 flags them
+		for i, chapter := range []struct {
+			id     int
+			policy string
+		}{{course.chapter1, "reject"}, {course.chapter2, "flag"}} {
+			admin.call(http.MethodPut, fmt.Sprintf("/api/admin/chapters/%d", chapter.id), gin.H{
+				"course_id": course.courseID, "title": fmt.Sprintf("Chapter %d", i+1), "order_index": i + 1,
+				"quiz_time_limit_seconds": 1, "quiz_late_policy": chapter.policy,
+			}, http.StatusOK)
+		}
+		resp := admin.call(http.MethodPost, fmt.Sprintf("/api/admin/chapters/%d/questions", course.chapter2), gin.H{
+			"question_text":  "Question 3",
+			"options":        []gin.H{{"key": "A", "text": "First"}, {"key": "B", "text": "Second"}},
+			"correct_answer": "A",
+			"order_index":    1,
+		}, http.StatusCreated)
+		question3 := int(num(t, resp, "question", "id"))
+
+		start := func(client *apiClient, chapterID, want int) map[string]interface{} {
+			return client.call(http.MethodPost, fmt.Sprintf("/api/quiz/attempts/user/%s/chapter/%d", client.userID, chapterID),
+				nil, want)
+		}
+		rejected := int(num(t, start(learner, course.chapter1, http.StatusCreated), "attempt", "id"))
+		closed := int(num(t, start(other, course.chapter1, http.StatusCreated), "attempt", "id"))
+		resp = start(learner, course.chapter2, http.StatusCreated)
+		if flag(t, resp, "attempt", "time_expired") || field(t, resp, "attempt", "deadline_at") == nil {
+			t.Errorf("new timed attempt = %v", resp["attempt"])
+		}
+		flagged := int(num(t, resp, "attempt", "id"))
+		time.Sleep(1100 * time.Millisecond)
+
+		// Reject: a late answer closes the attempt with 409
+		resp = other.call(http.MethodPost, "/api/quiz/submit", gin.H{"user_id": "learner2", "chapter_id": course.chapter1,
+			"quiz_question_id": course.question1, "user_answer": "A"}, http.StatusConflict)
+		if int(num(t, resp, "attempt", "id")) != closed {
+			t.Errorf("late answer refused for attempt %v, want %d", field(t, resp, "attempt", "id"), closed)
+		}
+		resp = other.call(http.MethodGet, fmt.Sprintf("/api/quiz/attempts/user/learner2/attempt/%d", closed), nil, http.StatusOK)
+		if got := str(t, resp, "attempt", "status"); got != "finished" {
+			t.Errorf("expired attempt is %s, want finished", got)
+		}
+
+		// Reject: an expired attempt is not resumed, a new one starts instead
+		resp = start(learner, course.chapter1, http.StatusCreated)
+		if int(num(t, resp, "attempt", "id")) == rejected {
+			t.Errorf("expired attempt %d was resumed under the reject policy", rejected)
+		}
+
+		// Flag: the expired attempt is resumed and late answers count, marked is_late
+		resp = start(learner, course.chapter2, http.StatusOK)
+		if int(num(t, resp, "attempt", "id")) != flagged || !flag(t, resp, "attempt", "time_expired") {
+			t.Errorf("resumed attempt = %v, want expired attempt %d", resp["attempt"], flagged)
+		}
+		resp = learner.call(http.MethodPost, "/api/quiz/submit", gin.H{"user_id": "learner1", "chapter_id": course.chapter2,
+			"quiz_question_id": question3, "user_answer": "A"}, http.StatusOK)
+		if !flag(t, resp, "is_late") || !flag(t, resp, "is_correct") {
+			t.Errorf("late answer = %v, want correct and is_late", resp)
+		}
+	})
+}
+
+func TestQuizAttemptLimits(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		course := api.seedCourse(t)
+		learner := api.learner
+		api.admin.call(http.MethodPut, fmt.Sprintf("/api/admin/chapters/%d", course.chapter1), gin.H{
+			"course_id": course.courseID, "title": "Variables", "order_index": 1,
+			"max_answer_attempts": 1, "max_quiz_attempts": 2,
+		}, http.StatusOK)
+		answer := func(questionID int, key string, want int) map[string]interface{} {
+			return learner.call(http.MethodPost, "/api/quiz/submit", gin.H{"user_id": "learner1",
+				"chapter_id": course.chapter1, "quiz_question_id": questionID, "user_answer": key}, want)
+		}
+		finish := func(attemptID int) {
+			learner.call(http.MethodPost, fmt.Sprintf("/api/quiz/attempts/user/learner1/attempt/%d/finish", attemptID),
+				nil, http.StatusOK)
+		}
+
+		// One answer per question in each attempt
+		resp := answer(course.question1, "B", http.StatusOK)
+		if _, revealed := resp["correct_answer"]; revealed || num(t, resp, "attempts_remaining") != 0 {
+			t.Errorf("first attempt's wrong answer = %v", resp)
+		}
+		answer(course.question1, "A", http.StatusConflict)
+		finish(int(num(t, resp, "attempt_id")))
+
+		// A new attempt answers afresh, and a failed chapter can still be passed
+		resp = answer(course.question1, "A", http.StatusOK)
+		if !flag(t, resp, "is_correct") {
+			t.Errorf("second attempt's answer = %v", resp)
+		}
+		answer(course.question2, "A", http.StatusOK)
+		finish(int(num(t, resp, "attempt_id")))
+
+		// Until the quiz's own attempts run out
+		learner.call(http.MethodPost, fmt.Sprintf("/api/quiz/attempts/user/learner1/chapter/%d", course.chapter1),
+			nil, http.StatusConflict)
+		answer(course.question2, "B", http.StatusConflict)
+		resp = learner.call(http.MethodGet, fmt.Sprintf("/api/progress/user/learner1/chapter/%d/status", course.chapter1),
+			nil, http.StatusOK)
+		if got := str(t, resp, "status", "status"); got != "no_attempts_left" {
+			t.Errorf("chapter status = %q, want no_attempts_left", got)
+		}
+		resp = learner.call(http.MethodGet, "/api/progress/user/learner1/dashboard", nil, http.StatusOK)
+		if int(num(t, resp, "next_chapter", "chapter_id")) != course.chapter2 {
+			t.Errorf("next chapter = %v, want chapter %d", resp["next_chapter"], course.chapter2)
+		}
+	})
+}
+
+func TestQuestionPoolDraws(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		course := api.seedCourse(t)
+		admin, learner := api.admin, api.learner
+
+		resp := admin.call(http.MethodPost, fmt.Sprintf("/api/admin/chapters/%d/pools", course.chapter1),
+			gin.H{"title": "Loops"}, http.StatusCreated)
+		poolID := int(num(t, resp, "pool", "id"))
+		pooled := map[int]bool{}
+		for i := 0; i < 4; i++ {
+			resp = admin.call(http.MethodPost, fmt.Sprintf("/api/admin/chapters/%d/questions", course.chapter1), gin.H{
+				"question_text":  fmt.Sprintf("Pooled %d", i+1),
+				"options":        []gin.H{{"key": "A", "text": "First"}, {"key": "B", "text": "Second"}},
+				"correct_answer": "A",
+				"order_index":    10 + i,
+				"pool_id":        poolID,
+			}, http.StatusCreated)
+			pooled[int(num(t, resp, "question", "id"))] = true
+		}
+		rulesPath := fmt.Sprintf("/api/admin/chapters/%d/draw-rules", course.chapter1)
+		admin.call(http.MethodPut, rulesPath, gin.H{"rules": []gin.H{{"pool_id": poolID, "draw_count": 2}}}, http.StatusOK)
+
+		drawn := func() []int {
+			resp := learner.call(http.MethodGet, fmt.Sprintf("/api/chapters/%d/quiz", course.chapter1), nil, http.StatusOK)
+			var ids []int
+			for i := 0; i < count(t, resp, "questions"); i++ {
+				ids = append(ids, int(num(t, resp, "questions", i, "id")))
+			}
+			return ids
+		}
+		attemptsPath := fmt.Sprintf("/api/quiz/attempts/user/learner1/chapter/%d", course.chapter1)
+		resp = learner.call(http.MethodPost, attemptsPath, nil, http.StatusCreated)
+		attemptID := int(num(t, resp, "attempt", "id"))
+		draw := drawn()
+		if len(draw) != 2 || num(t, resp, "attempt", "total_questions") != 2 {
+			t.Fatalf("drew %v for an attempt of %v questions, want 2 pooled questions", draw,
+				field(t, resp, "attempt", "total_questions"))
+		}
+		inDraw := map[int]bool{}
+		for _, id := range draw {
+			if !pooled[id] {
+				t.Errorf("question %d was drawn but is not in the pool", id)
+			}
+			inDraw[id] = true
+		}
+
+		// Questions outside the draw cannot be answered, pooled or not
+		submit := func(questionID, want int) {
+			learner.call(http.MethodPost, "/api/quiz/submit", gin.H{"user_id": "learner1", "chapter_id": course.chapter1,
+				"quiz_question_id": questionID, "user_answer": "A"}, want)
+		}
+		submit(course.question1, http.StatusBadRequest)
+		for id := range pooled {
+			if !inDraw[id] {
+				submit(id, http.StatusBadRequest)
+			}
+		}
+
+		// Changing the rules mid-attempt does not touch the frozen draw
+		admin.call(http.MethodPut, rulesPath, gin.H{"rules": []gin.H{{"pool_id": poolID, "draw_count": 4}}}, http.StatusOK)
+		resp = learner.call(http.MethodPost, attemptsPath, nil, http.StatusOK)
+		if int(num(t, resp, "attempt", "id")) != attemptID {
+			t.Errorf("resumed attempt %v, want %d", field(t, resp, "attempt", "id"), attemptID)
+		}
+		if got := drawn(); fmt.Sprint(got) != fmt.Sprint(draw) {
+			t.Errorf("resumed attempt shows %v, want the frozen draw %v", got, draw)
+		}
+		for _, id := range draw {
+			submit(id, http.StatusOK)
+		}
+		resp = learner.call(http.MethodPost, fmt.Sprintf("/api/quiz/attempts/user/learner1/attempt/%d/finish", attemptID),
+			nil, http.StatusOK)
+		if num(t, resp, "attempt", "total_questions") != 2 || num(t, resp, "attempt", "score_percent") != 100 {
+			t.Errorf("finished attempt = %v, want 2 of 2 correct", resp["attempt"])
+		}
+
+		// The next attempt draws under the new rules
+		learner.call(http.MethodPost, attemptsPath, nil, http.StatusCreated)
+		if got := drawn(); len(got) != 4 {
+			t.Errorf("next attempt drew %v, want all 4 pooled questions", got)
+		}
+	})
+}
+
+func TestOfflineAnswers(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		course := api.seedCourse(t)
+		learner := api.learner
+		resp := learner.call(http.MethodPost, fmt.Sprintf("/api/quiz/attempts/user/learner1/chapter/%d", course.chapter1),
+			nil, http.StatusCreated)
+		attemptID := int(num(t, resp, "attempt", "id"))
+
+		// Online answers land strictly after the attempt started
+		time.Sleep(2 * time.Millisecond)
+		for _, questionID := range []int{course.question1, course.question2} {
+			learner.call(http.MethodPost, "/api/quiz/submit", gin.H{"user_id": "learner1", "chapter_id": course.chapter1,
+				"quiz_question_id": questionID, "user_answer": "B"}, http.StatusOK)
+		}
+		sync := func(eventID string, questionID int, key string, at time.Time) string {
+			resp := learner.call(http.MethodPost, "/api/progress/sync", gin.H{"user_id": "learner1", "events": []gin.H{{
+				"client_event_id": eventID, "type": "answer", "occurred_at": at.UTC(), "chapter_id": course.chapter1,
+				"quiz_question_id": questionID, "user_answer": key,
+			}}}, http.StatusOK)
+			return str(t, resp, "results", 0, "status")
+		}
+
+		// The earliest answer wins, whichever way it arrived
+		if got := sync("offline-1", course.question1, "A", time.Now().Add(-time.Hour)); got != "applied" {
+			t.Errorf("earlier offline answer %s, want applied", got)
+		}
+		if got := sync("offline-2", course.question2, "A", time.Now()); got != "conflict" {
+			t.Errorf("later offline answer %s, want conflict", got)
+		}
+
+		resp = learner.call(http.MethodGet, fmt.Sprintf("/api/quiz/history/user/learner1/chapter/%d", course.chapter1),
+			nil, http.StatusOK)
+		if n := count(t, resp, "answers"); n != 2 {
+			t.Errorf("%d answers in chapter history, want 2", n)
+		}
+		resp = learner.call(http.MethodPost, fmt.Sprintf("/api/quiz/attempts/user/learner1/attempt/%d/finish", attemptID),
+			nil, http.StatusOK)
+		if num(t, resp, "attempt", "score_percent") != 100 {
+			t.Errorf("attempt score = %v, want 100", field(t, resp, "attempt", "score_percent"))
+		}
+	})
+}
+
+func TestReviewRoutes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		course := api.seedCourse(t)
+		learner := api.learner
+
+		// A wrong answer is due for review right away; a right one only tomorrow
+		learner.call(http.MethodPost, "/api/quiz/submit", gin.H{"user_id": "learner1", "chapter_id": course.chapter1,
+			"quiz_question_id": course.question1, "user_answer": "B"}, http.StatusOK)
+		learner.call(http.MethodPost, "/api/quiz/submit", gin.H{"user_id": "learner1", "chapter_id": course.chapter1,
+			"quiz_question_id": course.question2, "user_answer": "B"}, http.StatusOK)
+
+		resp := learner.call(http.MethodGet, "/api/reviews/user/learner1/due", nil, http.StatusOK)
+		if n := num(t, resp, "count"); n != 1 {
+			t.Fatalf("%v reviews due, want 1", n)
+		}
+
+		review := gin.H{"user_id": "learner1", "quiz_question_id": course.question1, "user_answer": "A"}
+		resp = learner.call(http.MethodPost, "/api/reviews/submit", review, http.StatusOK)
+		if !flag(t, resp, "is_correct") {
+			t.Errorf("correct review marked wrong: %v", resp)
+		}
+		learner.call(http.MethodPost, "/api/reviews/submit", review, http.StatusConflict)
+		learner.call(http.MethodPost, "/api/reviews/submit", gin.H{"user_id": "learner1",
+			"quiz_question_id": course.question2, "user_answer": "B"}, http.StatusConflict)
+	})
+}
+
+func TestStreakRoutes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, api *testAPI) {
+		course := api.seedCourse(t)
+		learner := api.learner
+		base := "/api/streaks/user/learner1"
+
+		resp := learner.call(http.MethodGet, base, nil, http.StatusOK)
+		if n := count(t, resp, "days"); n != 30 {
+			t.Errorf("%d days, want 30", n)
+		}
+		learner.call(http.MethodGet, base+"?days=0", nil, http.StatusBadRequest)
+		api.admin.call(http.MethodGet, base, nil, http.StatusOK)
+		// Reading a streak leaves the stored record alone
+		if _, err := api.stores.Streaks.GetStreakRecord("learner1"); err != store.ErrNotFound {
+			t.Errorf("streak record after reads: %v, want not found", err)
+		}
+
+		resp = learner.call(http.MethodPut, base+"/goal", gin.H{"goal_type": "questions", "target": 1}, http.StatusOK)
+		if flag(t, resp, "streak", "goal_met_today") {
+			t.Errorf("goal met before answering: %v", resp["streak"])
+		}
+		learner.call(http.MethodPut, base+"/goal", gin.H{"goal_type": "pages", "target": 1}, http.StatusBadRequest)
+
+		today := str(t, resp, "today")
+		learner.call(http.MethodPost, base+"/freezes", gin.H{"date": today}, http.StatusCreated)
+		learner.call(http.MethodPost, base+"/freezes", gin.H{"date": today}, http.StatusConflict)
+		learner.call(http.MethodDelete, base+"/freezes/"+today, nil, http.StatusOK)
+		learner.call(http.MethodDelete, base+"/freezes/"+today, nil, http.StatusNotFound)
+
+		learner.call(http.MethodPost, "/api/quiz/submit", gin.H{"user_id": "learner1", "chapter_id": course.chapter1,
+			"quiz_question_id": course.question1, "user_answer": "A"}, http.StatusOK)
+		resp = learner.call(http.MethodGet, base+"?days=7", nil, http.StatusOK)
+		if num(t, resp, "streak", "current_streak") != 1 || num(t, resp, "streak", "longest_streak") != 1 {
+			t.Errorf("streak = %v", resp["streak"])
+		}
+		if record, err := api.stores.Streaks.GetStreakRecord("learner1"); err != nil || record.CountedThrough != today {
+			t.Errorf("streak record after answering: %+v, %v", record, err)
+		}
+		learner.call(http.MethodPost, base+"/freezes", gin.H{"date": today}, http.StatusConflict)
+	})
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"sync"
+	"time"
+)
+
+// MemoryStore implements every store in process memory. It mirrors the SQL
+// stores' behaviour (soft deletes, ordering) and is meant for tests.
+type MemoryStore struct {
+	mu sync.Mutex
+
+	users     []models.User
+	sessions  []models.AuthSession
+	courses   []memoryRow[models.Course]
+	chapters  []memoryRow[models.Chapter]
+	videos    []memoryRow[models.Video]
+	questions []memoryRow[models.QuizQuestion]
+	prereqs   []memoryRow[models.ChapterPrerequisite]
+	pools     []memoryRow[models.QuestionPool]
+	rules     []memoryRow[models.QuizDrawRule]
+	attempts  []memoryRow[models.QuizAttempt]
+	answers   []memoryRow[models.QuizAnswer]
+	reviews   []memoryRow[models.ReviewItem]
+	progress  []memoryRow[models.Progress]
+
+	completions []memoryRow[models.ChapterCompletion]
+	watches     []memoryRow[models.VideoWatch]
+
+	// reviewAnswers is append-only, like the review_answers table
+	reviewAnswers []models.ReviewAnswer
+
+	// syncEvents is append-only, like the sync_events table
+	syncEvents []models.SyncEvent
+
+	// progressEvents is append-only, like the progress_events table
+	progressEvents []models.ProgressEvent
+
+	learningSessions []models.LearningSession
+
+	streakGoals   []models.StreakGoal
+	streakFreezes []models.StreakFreeze
+	streakRecords []models.StreakRecord
+
+	// attemptQuestions holds each attempt's drawn question IDs in order
+	attemptQuestions map[uint][]uint
+
+	// lastIDs holds the last ID handed out per table, so IDs count up per table as in SQL
+	lastIDs map[string]uint
+}
+
+// memoryRow pairs a model with its soft-delete marker
+type memoryRow[T any] struct {
+	value   T
+	deleted bool
+}
+
+// NewMemoryStores - Build stores backed by a fresh, empty MemoryStore
+func NewMemoryStores() *Stores {
+	s := &MemoryStore{}
+	return &Stores{
+		Users:    s,
+		Courses:  s,
+		Chapters: s,
+		Quiz:     s,
+		Reviews:  s,
+		Progress: s,
+		Streaks:  s,
+	}
+}
+
+func (s *MemoryStore) newID(table string) uint {
+	if s.lastIDs == nil {
+		s.lastIDs = map[string]uint{}
+	}
+	s.lastIDs[table]++
+	return s.lastIDs[table]
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
@@ -0,0 +1,354 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"sort"
+)
+
+func (s *MemoryStore) findChapter(chapterID uint) *memoryRow[models.Chapter] {
+	for i := range s.chapters {
+		if s.chapters[i].value.ID == chapterID && !s.chapters[i].deleted {
+			return &s.chapters[i]
+		}
+	}
+	return nil
+}
+
+// chapterByID finds a chapter even if it is soft-deleted, like the SQL joins do
+func (s *MemoryStore) chapterByID(chapterID uint) (models.Chapter, bool) {
+	for _, row := range s.chapters {
+		if row.value.ID == chapterID {
+			return row.value, true
+		}
+	}
+	return models.Chapter{}, false
+}
+
+func (s *MemoryStore) ListChapters(courseID uint) ([]models.Chapter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	courseOrder := map[uint]int{}
+	for _, row := range s.courses {
+		courseOrder[row.value.ID] = row.value.OrderIndex
+	}
+
+	var chapters []models.Chapter
+	for _, row := range s.chapters {
+		if !row.deleted && (courseID == 0 || row.value.CourseID == courseID) {
+			chapters = append(chapters, row.value)
+		}
+	}
+	sort.SliceStable(chapters, func(i, j int) bool {
+		if a, b := courseOrder[chapters[i].CourseID], courseOrder[chapters[j].CourseID]; a != b {
+			return a < b
+		}
+		return chapters[i].OrderIndex < chapters[j].OrderIndex
+	})
+	return chapters, nil
+}
+
+func (s *MemoryStore) GetChapter(chapterID uint) (*models.Chapter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findChapter(chapterID)
+	if row == nil {
+		return nil, ErrNotFound
+	}
+	chapter := row.value
+	return &chapter, nil
+}
+
+func (s *MemoryStore) ChapterExists(chapterID uint) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.findChapter(chapterID) != nil, nil
+}
+
+func (s *MemoryStore) CreateChapter(chapter *models.Chapter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chapter.ID = s.newID("chapters")
+	chapter.CreatedAt = now()
+	chapter.UpdatedAt = chapter.CreatedAt
+	s.chapters = append(s.chapters, memoryRow[models.Chapter]{value: *chapter})
+	return nil
+}
+
+func (s *MemoryStore) UpdateChapter(chapter *models.Chapter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findChapter(chapter.ID)
+	if row == nil {
+		return ErrNotFound
+	}
+	chapter.CreatedAt = row.value.CreatedAt
+	chapter.UpdatedAt = now()
+	row.value = *chapter
+	return nil
+}
+
+func (s *MemoryStore) DeleteChapter(chapterID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findChapter(chapterID)
+	if row == nil {
+		return ErrNotFound
+	}
+	row.deleted = true
+
+	for i := range s.videos {
+		if s.videos[i].value.ChapterID == chapterID {
+			s.videos[i].deleted = true
+		}
+	}
+	for i := range s.questions {
+		if s.questions[i].value.ChapterID == chapterID {
+			s.questions[i].deleted = true
+		}
+	}
+	for i := range s.pools {
+		if s.pools[i].value.ChapterID == chapterID {
+			s.pools[i].deleted = true
+		}
+	}
+	for i := range s.rules {
+		if s.rules[i].value.ChapterID == chapterID {
+			s.rules[i].deleted = true
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) ChapterOrderIndexTaken(courseID uint, orderIndex int, excludeChapterID uint) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, row := range s.chapters {
+		if !row.deleted && row.value.CourseID == courseID && row.value.OrderIndex == orderIndex && row.value.ID != excludeChapterID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *MemoryStore) GetChapterVideo(chapterID uint) (*models.Video, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, row := range s.videos {
+		if !row.deleted && row.value.ChapterID == chapterID {
+			video := row.value
+			return &video, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) ListVideos(courseID uint) ([]models.Video, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	live := map[uint]bool{}
+	for _, row := range s.chapters {
+		if !row.deleted && (courseID == 0 || row.value.CourseID == courseID) {
+			live[row.value.ID] = true
+		}
+	}
+
+	var videos []models.Video
+	for _, row := range s.videos {
+		if !row.deleted && live[row.value.ChapterID] {
+			videos = append(videos, row.value)
+		}
+	}
+	sort.SliceStable(videos, func(i, j int) bool { return videos[i].ChapterID < videos[j].ChapterID })
+	return videos, nil
+}
+
+func (s *MemoryStore) CreateVideo(video *models.Video) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	video.ID = s.newID("videos")
+	video.CreatedAt = now()
+	video.UpdatedAt = video.CreatedAt
+	s.videos = append(s.videos, memoryRow[models.Video]{value: *video})
+	return nil
+}
+
+func (s *MemoryStore) UpdateVideo(video *models.Video) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.videos {
+		row := &s.videos[i]
+		if row.value.ID == video.ID && !row.deleted {
+			video.ChapterID = row.value.ChapterID
+			video.CreatedAt = row.value.CreatedAt
+			video.UpdatedAt = now()
+			row.value = *video
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (s *MemoryStore) DeleteVideo(videoID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.videos {
+		if s.videos[i].value.ID == videoID && !s.videos[i].deleted {
+			s.videos[i].deleted = true
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (s *MemoryStore) findQuestion(questionID uint) *memoryRow[models.QuizQuestion] {
+	for i := range s.questions {
+		if s.questions[i].value.ID == questionID && !s.questions[i].deleted {
+			return &s.questions[i]
+		}
+	}
+	return nil
+}
+
+// chapterQuestions returns the chapter's live questions in order_index order (caller holds the lock)
+func (s *MemoryStore) chapterQuestions(chapterID uint) []models.QuizQuestion {
+	var questions []models.QuizQuestion
+	for _, row := range s.questions {
+		if !row.deleted && row.value.ChapterID == chapterID {
+			questions = append(questions, row.value)
+		}
+	}
+	sort.SliceStable(questions, func(i, j int) bool { return questions[i].OrderIndex < questions[j].OrderIndex })
+	return questions
+}
+
+func (s *MemoryStore) ListQuizQuestions(chapterID uint) ([]models.QuizQuestion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.chapterQuestions(chapterID), nil
+}
+
+func (s *MemoryStore) GetQuizQuestion(questionID uint) (*models.QuizQuestion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findQuestion(questionID)
+	if row == nil {
+		return nil, ErrNotFound
+	}
+	q := row.value
+	return &q, nil
+}
+
+func (s *MemoryStore) CreateQuizQuestion(q *models.QuizQuestion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q.ID = s.newID("quiz_questions")
+	q.CreatedAt = now()
+	q.UpdatedAt = q.CreatedAt
+	s.questions = append(s.questions, memoryRow[models.QuizQuestion]{value: *q})
+	return nil
+}
+
+func (s *MemoryStore) UpdateQuizQuestion(q *models.QuizQuestion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findQuestion(q.ID)
+	if row == nil {
+		return ErrNotFound
+	}
+	q.ChapterID = row.value.ChapterID
+	q.CreatedAt = row.value.CreatedAt
+	q.UpdatedAt = now()
+	row.value = *q
+	return nil
+}
+
+func (s *MemoryStore) DeleteQuizQuestion(questionID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findQuestion(questionID)
+	if row == nil {
+		return ErrNotFound
+	}
+	row.deleted = true
+	return nil
+}
+
+func (s *MemoryStore) QuestionOrderIndexTaken(chapterID uint, orderIndex int, excludeQuestionID uint) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, q := range s.chapterQuestions(chapterID) {
+		if q.OrderIndex == orderIndex && q.ID != excludeQuestionID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *MemoryStore) ListPrerequisites(chapterID uint) ([]models.ChapterPrerequisite, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var prerequisites []models.ChapterPrerequisite
+	for _, row := range s.prereqs {
+		if row.deleted || (chapterID != 0 && row.value.ChapterID != chapterID) {
+			continue
+		}
+		required := s.findChapter(row.value.RequiredChapterID)
+		if required == nil {
+			continue
+		}
+		p := row.value
+		p.RequiredChapterTitle = required.value.Title
+		prerequisites = append(prerequisites, p)
+	}
+	sort.SliceStable(prerequisites, func(i, j int) bool {
+		if prerequisites[i].ChapterID != prerequisites[j].ChapterID {
+			return prerequisites[i].ChapterID < prerequisites[j].ChapterID
+		}
+		return prerequisites[i].ID < prerequisites[j].ID
+	})
+	return prerequisites, nil
+}
+
+func (s *MemoryStore) CreatePrerequisite(p *models.ChapterPrerequisite) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p.ID = s.newID("chapter_prerequisites")
+	p.CreatedAt = now()
+	p.UpdatedAt = p.CreatedAt
+	if chapter, ok := s.chapterByID(p.RequiredChapterID); ok {
+		p.RequiredChapterTitle = chapter.Title
+	}
+	s.prereqs = append(s.prereqs, memoryRow[models.ChapterPrerequisite]{value: *p})
+	return nil
+}
+
+func (s *MemoryStore) DeletePrerequisite(prerequisiteID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.prereqs {
+		if s.prereqs[i].value.ID == prerequisiteID && !s.prereqs[i].deleted {
+			s.prereqs[i].deleted = true
+			return nil
+		}
+	}
+	return ErrNotFound
+}
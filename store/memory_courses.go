@@ -0,0 +1,96 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"sort"
+)
+
+func (s *MemoryStore) findCourse(courseID uint) *memoryRow[models.Course] {
+	for i := range s.courses {
+		if s.courses[i].value.ID == courseID && !s.courses[i].deleted {
+			return &s.courses[i]
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) ListCourses() ([]models.Course, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var courses []models.Course
+	for _, row := range s.courses {
+		if !row.deleted {
+			courses = append(courses, row.value)
+		}
+	}
+	sort.SliceStable(courses, func(i, j int) bool { return courses[i].OrderIndex < courses[j].OrderIndex })
+	return courses, nil
+}
+
+func (s *MemoryStore) GetCourse(courseID uint) (*models.Course, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findCourse(courseID)
+	if row == nil {
+		return nil, ErrNotFound
+	}
+	course := row.value
+	return &course, nil
+}
+
+func (s *MemoryStore) CourseExists(courseID uint) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.findCourse(courseID) != nil, nil
+}
+
+func (s *MemoryStore) CreateCourse(course *models.Course) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	course.ID = s.newID("courses")
+	course.CreatedAt = now()
+	course.UpdatedAt = course.CreatedAt
+	s.courses = append(s.courses, memoryRow[models.Course]{value: *course})
+	return nil
+}
+
+func (s *MemoryStore) UpdateCourse(course *models.Course) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findCourse(course.ID)
+	if row == nil {
+		return ErrNotFound
+	}
+	course.CreatedAt = row.value.CreatedAt
+	course.UpdatedAt = now()
+	row.value = *course
+	return nil
+}
+
+func (s *MemoryStore) DeleteCourse(courseID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findCourse(courseID)
+	if row == nil {
+		return ErrNotFound
+	}
+	row.deleted = true
+	return nil
+}
+
+func (s *MemoryStore) CourseOrderIndexTaken(orderIndex int, excludeCourseID uint) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, row := range s.courses {
+		if !row.deleted && row.value.OrderIndex == orderIndex && row.value.ID != excludeCourseID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
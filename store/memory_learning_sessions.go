@@ -0,0 +1,67 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"sort"
+	"time"
+)
+
+func (s *MemoryStore) FindLearningSession(userID string, chapterID uint, from, to time.Time) (*models.LearningSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found *models.LearningSession
+	for i := range s.learningSessions {
+		ls := &s.learningSessions[i]
+		if ls.UserID != userID || (chapterID != 0 && ls.ChapterID != chapterID) || ls.StartedAt.After(to) ||
+			ls.LastActivityAt.Before(from) {
+			continue
+		}
+		if found == nil || !ls.LastActivityAt.Before(found.LastActivityAt) {
+			found = ls
+		}
+	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	session := *found
+	return &session, nil
+}
+
+func (s *MemoryStore) ListLearningSessions(userID string, from, to time.Time) ([]models.LearningSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var sessions []models.LearningSession
+	for _, ls := range s.learningSessions {
+		if ls.UserID != userID || (!from.IsZero() && ls.StartedAt.Before(from)) ||
+			(!to.IsZero() && !ls.StartedAt.Before(to)) {
+			continue
+		}
+		sessions = append(sessions, ls)
+	}
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].StartedAt.Before(sessions[j].StartedAt) })
+	return sessions, nil
+}
+
+func (s *MemoryStore) SaveLearningSession(ls *models.LearningSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ls.ID == 0 {
+		ls.ID = s.newID("learning_sessions")
+		ls.CreatedAt = now()
+		ls.UpdatedAt = ls.CreatedAt
+		s.learningSessions = append(s.learningSessions, *ls)
+		return nil
+	}
+
+	for i := range s.learningSessions {
+		if s.learningSessions[i].ID == ls.ID {
+			ls.UpdatedAt = now()
+			s.learningSessions[i] = *ls
+			return nil
+		}
+	}
+	return ErrNotFound
+}
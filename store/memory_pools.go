@@ -0,0 +1,133 @@
+package store
+
+import "learning-app-backend/models"
+
+func (s *MemoryStore) findPool(poolID uint) *memoryRow[models.QuestionPool] {
+	for i := range s.pools {
+		if s.pools[i].value.ID == poolID && !s.pools[i].deleted {
+			return &s.pools[i]
+		}
+	}
+	return nil
+}
+
+// withQuestionCount fills in the pool's live question count (caller holds the lock)
+func (s *MemoryStore) withQuestionCount(pool models.QuestionPool) models.QuestionPool {
+	pool.QuestionCount = 0
+	for _, row := range s.questions {
+		if !row.deleted && row.value.PoolID != nil && *row.value.PoolID == pool.ID {
+			pool.QuestionCount++
+		}
+	}
+	return pool
+}
+
+func (s *MemoryStore) ListPools(chapterID uint) ([]models.QuestionPool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pools []models.QuestionPool
+	for _, row := range s.pools {
+		if !row.deleted && row.value.ChapterID == chapterID {
+			pools = append(pools, s.withQuestionCount(row.value))
+		}
+	}
+	return pools, nil
+}
+
+func (s *MemoryStore) GetPool(poolID uint) (*models.QuestionPool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findPool(poolID)
+	if row == nil {
+		return nil, ErrNotFound
+	}
+	pool := s.withQuestionCount(row.value)
+	return &pool, nil
+}
+
+func (s *MemoryStore) CreatePool(pool *models.QuestionPool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pool.ID = s.newID("question_pools")
+	pool.QuestionCount = 0
+	pool.CreatedAt = now()
+	pool.UpdatedAt = pool.CreatedAt
+	s.pools = append(s.pools, memoryRow[models.QuestionPool]{value: *pool})
+	return nil
+}
+
+func (s *MemoryStore) UpdatePool(pool *models.QuestionPool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findPool(pool.ID)
+	if row == nil {
+		return ErrNotFound
+	}
+	pool.ChapterID = row.value.ChapterID
+	pool.CreatedAt = row.value.CreatedAt
+	pool.UpdatedAt = now()
+	*pool = s.withQuestionCount(*pool)
+	row.value = *pool
+	return nil
+}
+
+func (s *MemoryStore) DeletePool(poolID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findPool(poolID)
+	if row == nil {
+		return ErrNotFound
+	}
+	row.deleted = true
+
+	for i := range s.rules {
+		if s.rules[i].value.PoolID == poolID {
+			s.rules[i].deleted = true
+		}
+	}
+	for i := range s.questions {
+		if q := &s.questions[i].value; q.PoolID != nil && *q.PoolID == poolID {
+			q.PoolID = nil
+			q.UpdatedAt = now()
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) ListDrawRules(chapterID uint) ([]models.QuizDrawRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rules []models.QuizDrawRule
+	for _, row := range s.rules {
+		if !row.deleted && row.value.ChapterID == chapterID {
+			rules = append(rules, row.value)
+		}
+	}
+	return rules, nil
+}
+
+func (s *MemoryStore) ReplaceDrawRules(chapterID uint, rules []models.QuizDrawRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.rules {
+		if s.rules[i].value.ChapterID == chapterID {
+			s.rules[i].deleted = true
+		}
+	}
+	for i := range rules {
+		r := &rules[i]
+		r.ID = s.newID("quiz_draw_rules")
+		r.ChapterID = chapterID
+		r.CreatedAt = now()
+		r.UpdatedAt = r.CreatedAt
+		s.rules = append(s.rules, memoryRow[models.QuizDrawRule]{value: *r})
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"sort"
+)
+
+func (s *MemoryStore) SaveProgress(p *models.Progress, event *models.ProgressEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := now()
+	lastUpdated := p.LastUpdated
+	if lastUpdated.IsZero() {
+		lastUpdated = t
+	}
+
+	saved := false
+	for i := range s.progress {
+		row := &s.progress[i]
+		if !row.deleted && row.value.UserID == p.UserID && row.value.ChapterID == p.ChapterID &&
+			row.value.ContentType == p.ContentType {
+			row.value.VideoTimestamp = p.VideoTimestamp
+			row.value.QuizQuestionIndex = p.QuizQuestionIndex
+			row.value.IsCompleted = p.IsCompleted
+			row.value.LastUpdated = lastUpdated
+			row.value.UpdatedAt = t
+			*p = row.value
+			saved = true
+			break
+		}
+	}
+	if !saved {
+		p.ID = s.newID("progresses")
+		p.LastUpdated = lastUpdated
+		p.CreatedAt = t
+		p.UpdatedAt = t
+		s.progress = append(s.progress, memoryRow[models.Progress]{value: *p})
+	}
+
+	// Log what was written
+	event.UserID = p.UserID
+	event.EventType = models.ProgressSaved
+	event.ChapterID = p.ChapterID
+	event.ContentType = p.ContentType
+	event.VideoTimestamp = p.VideoTimestamp
+	event.QuizQuestionIndex = p.QuizQuestionIndex
+	event.IsCompleted = p.IsCompleted
+	s.appendProgressEvent(event)
+	return nil
+}
+
+// userProgress returns the user's live progress rows (caller holds the lock)
+func (s *MemoryStore) userProgress(userID string) []models.Progress {
+	var records []models.Progress
+	for _, row := range s.progress {
+		if !row.deleted && row.value.UserID == userID {
+			records = append(records, row.value)
+		}
+	}
+	return records
+}
+
+func (s *MemoryStore) LatestProgress(userID string, courseID uint) (*models.Progress, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest *models.Progress
+	var chapterTitle string
+	records := s.userProgress(userID)
+	for i := range records {
+		chapter, ok := s.chapterByID(records[i].ChapterID)
+		if !ok || (courseID != 0 && chapter.CourseID != courseID) {
+			continue
+		}
+		if latest == nil || records[i].LastUpdated.After(latest.LastUpdated) {
+			latest = &records[i]
+			chapterTitle = chapter.Title
+		}
+	}
+	if latest == nil {
+		return nil, "", ErrNotFound
+	}
+	return latest, chapterTitle, nil
+}
+
+func (s *MemoryStore) ListChapterProgress(userID string, chapterID uint) ([]models.Progress, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []models.Progress
+	for _, p := range s.userProgress(userID) {
+		if p.ChapterID == chapterID {
+			records = append(records, p)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].LastUpdated.After(records[j].LastUpdated) })
+	return records, nil
+}
+
+func (s *MemoryStore) ListUserProgress(userID string) ([]models.Progress, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := s.userProgress(userID)
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].ChapterID != records[j].ChapterID {
+			return records[i].ChapterID < records[j].ChapterID
+		}
+		return records[i].ContentType < records[j].ContentType
+	})
+	return records, nil
+}
+
+func (s *MemoryStore) ResetProgress(userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var reset int64
+	for i := range s.progress {
+		if !s.progress[i].deleted && s.progress[i].value.UserID == userID {
+			s.progress[i].deleted = true
+			reset++
+		}
+	}
+	for i := range s.completions {
+		if s.completions[i].value.UserID == userID {
+			s.completions[i].deleted = true
+		}
+	}
+	for i := range s.watches {
+		if s.watches[i].value.UserID == userID {
+			s.watches[i].deleted = true
+		}
+	}
+	s.appendProgressEvent(&models.ProgressEvent{UserID: userID, EventType: models.ProgressReset})
+	return reset, nil
+}
+
+func (s *MemoryStore) ListCompletions(userID string) ([]models.ChapterCompletion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var completions []models.ChapterCompletion
+	for _, row := range s.completions {
+		if !row.deleted && row.value.UserID == userID {
+			completions = append(completions, row.value)
+		}
+	}
+	return completions, nil
+}
+
+func (s *MemoryStore) CreateCompletion(completion *models.ChapterCompletion) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, row := range s.completions {
+		if !row.deleted && row.value.UserID == completion.UserID && row.value.ChapterID == completion.ChapterID {
+			return nil
+		}
+	}
+	completion.ID = s.newID("chapter_completions")
+	completion.CompletedAt = now()
+	s.completions = append(s.completions, memoryRow[models.ChapterCompletion]{value: *completion})
+	return nil
+}
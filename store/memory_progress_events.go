@@ -0,0 +1,36 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"sort"
+)
+
+// appendProgressEvent adds an event to the progress log (caller holds the lock)
+func (s *MemoryStore) appendProgressEvent(event *models.ProgressEvent) {
+	t := now()
+	event.ID = s.newID("progress_events")
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = t
+	}
+	event.CreatedAt = t
+	s.progressEvents = append(s.progressEvents, *event)
+}
+
+func (s *MemoryStore) ListProgressEvents(filter ProgressEventFilter) ([]models.ProgressEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []models.ProgressEvent
+	for _, e := range s.progressEvents {
+		if e.UserID != filter.UserID ||
+			(filter.ChapterID != 0 && e.ChapterID != filter.ChapterID && e.EventType != models.ProgressReset) ||
+			(!filter.From.IsZero() && e.OccurredAt.Before(filter.From)) ||
+			(!filter.To.IsZero() && !e.OccurredAt.Before(filter.To)) ||
+			(!filter.RecordedTo.IsZero() && !e.CreatedAt.Before(filter.RecordedTo)) {
+			continue
+		}
+		events = append(events, e)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].OccurredAt.Before(events[j].OccurredAt) })
+	return events, nil
+}
@@ -0,0 +1,309 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"sort"
+)
+
+func (s *MemoryStore) CreateAttempt(attempt *models.QuizAttempt, questionIDs []uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt.ID = s.newID("quiz_attempts")
+	attempt.StartedAt = now()
+	attempt.CreatedAt = attempt.StartedAt
+	attempt.UpdatedAt = attempt.StartedAt
+	s.attempts = append(s.attempts, memoryRow[models.QuizAttempt]{value: *attempt})
+	if len(questionIDs) > 0 {
+		if s.attemptQuestions == nil {
+			s.attemptQuestions = map[uint][]uint{}
+		}
+		s.attemptQuestions[attempt.ID] = append([]uint(nil), questionIDs...)
+	}
+	return nil
+}
+
+func (s *MemoryStore) AttemptQuestionIDs(attemptID uint) ([]uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]uint(nil), s.attemptQuestions[attemptID]...), nil
+}
+
+func (s *MemoryStore) findAttempt(attemptID uint) *memoryRow[models.QuizAttempt] {
+	for i := range s.attempts {
+		if s.attempts[i].value.ID == attemptID && !s.attempts[i].deleted {
+			return &s.attempts[i]
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetAttempt(attemptID uint) (*models.QuizAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findAttempt(attemptID)
+	if row == nil {
+		return nil, ErrNotFound
+	}
+	a := row.value
+	return &a, nil
+}
+
+// userAttempts returns the user's live attempts newest first (caller holds the lock)
+func (s *MemoryStore) userAttempts(userID string, chapterID uint) []models.QuizAttempt {
+	var attempts []models.QuizAttempt
+	for _, row := range s.attempts {
+		if !row.deleted && row.value.UserID == userID && (chapterID == 0 || row.value.ChapterID == chapterID) {
+			attempts = append(attempts, row.value)
+		}
+	}
+	sort.SliceStable(attempts, func(i, j int) bool {
+		if !attempts[i].StartedAt.Equal(attempts[j].StartedAt) {
+			return attempts[i].StartedAt.After(attempts[j].StartedAt)
+		}
+		return attempts[i].ID > attempts[j].ID
+	})
+	return attempts
+}
+
+func (s *MemoryStore) ActiveAttempt(userID string, chapterID uint) (*models.QuizAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.userAttempts(userID, chapterID) {
+		if a.Status == models.AttemptInProgress {
+			return &a, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) FinishAttempt(attempt *models.QuizAttempt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findAttempt(attempt.ID)
+	if row == nil || row.value.Status != models.AttemptInProgress {
+		return ErrNotFound
+	}
+	finishedAt := now()
+	row.value.Status = models.AttemptFinished
+	row.value.TotalQuestions = attempt.TotalQuestions
+	row.value.CorrectAnswers = attempt.CorrectAnswers
+	row.value.ScorePercent = attempt.ScorePercent
+	row.value.FinishedAt = &finishedAt
+	row.value.UpdatedAt = finishedAt
+	*attempt = row.value
+	return nil
+}
+
+func (s *MemoryStore) ListAttempts(userID string, chapterID uint) ([]models.QuizAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.userAttempts(userID, chapterID), nil
+}
+
+func (s *MemoryStore) StartedAttempts(userID string, chapterID uint) (map[uint]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	started := map[uint]int{}
+	for _, row := range s.attempts {
+		if row.value.UserID == userID && (chapterID == 0 || row.value.ChapterID == chapterID) {
+			started[row.value.ChapterID]++
+		}
+	}
+	return started, nil
+}
+
+func (s *MemoryStore) CreateAnswer(answer *models.QuizAnswer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	answer.ID = s.newID("quiz_answers")
+	answer.CreatedAt = now()
+	answer.UpdatedAt = answer.CreatedAt
+	if answer.AnsweredAt.IsZero() {
+		answer.AnsweredAt = answer.CreatedAt
+	}
+	s.answers = append(s.answers, memoryRow[models.QuizAnswer]{value: *answer})
+	return nil
+}
+
+// userAnswers returns the user's live answers newest first (caller holds the lock)
+func (s *MemoryStore) userAnswers(userID string, match func(models.QuizAnswer) bool) []models.QuizAnswer {
+	return s.userAnswersWithCleared(userID, false, match)
+}
+
+// userAnswersWithCleared - The user's answers, newest first, optionally with soft-deleted ones
+func (s *MemoryStore) userAnswersWithCleared(userID string, cleared bool, match func(models.QuizAnswer) bool) []models.QuizAnswer {
+	var answers []models.QuizAnswer
+	for _, row := range s.answers {
+		if (cleared || !row.deleted) && row.value.UserID == userID && match(row.value) {
+			answers = append(answers, row.value)
+		}
+	}
+	sort.SliceStable(answers, func(i, j int) bool {
+		if !answers[i].AnsweredAt.Equal(answers[j].AnsweredAt) {
+			return answers[i].AnsweredAt.After(answers[j].AnsweredAt)
+		}
+		return answers[i].ID > answers[j].ID
+	})
+	return answers
+}
+
+func (s *MemoryStore) ListAnswers(filter AnswerFilter) ([]models.QuizAnswerWithDetails, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	answers := s.userAnswersWithCleared(filter.UserID, filter.IncludeCleared, func(a models.QuizAnswer) bool {
+		return (filter.ChapterID == 0 || a.ChapterID == filter.ChapterID) &&
+			(filter.QuestionID == 0 || a.QuizQuestionID == filter.QuestionID) &&
+			(filter.AttemptID == 0 || (a.AttemptID != nil && *a.AttemptID == filter.AttemptID))
+	})
+	sort.SliceStable(answers, func(i, j int) bool { return answers[i].ChapterID < answers[j].ChapterID })
+
+	var details []models.QuizAnswerWithDetails
+	for _, a := range answers {
+		// Answers keep pointing at their question even after it is soft-deleted
+		for _, row := range s.questions {
+			if row.value.ID == a.QuizQuestionID {
+				details = append(details, answerWithDetails(a, row.value))
+				break
+			}
+		}
+	}
+	return details, nil
+}
+
+func (s *MemoryStore) ChapterScores(userID string, courseID uint) ([]models.QuizHistorySummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Only the latest answer to each question counts, so retries do not inflate the totals
+	byChapter := map[uint]*models.QuizHistorySummary{}
+	counted := map[uint]bool{}
+	for _, a := range s.userAnswers(userID, func(models.QuizAnswer) bool { return true }) {
+		chapter, ok := s.chapterByID(a.ChapterID)
+		if !ok || (courseID != 0 && chapter.CourseID != courseID) || counted[a.QuizQuestionID] {
+			continue
+		}
+		counted[a.QuizQuestionID] = true
+		sum, ok := byChapter[a.ChapterID]
+		if !ok {
+			sum = &models.QuizHistorySummary{ChapterID: a.ChapterID, ChapterTitle: chapter.Title}
+			byChapter[a.ChapterID] = sum
+		}
+		sum.TotalAnswered++
+		if a.IsCorrect {
+			sum.TotalCorrect++
+		} else {
+			sum.TotalWrong++
+		}
+	}
+
+	attempts := s.userAttempts(userID, 0)
+	for i := len(attempts) - 1; i >= 0; i-- {
+		a := attempts[i]
+		chapter, ok := s.chapterByID(a.ChapterID)
+		if a.Status != models.AttemptFinished || !ok || (courseID != 0 && chapter.CourseID != courseID) {
+			continue
+		}
+		addAttemptScore(byChapter, a.ChapterID, chapter.Title, a.ScorePercent)
+	}
+	return sortedSummaries(byChapter), nil
+}
+
+func (s *MemoryStore) ListQuestionsWithLatestAnswer(userID string, chapterID uint) ([]models.QuizQuestionWithUserAnswer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chapterIDs := []uint{chapterID}
+	if chapterID == 0 {
+		chapterIDs = nil
+		seen := map[uint]bool{}
+		for _, row := range s.questions {
+			if !row.deleted && !seen[row.value.ChapterID] {
+				seen[row.value.ChapterID] = true
+				chapterIDs = append(chapterIDs, row.value.ChapterID)
+			}
+		}
+		sort.Slice(chapterIDs, func(i, j int) bool { return chapterIDs[i] < chapterIDs[j] })
+	}
+
+	var questions []models.QuizQuestionWithUserAnswer
+	for _, id := range chapterIDs {
+		for _, q := range s.chapterQuestions(id) {
+			withAnswer := questionWithUserAnswer(q)
+
+			answers := s.userAnswers(userID, func(a models.QuizAnswer) bool { return a.QuizQuestionID == q.ID })
+			withAnswer.TimesAttempted = len(answers)
+			if len(answers) > 0 {
+				revealAnswer(&withAnswer, q, answers[0])
+			}
+			questions = append(questions, withAnswer)
+		}
+	}
+	return questions, nil
+}
+
+func (s *MemoryStore) FirstUnansweredQuestion(userID string, chapterID uint) (*models.QuizQuestion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, q := range s.chapterQuestions(chapterID) {
+		answers := s.userAnswers(userID, func(a models.QuizAnswer) bool { return a.QuizQuestionID == q.ID })
+		if len(answers) == 0 {
+			return &q, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) ClearAttemptAnswers(attemptID, questionID uint) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var cleared int64
+	for i := range s.answers {
+		row := &s.answers[i]
+		if !row.deleted && row.value.AttemptID != nil && *row.value.AttemptID == attemptID &&
+			row.value.QuizQuestionID == questionID {
+			row.deleted = true
+			cleared++
+		}
+	}
+	return cleared, nil
+}
+
+func (s *MemoryStore) ClearAnswers(userID string, chapterID uint) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var cleared int64
+	for i := range s.answers {
+		row := &s.answers[i]
+		if !row.deleted && row.value.UserID == userID && (chapterID == 0 || row.value.ChapterID == chapterID) {
+			row.deleted = true
+			cleared++
+		}
+	}
+
+	// Attempts go with their answers
+	for i := range s.attempts {
+		row := &s.attempts[i]
+		if row.value.UserID == userID && (chapterID == 0 || row.value.ChapterID == chapterID) {
+			row.deleted = true
+		}
+	}
+
+	// So do the review schedules built from them
+	for i := range s.reviews {
+		row := &s.reviews[i]
+		if row.value.UserID == userID && (chapterID == 0 || row.value.ChapterID == chapterID) {
+			row.deleted = true
+		}
+	}
+	return cleared, nil
+}
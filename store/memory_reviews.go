@@ -0,0 +1,101 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"sort"
+	"time"
+)
+
+func (s *MemoryStore) findReviewItem(userID string, questionID uint) *memoryRow[models.ReviewItem] {
+	for i := range s.reviews {
+		row := &s.reviews[i]
+		if !row.deleted && row.value.UserID == userID && row.value.QuizQuestionID == questionID {
+			return row
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetReviewItem(userID string, questionID uint) (*models.ReviewItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.findReviewItem(userID, questionID)
+	if row == nil {
+		return nil, ErrNotFound
+	}
+	item := row.value
+	return &item, nil
+}
+
+func (s *MemoryStore) SaveReviewItem(item *models.ReviewItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item.ID == 0 {
+		item.ID = s.newID("review_items")
+		item.CreatedAt = now()
+		item.UpdatedAt = item.CreatedAt
+		s.reviews = append(s.reviews, memoryRow[models.ReviewItem]{value: *item})
+		return nil
+	}
+	return s.updateReviewItem(item)
+}
+
+// updateReviewItem stores the item's schedule (caller holds the lock)
+func (s *MemoryStore) updateReviewItem(item *models.ReviewItem) error {
+	row := s.findReviewItem(item.UserID, item.QuizQuestionID)
+	if row == nil || row.value.ID != item.ID {
+		return ErrNotFound
+	}
+	item.CreatedAt = row.value.CreatedAt
+	item.UpdatedAt = now()
+	row.value = *item
+	return nil
+}
+
+func (s *MemoryStore) RecordReview(item *models.ReviewItem, answer *models.ReviewAnswer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.updateReviewItem(item); err != nil {
+		return err
+	}
+	answer.ID = s.newID("review_answers")
+	answer.ReviewItemID = item.ID
+	answer.ReviewedAt = now()
+	s.reviewAnswers = append(s.reviewAnswers, *answer)
+	return nil
+}
+
+func (s *MemoryStore) ListDueReviews(userID string, before time.Time, limit int) ([]models.DueReview, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var reviews []models.DueReview
+	for _, row := range s.reviews {
+		if row.deleted || row.value.UserID != userID || !row.value.DueAt.Before(before) {
+			continue
+		}
+		question := s.findQuestion(row.value.QuizQuestionID)
+		chapter := s.findChapter(row.value.ChapterID)
+		if question == nil || chapter == nil {
+			continue
+		}
+		reviews = append(reviews, models.DueReview{
+			ReviewItem:   row.value,
+			ChapterTitle: chapter.value.Title,
+			Question:     question.value.LearnerView(),
+		})
+	}
+	sort.SliceStable(reviews, func(i, j int) bool {
+		if !reviews[i].DueAt.Equal(reviews[j].DueAt) {
+			return reviews[i].DueAt.Before(reviews[j].DueAt)
+		}
+		return reviews[i].ID < reviews[j].ID
+	})
+	if limit > 0 && len(reviews) > limit {
+		reviews = reviews[:limit]
+	}
+	return reviews, nil
+}
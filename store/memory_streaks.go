@@ -0,0 +1,132 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"sort"
+	"time"
+)
+
+func (s *MemoryStore) ListStreakGoals(userID string) ([]models.StreakGoal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var goals []models.StreakGoal
+	for _, g := range s.streakGoals {
+		if g.UserID == userID {
+			goals = append(goals, g)
+		}
+	}
+	sort.Slice(goals, func(i, j int) bool { return goals[i].EffectiveFrom < goals[j].EffectiveFrom })
+	return goals, nil
+}
+
+func (s *MemoryStore) SaveStreakGoal(goal *models.StreakGoal) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.streakGoals {
+		existing := &s.streakGoals[i]
+		if existing.UserID == goal.UserID && existing.EffectiveFrom == goal.EffectiveFrom {
+			goal.ID = existing.ID
+			goal.CreatedAt = existing.CreatedAt
+			goal.UpdatedAt = now()
+			*existing = *goal
+			return nil
+		}
+	}
+
+	goal.ID = s.newID("streak_goals")
+	goal.CreatedAt = now()
+	goal.UpdatedAt = goal.CreatedAt
+	s.streakGoals = append(s.streakGoals, *goal)
+	return nil
+}
+
+func (s *MemoryStore) ListStreakFreezes(userID string) ([]models.StreakFreeze, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var freezes []models.StreakFreeze
+	for _, f := range s.streakFreezes {
+		if f.UserID == userID {
+			freezes = append(freezes, f)
+		}
+	}
+	sort.Slice(freezes, func(i, j int) bool { return freezes[i].Date < freezes[j].Date })
+	return freezes, nil
+}
+
+func (s *MemoryStore) CreateStreakFreeze(freeze *models.StreakFreeze) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, f := range s.streakFreezes {
+		if f.UserID == freeze.UserID && f.Date == freeze.Date {
+			return nil
+		}
+	}
+	freeze.ID = s.newID("streak_freezes")
+	freeze.CreatedAt = now()
+	s.streakFreezes = append(s.streakFreezes, *freeze)
+	return nil
+}
+
+func (s *MemoryStore) DeleteStreakFreeze(userID, date string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.streakFreezes {
+		if f.UserID == userID && f.Date == date {
+			s.streakFreezes = append(s.streakFreezes[:i], s.streakFreezes[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (s *MemoryStore) ListAnswerTimes(userID string, from time.Time) ([]time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Cleared answers included, like the SQL store
+	var times []time.Time
+	for _, row := range s.answers {
+		if row.value.UserID == userID && !row.value.AnsweredAt.Before(from) {
+			times = append(times, row.value.AnsweredAt)
+		}
+	}
+	for _, answer := range s.reviewAnswers {
+		if answer.UserID == userID && !answer.ReviewedAt.Before(from) {
+			times = append(times, answer.ReviewedAt)
+		}
+	}
+	return times, nil
+}
+
+func (s *MemoryStore) GetStreakRecord(userID string) (*models.StreakRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.streakRecords {
+		if r.UserID == userID {
+			record := r
+			return &record, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) SaveStreakRecord(record *models.StreakRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record.UpdatedAt = now()
+	for i := range s.streakRecords {
+		if s.streakRecords[i].UserID == record.UserID {
+			s.streakRecords[i] = *record
+			return nil
+		}
+	}
+	s.streakRecords = append(s.streakRecords, *record)
+	return nil
+}
@@ -0,0 +1,59 @@
+package store
+
+import "learning-app-backend/models"
+
+func (s *MemoryStore) GetSyncEvent(userID, clientEventID string) (*models.SyncEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.syncEvents {
+		if e.UserID == userID && e.ClientEventID == clientEventID {
+			event := e
+			return &event, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) ClaimSyncEvent(event *models.SyncEvent) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.syncEvents {
+		if e.UserID == event.UserID && e.ClientEventID == event.ClientEventID {
+			return false, nil
+		}
+	}
+	event.ID = s.newID("sync_events")
+	event.CreatedAt = now()
+	s.syncEvents = append(s.syncEvents, *event)
+	return true, nil
+}
+
+func (s *MemoryStore) FinishSyncEvent(event *models.SyncEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.syncEvents {
+		if e := &s.syncEvents[i]; e.ID == event.ID {
+			e.Status = event.Status
+			e.Message = event.Message
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (s *MemoryStore) ReleaseSyncEvent(userID, clientEventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.syncEvents[:0]
+	for _, e := range s.syncEvents {
+		if e.UserID != userID || e.ClientEventID != clientEventID {
+			kept = append(kept, e)
+		}
+	}
+	s.syncEvents = kept
+	return nil
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"learning-app-backend/auth"
+	"learning-app-backend/models"
+	"sort"
+	"time"
+)
+
+func (s *MemoryStore) findUser(userID string) *models.User {
+	for i := range s.users {
+		if s.users[i].UserID == userID {
+			return &s.users[i]
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetUser(userID string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.findUser(userID)
+	if u == nil {
+		return nil, ErrNotFound
+	}
+	user := *u
+	return &user, nil
+}
+
+func (s *MemoryStore) UserExists(userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.findUser(userID) != nil, nil
+}
+
+func (s *MemoryStore) CreateUser(userID, username, passwordHash string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := now()
+	user := models.User{
+		ID:           s.newID("users"),
+		UserID:       userID,
+		Username:     username,
+		Role:         auth.RoleLearner,
+		PasswordHash: passwordHash,
+		CreatedAt:    t,
+		UpdatedAt:    t,
+	}
+	s.users = append(s.users, user)
+	return &user, nil
+}
+
+func (s *MemoryStore) ListUsers(role string) ([]models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var users []models.User
+	for _, u := range s.users {
+		if role == "" || u.Role == role {
+			users = append(users, u)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].UserID < users[j].UserID })
+	return users, nil
+}
+
+func (s *MemoryStore) UpdateUserRole(userID, role string) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.findUser(userID)
+	if u == nil {
+		return nil, ErrNotFound
+	}
+	u.Role = role
+	u.UpdatedAt = now()
+	user := *u
+	return &user, nil
+}
+
+func (s *MemoryStore) CreateSession(userID, refreshTokenHash string, expiresAt time.Time) (*models.AuthSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := now()
+	session := models.AuthSession{
+		ID:               s.newID("auth_sessions"),
+		UserID:           userID,
+		RefreshTokenHash: refreshTokenHash,
+		ExpiresAt:        expiresAt.UTC(),
+		CreatedAt:        t,
+		UpdatedAt:        t,
+	}
+	s.sessions = append(s.sessions, session)
+	return &session, nil
+}
+
+func (s *MemoryStore) activeSession(match func(*models.AuthSession) bool) *models.AuthSession {
+	t := now()
+	for i := range s.sessions {
+		session := &s.sessions[i]
+		if session.RevokedAt == nil && session.ExpiresAt.After(t) && match(session) {
+			return session
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) FindActiveSessionByRefreshHash(refreshTokenHash string) (*models.AuthSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session := s.activeSession(func(a *models.AuthSession) bool { return a.RefreshTokenHash == refreshTokenHash })
+	if session == nil {
+		return nil, ErrNotFound
+	}
+	found := *session
+	return &found, nil
+}
+
+func (s *MemoryStore) RotateSession(sessionID uint, refreshTokenHash string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.sessions {
+		if s.sessions[i].ID == sessionID {
+			s.sessions[i].RefreshTokenHash = refreshTokenHash
+			s.sessions[i].ExpiresAt = expiresAt.UTC()
+			s.sessions[i].UpdatedAt = now()
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (s *MemoryStore) RevokeSession(sessionID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.sessions {
+		if s.sessions[i].ID == sessionID && s.sessions[i].RevokedAt == nil {
+			t := now()
+			s.sessions[i].RevokedAt = &t
+			s.sessions[i].UpdatedAt = t
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) ActiveSessionRole(sessionID uint, userID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session := s.activeSession(func(a *models.AuthSession) bool { return a.ID == sessionID && a.UserID == userID })
+	if session == nil {
+		return "", ErrNotFound
+	}
+	u := s.findUser(userID)
+	if u == nil {
+		return "", ErrNotFound
+	}
+	return u.Role, nil
+}
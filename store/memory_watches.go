@@ -0,0 +1,82 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"sort"
+)
+
+// liveWatch copies a watch of a live video and measures it (caller holds the lock)
+func (s *MemoryStore) liveWatch(w models.VideoWatch) (models.VideoWatch, bool) {
+	for _, row := range s.videos {
+		if !row.deleted && row.value.ID == w.VideoID {
+			w.Ranges = append([]models.WatchRange{}, w.Ranges...)
+			w.SetDuration(row.value.DurationSeconds)
+			return w, true
+		}
+	}
+	return w, false
+}
+
+func (s *MemoryStore) GetVideoWatch(userID string, videoID uint) (*models.VideoWatch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, row := range s.watches {
+		if !row.deleted && row.value.UserID == userID && row.value.VideoID == videoID {
+			if w, ok := s.liveWatch(row.value); ok {
+				return &w, nil
+			}
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryStore) ListVideoWatches(userID string, chapterID uint) ([]models.VideoWatch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var watches []models.VideoWatch
+	for _, row := range s.watches {
+		if row.deleted || row.value.UserID != userID || (chapterID != 0 && row.value.ChapterID != chapterID) {
+			continue
+		}
+		if w, ok := s.liveWatch(row.value); ok {
+			watches = append(watches, w)
+		}
+	}
+	sort.SliceStable(watches, func(i, j int) bool { return watches[i].ChapterID < watches[j].ChapterID })
+	return watches, nil
+}
+
+func (s *MemoryStore) UpdateVideoWatch(userID string, chapterID, videoID uint, update func(watch *models.VideoWatch)) (*models.VideoWatch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.liveWatch(models.VideoWatch{VideoID: videoID}); !ok {
+		return nil, ErrNotFound
+	}
+	var row *memoryRow[models.VideoWatch]
+	for i := range s.watches {
+		if !s.watches[i].deleted && s.watches[i].value.UserID == userID && s.watches[i].value.VideoID == videoID {
+			row = &s.watches[i]
+			break
+		}
+	}
+	if row == nil {
+		created := models.VideoWatch{ID: s.newID("video_watches"), UserID: userID, ChapterID: chapterID,
+			VideoID: videoID, CreatedAt: now()}
+		created.UpdatedAt = created.CreatedAt
+		s.watches = append(s.watches, memoryRow[models.VideoWatch]{value: created})
+		row = &s.watches[len(s.watches)-1]
+	}
+	w, _ := s.liveWatch(row.value)
+
+	update(&w)
+	w.UpdatedAt = now()
+	row.value.Ranges = append([]models.WatchRange{}, w.Ranges...)
+	row.value.WatchedSeconds = w.WatchedSeconds
+	row.value.LastPosition = w.LastPosition
+	row.value.LastHeartbeatAt = w.LastHeartbeatAt
+	row.value.UpdatedAt = w.UpdatedAt
+	return &w, nil
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"sort"
+)
+
+// Helpers shared by the SQL and in-memory quiz stores
+
+// answerWithDetails joins an answer with the question it was given for
+func answerWithDetails(a models.QuizAnswer, q models.QuizQuestion) models.QuizAnswerWithDetails {
+	return models.QuizAnswerWithDetails{
+		QuizAnswer:    a,
+		QuestionType:  q.QuestionType,
+		QuestionText:  q.QuestionText,
+		CorrectAnswer: q.CorrectAnswerText(),
+		Explanation:   q.Explanation,
+		Options:       q.ReviewOptions(),
+		Feedback:      q.AnswerFeedback(a.UserAnswer),
+	}
+}
+
+// questionWithUserAnswer is the learner view of q before any answer is attached; revealAnswer
+// adds what the learner may see once they have answered
+func questionWithUserAnswer(q models.QuizQuestion) models.QuizQuestionWithUserAnswer {
+	return models.QuizQuestionWithUserAnswer{
+		ID:           q.ID,
+		ChapterID:    q.ChapterID,
+		QuestionType: q.QuestionType,
+		QuestionText: q.QuestionText,
+		Options:      q.DisplayOptions(),
+		OrderIndex:   q.OrderIndex,
+		CreatedAt:    q.CreatedAt,
+		UpdatedAt:    q.UpdatedAt,
+	}
+}
+
+// revealAnswer attaches the user's latest answer to view, with the correct answer, explanation and feedback
+func revealAnswer(view *models.QuizQuestionWithUserAnswer, q models.QuizQuestion, latest models.QuizAnswer) {
+	correctAnswer := q.CorrectAnswerText()
+	view.HasAnswered = true
+	view.UserAnswer = &latest.UserAnswer
+	view.IsCorrect = &latest.IsCorrect
+	view.AnsweredAt = &latest.AnsweredAt
+	view.CorrectAnswer = &correctAnswer
+	view.Explanation = q.Explanation
+	view.Options = q.ReviewOptions()
+}
+
+// addAttemptScore folds one finished attempt into its chapter's summary (feed attempts oldest first)
+func addAttemptScore(byChapter map[uint]*models.QuizHistorySummary, chapterID uint, title string, score float64) {
+	sum, ok := byChapter[chapterID]
+	if !ok {
+		sum = &models.QuizHistorySummary{ChapterID: chapterID, ChapterTitle: title}
+		byChapter[chapterID] = sum
+	}
+	sum.AttemptsFinished++
+	if sum.BestScorePercent == nil || score > *sum.BestScorePercent {
+		best := score
+		sum.BestScorePercent = &best
+	}
+	latest := score
+	sum.LatestScorePercent = &latest
+}
+
+// sortedSummaries fills in percentages and orders the summaries by chapter
+func sortedSummaries(byChapter map[uint]*models.QuizHistorySummary) []models.QuizHistorySummary {
+	var summaries []models.QuizHistorySummary
+	for _, sum := range byChapter {
+		if sum.TotalAnswered > 0 {
+			sum.Percentage = (float64(sum.TotalCorrect) / float64(sum.TotalAnswered)) * 100
+		}
+		summaries = append(summaries, *sum)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].ChapterID < summaries[j].ChapterID })
+	return summaries
+}
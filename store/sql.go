@@ -0,0 +1,44 @@
+package store
+
+import (
+	"database/sql"
+	"learning-app-backend/database"
+)
+
+// SQLStore implements every store with raw SQL against Postgres or SQLite
+type SQLStore struct {
+	db *database.Conn
+}
+
+// NewSQLStores - Build the SQL-backed stores on top of a dialect-aware connection
+func NewSQLStores(db *database.Conn) *Stores {
+	s := &SQLStore{db: db}
+	return &Stores{
+		Users:    s,
+		Courses:  s,
+		Chapters: s,
+		Quiz:     s,
+		Reviews:  s,
+		Progress: s,
+		Streaks:  s,
+	}
+}
+
+// notFound maps sql.ErrNoRows to ErrNotFound
+func notFound(err error) error {
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	return err
+}
+
+// requireAffected returns ErrNotFound when an UPDATE touched no rows
+func requireAffected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,361 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"learning-app-backend/models"
+)
+
+const chapterColumns = `ch.id, COALESCE(ch.course_id, 0), ch.title, ch.description, ch.order_index,
+			  ch.quiz_time_limit_seconds, ch.quiz_late_policy, ch.shuffle_questions, ch.shuffle_options,
+			  ch.max_answer_attempts, ch.answer_cooldown_seconds, ch.max_quiz_attempts, ch.min_video_percent, ch.pass_score_percent,
+			  ch.created_at, ch.updated_at`
+
+func scanChapter(row interface{ Scan(...interface{}) error }, ch *models.Chapter) error {
+	return row.Scan(&ch.ID, &ch.CourseID, &ch.Title, &ch.Description, &ch.OrderIndex,
+		&ch.QuizTimeLimitSeconds, &ch.QuizLatePolicy, &ch.ShuffleQuestions, &ch.ShuffleOptions,
+		&ch.MaxAnswerAttempts, &ch.AnswerCooldownSeconds, &ch.MaxQuizAttempts, &ch.MinVideoPercent, &ch.PassScorePercent,
+		&ch.CreatedAt, &ch.UpdatedAt)
+}
+
+func (s *SQLStore) ListChapters(courseID uint) ([]models.Chapter, error) {
+	query := `SELECT ` + chapterColumns + `
+			  FROM chapters ch
+			  LEFT JOIN courses co ON co.id = ch.course_id
+			  WHERE ch.deleted_at IS NULL AND ($1 = 0 OR ch.course_id = $1)
+			  ORDER BY co.order_index ASC, ch.order_index ASC`
+
+	rows, err := s.db.Query(query, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chapters []models.Chapter
+	for rows.Next() {
+		var ch models.Chapter
+		if err := scanChapter(rows, &ch); err != nil {
+			continue
+		}
+		chapters = append(chapters, ch)
+	}
+	return chapters, rows.Err()
+}
+
+func (s *SQLStore) GetChapter(chapterID uint) (*models.Chapter, error) {
+	var chapter models.Chapter
+	query := `SELECT ` + chapterColumns + `
+			  FROM chapters ch WHERE ch.id = $1 AND ch.deleted_at IS NULL`
+
+	if err := scanChapter(s.db.QueryRow(query, chapterID), &chapter); err != nil {
+		return nil, notFound(err)
+	}
+	return &chapter, nil
+}
+
+func (s *SQLStore) ChapterExists(chapterID uint) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM chapters WHERE id = $1 AND deleted_at IS NULL)`
+	err := s.db.QueryRow(query, chapterID).Scan(&exists)
+	return exists, err
+}
+
+func (s *SQLStore) CreateChapter(chapter *models.Chapter) error {
+	query := `INSERT INTO chapters (course_id, title, description, order_index, quiz_time_limit_seconds,
+			  quiz_late_policy, shuffle_questions, shuffle_options, max_answer_attempts,
+			  answer_cooldown_seconds, max_quiz_attempts, min_video_percent, pass_score_percent, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW())
+			  RETURNING id, created_at, updated_at`
+
+	return s.db.QueryRow(query, chapter.CourseID, chapter.Title, chapter.Description, chapter.OrderIndex,
+		chapter.QuizTimeLimitSeconds, chapter.QuizLatePolicy, chapter.ShuffleQuestions, chapter.ShuffleOptions,
+		chapter.MaxAnswerAttempts, chapter.AnswerCooldownSeconds, chapter.MaxQuizAttempts, chapter.MinVideoPercent, chapter.PassScorePercent).Scan(
+		&chapter.ID, &chapter.CreatedAt, &chapter.UpdatedAt,
+	)
+}
+
+func (s *SQLStore) UpdateChapter(chapter *models.Chapter) error {
+	query := `UPDATE chapters SET course_id = $1, title = $2, description = $3, order_index = $4,
+			  quiz_time_limit_seconds = $5, quiz_late_policy = $6, shuffle_questions = $7,
+			  shuffle_options = $8, max_answer_attempts = $9, answer_cooldown_seconds = $10,
+			  max_quiz_attempts = $11, min_video_percent = $12, pass_score_percent = $13, updated_at = NOW()
+			  WHERE id = $14 AND deleted_at IS NULL
+			  RETURNING created_at, updated_at`
+
+	err := s.db.QueryRow(query, chapter.CourseID, chapter.Title, chapter.Description, chapter.OrderIndex,
+		chapter.QuizTimeLimitSeconds, chapter.QuizLatePolicy, chapter.ShuffleQuestions, chapter.ShuffleOptions,
+		chapter.MaxAnswerAttempts, chapter.AnswerCooldownSeconds, chapter.MaxQuizAttempts, chapter.MinVideoPercent, chapter.PassScorePercent,
+		chapter.ID).Scan(
+		&chapter.CreatedAt, &chapter.UpdatedAt,
+	)
+	return notFound(err)
+}
+
+func (s *SQLStore) DeleteChapter(chapterID uint) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = requireAffected(tx.Exec(`UPDATE chapters SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, chapterID))
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE videos SET deleted_at = NOW() WHERE chapter_id = $1 AND deleted_at IS NULL`, chapterID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE quiz_questions SET deleted_at = NOW() WHERE chapter_id = $1 AND deleted_at IS NULL`, chapterID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE question_pools SET deleted_at = NOW() WHERE chapter_id = $1 AND deleted_at IS NULL`, chapterID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE quiz_draw_rules SET deleted_at = NOW() WHERE chapter_id = $1 AND deleted_at IS NULL`, chapterID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) ChapterOrderIndexTaken(courseID uint, orderIndex int, excludeChapterID uint) (bool, error) {
+	var taken bool
+	query := `SELECT EXISTS(SELECT 1 FROM chapters
+			  WHERE course_id = $1 AND order_index = $2 AND id <> $3 AND deleted_at IS NULL)`
+	err := s.db.QueryRow(query, courseID, orderIndex, excludeChapterID).Scan(&taken)
+	return taken, err
+}
+
+func (s *SQLStore) GetChapterVideo(chapterID uint) (*models.Video, error) {
+	var video models.Video
+	query := `SELECT id, chapter_id, title, video_url, duration_seconds, created_at, updated_at
+			  FROM videos WHERE chapter_id = $1 AND deleted_at IS NULL`
+
+	err := s.db.QueryRow(query, chapterID).Scan(
+		&video.ID, &video.ChapterID, &video.Title, &video.VideoURL,
+		&video.DurationSeconds, &video.CreatedAt, &video.UpdatedAt,
+	)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &video, nil
+}
+
+func (s *SQLStore) ListVideos(courseID uint) ([]models.Video, error) {
+	query := `SELECT v.id, v.chapter_id, v.title, v.video_url, v.duration_seconds, v.created_at, v.updated_at
+			  FROM videos v
+			  JOIN chapters ch ON v.chapter_id = ch.id
+			  WHERE v.deleted_at IS NULL AND ch.deleted_at IS NULL AND ($1 = 0 OR ch.course_id = $1)
+			  ORDER BY v.chapter_id ASC`
+
+	rows, err := s.db.Query(query, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var videos []models.Video
+	for rows.Next() {
+		var video models.Video
+		err := rows.Scan(&video.ID, &video.ChapterID, &video.Title, &video.VideoURL,
+			&video.DurationSeconds, &video.CreatedAt, &video.UpdatedAt)
+		if err == nil {
+			videos = append(videos, video)
+		}
+	}
+	return videos, rows.Err()
+}
+
+func (s *SQLStore) CreateVideo(video *models.Video) error {
+	query := `INSERT INTO videos (chapter_id, title, video_url, duration_seconds, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, NOW(), NOW())
+			  RETURNING id, created_at, updated_at`
+
+	return s.db.QueryRow(query, video.ChapterID, video.Title, video.VideoURL, video.DurationSeconds).Scan(
+		&video.ID, &video.CreatedAt, &video.UpdatedAt,
+	)
+}
+
+func (s *SQLStore) UpdateVideo(video *models.Video) error {
+	query := `UPDATE videos SET title = $1, video_url = $2, duration_seconds = $3, updated_at = NOW()
+			  WHERE id = $4 AND deleted_at IS NULL
+			  RETURNING chapter_id, created_at, updated_at`
+
+	err := s.db.QueryRow(query, video.Title, video.VideoURL, video.DurationSeconds, video.ID).Scan(
+		&video.ChapterID, &video.CreatedAt, &video.UpdatedAt,
+	)
+	return notFound(err)
+}
+
+func (s *SQLStore) DeleteVideo(videoID uint) error {
+	return requireAffected(s.db.Exec(`UPDATE videos SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, videoID))
+}
+
+const quizQuestionColumns = `id, chapter_id, question_type, question_text, options, answer_key,
+			  explanation, max_attempts, pool_id, topic, difficulty, order_index, created_at, updated_at`
+
+func scanQuizQuestion(row interface{ Scan(...interface{}) error }, q *models.QuizQuestion) error {
+	var options, answerKey string
+	var poolID sql.NullInt64
+	if err := row.Scan(&q.ID, &q.ChapterID, &q.QuestionType, &q.QuestionText, &options, &answerKey,
+		&q.Explanation, &q.MaxAttempts, &poolID, &q.Topic, &q.Difficulty, &q.OrderIndex, &q.CreatedAt, &q.UpdatedAt); err != nil {
+		return err
+	}
+	if poolID.Valid {
+		id := uint(poolID.Int64)
+		q.PoolID = &id
+	}
+	return decodeQuestionJSON(q, options, answerKey)
+}
+
+// decodeQuestionJSON - Fill the question's options and answer key from their JSON columns
+func decodeQuestionJSON(q *models.QuizQuestion, options, answerKey string) error {
+	if err := json.Unmarshal([]byte(options), &q.Options); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(answerKey), &q.AnswerKey)
+}
+
+// encodeQuestionJSON - Serialize the question's options and answer key for storage
+func encodeQuestionJSON(q *models.QuizQuestion) (string, string, error) {
+	options := q.Options
+	if options == nil {
+		options = []models.QuestionOption{}
+	}
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		return "", "", err
+	}
+	answerKeyJSON, err := json.Marshal(q.AnswerKey)
+	if err != nil {
+		return "", "", err
+	}
+	return string(optionsJSON), string(answerKeyJSON), nil
+}
+
+func (s *SQLStore) ListQuizQuestions(chapterID uint) ([]models.QuizQuestion, error) {
+	query := `SELECT ` + quizQuestionColumns + `
+			  FROM quiz_questions WHERE chapter_id = $1 AND deleted_at IS NULL
+			  ORDER BY order_index ASC`
+
+	rows, err := s.db.Query(query, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []models.QuizQuestion
+	for rows.Next() {
+		var q models.QuizQuestion
+		if err := scanQuizQuestion(rows, &q); err != nil {
+			continue
+		}
+		questions = append(questions, q)
+	}
+	return questions, rows.Err()
+}
+
+func (s *SQLStore) GetQuizQuestion(questionID uint) (*models.QuizQuestion, error) {
+	var q models.QuizQuestion
+	query := `SELECT ` + quizQuestionColumns + `
+			  FROM quiz_questions WHERE id = $1 AND deleted_at IS NULL`
+
+	if err := scanQuizQuestion(s.db.QueryRow(query, questionID), &q); err != nil {
+		return nil, notFound(err)
+	}
+	return &q, nil
+}
+
+func (s *SQLStore) CreateQuizQuestion(q *models.QuizQuestion) error {
+	options, answerKey, err := encodeQuestionJSON(q)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO quiz_questions (chapter_id, question_type, question_text, options, answer_key,
+			  explanation, max_attempts, pool_id, topic, difficulty, order_index, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW())
+			  RETURNING id, created_at, updated_at`
+
+	return s.db.QueryRow(query, q.ChapterID, q.QuestionType, q.QuestionText, options,
+		answerKey, q.Explanation, q.MaxAttempts, q.PoolID, q.Topic, q.Difficulty, q.OrderIndex).Scan(
+		&q.ID, &q.CreatedAt, &q.UpdatedAt,
+	)
+}
+
+func (s *SQLStore) UpdateQuizQuestion(q *models.QuizQuestion) error {
+	options, answerKey, err := encodeQuestionJSON(q)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE quiz_questions SET question_type = $1, question_text = $2, options = $3,
+			  answer_key = $4, explanation = $5, max_attempts = $6, pool_id = $7, topic = $8,
+			  difficulty = $9, order_index = $10, updated_at = NOW()
+			  WHERE id = $11 AND deleted_at IS NULL
+			  RETURNING chapter_id, created_at, updated_at`
+
+	err = s.db.QueryRow(query, q.QuestionType, q.QuestionText, options, answerKey,
+		q.Explanation, q.MaxAttempts, q.PoolID, q.Topic, q.Difficulty, q.OrderIndex, q.ID).Scan(
+		&q.ChapterID, &q.CreatedAt, &q.UpdatedAt,
+	)
+	return notFound(err)
+}
+
+func (s *SQLStore) DeleteQuizQuestion(questionID uint) error {
+	return requireAffected(s.db.Exec(`UPDATE quiz_questions SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, questionID))
+}
+
+func (s *SQLStore) QuestionOrderIndexTaken(chapterID uint, orderIndex int, excludeQuestionID uint) (bool, error) {
+	var taken bool
+	query := `SELECT EXISTS(SELECT 1 FROM quiz_questions
+			  WHERE chapter_id = $1 AND order_index = $2 AND id <> $3 AND deleted_at IS NULL)`
+	err := s.db.QueryRow(query, chapterID, orderIndex, excludeQuestionID).Scan(&taken)
+	return taken, err
+}
+
+func (s *SQLStore) ListPrerequisites(chapterID uint) ([]models.ChapterPrerequisite, error) {
+	query := `SELECT p.id, p.chapter_id, p.required_chapter_id, ch.title, p.requirement_type,
+			  p.min_score_percent, p.created_at, p.updated_at
+			  FROM chapter_prerequisites p
+			  JOIN chapters ch ON ch.id = p.required_chapter_id AND ch.deleted_at IS NULL
+			  WHERE p.deleted_at IS NULL AND ($1 = 0 OR p.chapter_id = $1)
+			  ORDER BY p.chapter_id ASC, p.id ASC`
+
+	rows, err := s.db.Query(query, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prerequisites []models.ChapterPrerequisite
+	for rows.Next() {
+		var p models.ChapterPrerequisite
+		err := rows.Scan(&p.ID, &p.ChapterID, &p.RequiredChapterID, &p.RequiredChapterTitle, &p.Type,
+			&p.MinScorePercent, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			continue
+		}
+		prerequisites = append(prerequisites, p)
+	}
+	return prerequisites, rows.Err()
+}
+
+func (s *SQLStore) CreatePrerequisite(p *models.ChapterPrerequisite) error {
+	query := `INSERT INTO chapter_prerequisites (chapter_id, required_chapter_id, requirement_type,
+			  min_score_percent, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, NOW(), NOW())
+			  RETURNING id, created_at, updated_at`
+
+	err := s.db.QueryRow(query, p.ChapterID, p.RequiredChapterID, p.Type, p.MinScorePercent).Scan(
+		&p.ID, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return s.db.QueryRow(`SELECT title FROM chapters WHERE id = $1`, p.RequiredChapterID).Scan(&p.RequiredChapterTitle)
+}
+
+func (s *SQLStore) DeletePrerequisite(prerequisiteID uint) error {
+	return requireAffected(s.db.Exec(`UPDATE chapter_prerequisites SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, prerequisiteID))
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"learning-app-backend/models"
+)
+
+const courseColumns = `id, title, description, order_index, created_at, updated_at`
+
+func scanCourse(row interface{ Scan(...interface{}) error }, c *models.Course) error {
+	return row.Scan(&c.ID, &c.Title, &c.Description, &c.OrderIndex, &c.CreatedAt, &c.UpdatedAt)
+}
+
+func (s *SQLStore) ListCourses() ([]models.Course, error) {
+	query := `SELECT ` + courseColumns + `
+			  FROM courses WHERE deleted_at IS NULL ORDER BY order_index ASC`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var courses []models.Course
+	for rows.Next() {
+		var course models.Course
+		if err := scanCourse(rows, &course); err != nil {
+			continue
+		}
+		courses = append(courses, course)
+	}
+	return courses, rows.Err()
+}
+
+func (s *SQLStore) GetCourse(courseID uint) (*models.Course, error) {
+	var course models.Course
+	query := `SELECT ` + courseColumns + `
+			  FROM courses WHERE id = $1 AND deleted_at IS NULL`
+
+	if err := scanCourse(s.db.QueryRow(query, courseID), &course); err != nil {
+		return nil, notFound(err)
+	}
+	return &course, nil
+}
+
+func (s *SQLStore) CourseExists(courseID uint) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)`
+	err := s.db.QueryRow(query, courseID).Scan(&exists)
+	return exists, err
+}
+
+func (s *SQLStore) CreateCourse(course *models.Course) error {
+	query := `INSERT INTO courses (title, description, order_index, created_at, updated_at)
+			  VALUES ($1, $2, $3, NOW(), NOW())
+			  RETURNING id, created_at, updated_at`
+
+	return s.db.QueryRow(query, course.Title, course.Description, course.OrderIndex).Scan(
+		&course.ID, &course.CreatedAt, &course.UpdatedAt,
+	)
+}
+
+func (s *SQLStore) UpdateCourse(course *models.Course) error {
+	query := `UPDATE courses SET title = $1, description = $2, order_index = $3, updated_at = NOW()
+			  WHERE id = $4 AND deleted_at IS NULL
+			  RETURNING created_at, updated_at`
+
+	err := s.db.QueryRow(query, course.Title, course.Description, course.OrderIndex, course.ID).Scan(
+		&course.CreatedAt, &course.UpdatedAt,
+	)
+	return notFound(err)
+}
+
+func (s *SQLStore) DeleteCourse(courseID uint) error {
+	return requireAffected(s.db.Exec(`UPDATE courses SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, courseID))
+}
+
+func (s *SQLStore) CourseOrderIndexTaken(orderIndex int, excludeCourseID uint) (bool, error) {
+	var taken bool
+	query := `SELECT EXISTS(SELECT 1 FROM courses
+			  WHERE order_index = $1 AND id <> $2 AND deleted_at IS NULL)`
+	err := s.db.QueryRow(query, orderIndex, excludeCourseID).Scan(&taken)
+	return taken, err
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"strconv"
+	"time"
+)
+
+const learningSessionColumns = `id, user_id, chapter_id, started_at, last_activity_at, active_seconds,
+			  watched_seconds, activity_count, created_at, updated_at`
+
+func scanLearningSession(row interface{ Scan(...interface{}) error }, ls *models.LearningSession) error {
+	return row.Scan(&ls.ID, &ls.UserID, &ls.ChapterID, &ls.StartedAt, &ls.LastActivityAt, &ls.ActiveSeconds,
+		&ls.WatchedSeconds, &ls.ActivityCount, &ls.CreatedAt, &ls.UpdatedAt)
+}
+
+func (s *SQLStore) FindLearningSession(userID string, chapterID uint, from, to time.Time) (*models.LearningSession, error) {
+	var ls models.LearningSession
+	query := `SELECT ` + learningSessionColumns + `
+			  FROM learning_sessions
+			  WHERE user_id = $1 AND ($2 = 0 OR chapter_id = $2) AND started_at <= $3 AND last_activity_at >= $4
+			  ORDER BY last_activity_at DESC, id DESC LIMIT 1`
+
+	if err := scanLearningSession(s.db.QueryRow(query, userID, chapterID, to.UTC(), from.UTC()), &ls); err != nil {
+		return nil, notFound(err)
+	}
+	return &ls, nil
+}
+
+func (s *SQLStore) ListLearningSessions(userID string, from, to time.Time) ([]models.LearningSession, error) {
+	query := `SELECT ` + learningSessionColumns + `
+			  FROM learning_sessions
+			  WHERE user_id = $1`
+	args := []interface{}{userID}
+	if !from.IsZero() {
+		args = append(args, from.UTC())
+		query += ` AND started_at >= $` + strconv.Itoa(len(args))
+	}
+	if !to.IsZero() {
+		args = append(args, to.UTC())
+		query += ` AND started_at < $` + strconv.Itoa(len(args))
+	}
+	query += ` ORDER BY started_at ASC, id ASC`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []models.LearningSession
+	for rows.Next() {
+		var ls models.LearningSession
+		if err := scanLearningSession(rows, &ls); err == nil {
+			sessions = append(sessions, ls)
+		}
+	}
+	return sessions, rows.Err()
+}
+
+func (s *SQLStore) SaveLearningSession(ls *models.LearningSession) error {
+	if ls.ID == 0 {
+		query := `INSERT INTO learning_sessions (user_id, chapter_id, started_at, last_activity_at, active_seconds,
+				  watched_seconds, activity_count, created_at, updated_at)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+				  RETURNING id, created_at, updated_at`
+
+		return s.db.QueryRow(query, ls.UserID, ls.ChapterID, ls.StartedAt.UTC(), ls.LastActivityAt.UTC(),
+			ls.ActiveSeconds, ls.WatchedSeconds, ls.ActivityCount).Scan(&ls.ID, &ls.CreatedAt, &ls.UpdatedAt)
+	}
+
+	query := `UPDATE learning_sessions SET started_at = $1, last_activity_at = $2, active_seconds = $3,
+			  watched_seconds = $4, activity_count = $5, updated_at = NOW()
+			  WHERE id = $6
+			  RETURNING updated_at`
+
+	err := s.db.QueryRow(query, ls.StartedAt.UTC(), ls.LastActivityAt.UTC(), ls.ActiveSeconds,
+		ls.WatchedSeconds, ls.ActivityCount, ls.ID).Scan(&ls.UpdatedAt)
+	return notFound(err)
+}
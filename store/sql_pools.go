@@ -0,0 +1,142 @@
+package store
+
+import "learning-app-backend/models"
+
+const questionPoolColumns = `p.id, p.chapter_id, p.title, p.description,
+			  (SELECT COUNT(*) FROM quiz_questions q WHERE q.pool_id = p.id AND q.deleted_at IS NULL),
+			  p.created_at, p.updated_at`
+
+func scanQuestionPool(row interface{ Scan(...interface{}) error }, p *models.QuestionPool) error {
+	return row.Scan(&p.ID, &p.ChapterID, &p.Title, &p.Description, &p.QuestionCount, &p.CreatedAt, &p.UpdatedAt)
+}
+
+func (s *SQLStore) ListPools(chapterID uint) ([]models.QuestionPool, error) {
+	query := `SELECT ` + questionPoolColumns + `
+			  FROM question_pools p
+			  WHERE p.chapter_id = $1 AND p.deleted_at IS NULL
+			  ORDER BY p.id ASC`
+
+	rows, err := s.db.Query(query, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pools []models.QuestionPool
+	for rows.Next() {
+		var p models.QuestionPool
+		if err := scanQuestionPool(rows, &p); err != nil {
+			continue
+		}
+		pools = append(pools, p)
+	}
+	return pools, rows.Err()
+}
+
+func (s *SQLStore) GetPool(poolID uint) (*models.QuestionPool, error) {
+	var p models.QuestionPool
+	query := `SELECT ` + questionPoolColumns + `
+			  FROM question_pools p WHERE p.id = $1 AND p.deleted_at IS NULL`
+
+	if err := scanQuestionPool(s.db.QueryRow(query, poolID), &p); err != nil {
+		return nil, notFound(err)
+	}
+	return &p, nil
+}
+
+func (s *SQLStore) CreatePool(pool *models.QuestionPool) error {
+	query := `INSERT INTO question_pools (chapter_id, title, description, created_at, updated_at)
+			  VALUES ($1, $2, $3, NOW(), NOW())
+			  RETURNING id, created_at, updated_at`
+
+	return s.db.QueryRow(query, pool.ChapterID, pool.Title, pool.Description).Scan(
+		&pool.ID, &pool.CreatedAt, &pool.UpdatedAt,
+	)
+}
+
+func (s *SQLStore) UpdatePool(pool *models.QuestionPool) error {
+	query := `UPDATE question_pools SET title = $1, description = $2, updated_at = NOW()
+			  WHERE id = $3 AND deleted_at IS NULL
+			  RETURNING chapter_id, created_at, updated_at`
+
+	err := s.db.QueryRow(query, pool.Title, pool.Description, pool.ID).Scan(
+		&pool.ChapterID, &pool.CreatedAt, &pool.UpdatedAt,
+	)
+	if err != nil {
+		return notFound(err)
+	}
+	return s.db.QueryRow(`SELECT COUNT(*) FROM quiz_questions WHERE pool_id = $1 AND deleted_at IS NULL`, pool.ID).Scan(&pool.QuestionCount)
+}
+
+func (s *SQLStore) DeletePool(poolID uint) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = requireAffected(tx.Exec(`UPDATE question_pools SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, poolID))
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE quiz_draw_rules SET deleted_at = NOW() WHERE pool_id = $1 AND deleted_at IS NULL`, poolID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE quiz_questions SET pool_id = NULL, updated_at = NOW() WHERE pool_id = $1`, poolID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) ListDrawRules(chapterID uint) ([]models.QuizDrawRule, error) {
+	query := `SELECT id, chapter_id, pool_id, draw_count, topic, difficulty, created_at, updated_at
+			  FROM quiz_draw_rules
+			  WHERE chapter_id = $1 AND deleted_at IS NULL
+			  ORDER BY id ASC`
+
+	rows, err := s.db.Query(query, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.QuizDrawRule
+	for rows.Next() {
+		var r models.QuizDrawRule
+		err := rows.Scan(&r.ID, &r.ChapterID, &r.PoolID, &r.DrawCount, &r.Topic, &r.Difficulty,
+			&r.CreatedAt, &r.UpdatedAt)
+		if err != nil {
+			continue
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+func (s *SQLStore) ReplaceDrawRules(chapterID uint, rules []models.QuizDrawRule) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE quiz_draw_rules SET deleted_at = NOW() WHERE chapter_id = $1 AND deleted_at IS NULL`, chapterID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO quiz_draw_rules (chapter_id, pool_id, draw_count, topic, difficulty, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+			  RETURNING id, created_at, updated_at`
+	for i := range rules {
+		r := &rules[i]
+		r.ChapterID = chapterID
+		err := tx.QueryRow(query, chapterID, r.PoolID, r.DrawCount, r.Topic, r.Difficulty).Scan(
+			&r.ID, &r.CreatedAt, &r.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,200 @@
+package store
+
+import (
+	"database/sql"
+	"learning-app-backend/models"
+)
+
+const progressColumns = `id, user_id, chapter_id, content_type, video_timestamp,
+			  quiz_question_index, is_completed, last_updated, created_at, updated_at`
+
+func scanProgress(row interface{ Scan(...interface{}) error }, p *models.Progress) error {
+	return row.Scan(&p.ID, &p.UserID, &p.ChapterID, &p.ContentType,
+		&p.VideoTimestamp, &p.QuizQuestionIndex, &p.IsCompleted,
+		&p.LastUpdated, &p.CreatedAt, &p.UpdatedAt)
+}
+
+func (s *SQLStore) SaveProgress(p *models.Progress, event *models.ProgressEvent) error {
+	var lastUpdated interface{}
+	if !p.LastUpdated.IsZero() {
+		lastUpdated = p.LastUpdated.UTC()
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Check if progress exists
+	var progressID uint
+	checkQuery := `SELECT id FROM progresses
+				   WHERE user_id = $1 AND chapter_id = $2 AND content_type = $3 AND deleted_at IS NULL`
+	err = tx.QueryRow(checkQuery, p.UserID, p.ChapterID, p.ContentType).Scan(&progressID)
+
+	if err = notFound(err); err == ErrNotFound {
+		// Create new progress
+		insertQuery := `INSERT INTO progresses (user_id, chapter_id, content_type, video_timestamp,
+				   quiz_question_index, is_completed, last_updated, created_at, updated_at)
+				   VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, NOW()), NOW(), NOW())
+				   RETURNING ` + progressColumns
+
+		err = scanProgress(tx.QueryRow(insertQuery, p.UserID, p.ChapterID, p.ContentType,
+			p.VideoTimestamp, p.QuizQuestionIndex, p.IsCompleted, lastUpdated), p)
+	} else if err == nil {
+		// Update existing progress
+		updateQuery := `UPDATE progresses SET video_timestamp = $1, quiz_question_index = $2,
+						is_completed = $3, last_updated = COALESCE($4, NOW()), updated_at = NOW()
+						WHERE id = $5
+						RETURNING ` + progressColumns
+
+		err = scanProgress(tx.QueryRow(updateQuery, p.VideoTimestamp, p.QuizQuestionIndex,
+			p.IsCompleted, lastUpdated, progressID), p)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Log what was written
+	event.UserID = p.UserID
+	event.EventType = models.ProgressSaved
+	event.ChapterID = p.ChapterID
+	event.ContentType = p.ContentType
+	event.VideoTimestamp = p.VideoTimestamp
+	event.QuizQuestionIndex = p.QuizQuestionIndex
+	event.IsCompleted = p.IsCompleted
+	if err := insertProgressEvent(tx, event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) LatestProgress(userID string, courseID uint) (*models.Progress, string, error) {
+	query := `SELECT p.id, p.user_id, p.chapter_id, p.content_type, p.video_timestamp,
+			  p.quiz_question_index, p.is_completed, p.last_updated, p.created_at, p.updated_at, ch.title
+			  FROM progresses p
+			  JOIN chapters ch ON p.chapter_id = ch.id
+			  WHERE p.user_id = $1 AND p.deleted_at IS NULL AND ($2 = 0 OR ch.course_id = $2)
+			  ORDER BY p.last_updated DESC LIMIT 1`
+
+	var p models.Progress
+	var chapterTitle string
+	err := s.db.QueryRow(query, userID, courseID).Scan(&p.ID, &p.UserID, &p.ChapterID, &p.ContentType,
+		&p.VideoTimestamp, &p.QuizQuestionIndex, &p.IsCompleted,
+		&p.LastUpdated, &p.CreatedAt, &p.UpdatedAt, &chapterTitle)
+	if err != nil {
+		return nil, "", notFound(err)
+	}
+	return &p, chapterTitle, nil
+}
+
+func (s *SQLStore) ListChapterProgress(userID string, chapterID uint) ([]models.Progress, error) {
+	query := `SELECT ` + progressColumns + `
+			  FROM progresses
+			  WHERE user_id = $1 AND chapter_id = $2 AND deleted_at IS NULL
+			  ORDER BY last_updated DESC`
+	return s.queryProgress(query, userID, chapterID)
+}
+
+func (s *SQLStore) ListUserProgress(userID string) ([]models.Progress, error) {
+	query := `SELECT ` + progressColumns + `
+			  FROM progresses
+			  WHERE user_id = $1 AND deleted_at IS NULL
+			  ORDER BY chapter_id ASC, content_type ASC`
+	return s.queryProgress(query, userID)
+}
+
+func (s *SQLStore) ResetProgress(userID string) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Soft delete
+	query := `UPDATE progresses SET deleted_at = NOW() WHERE user_id = $1 AND deleted_at IS NULL`
+
+	result, err := tx.Exec(query, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, table := range []string{"chapter_completions", "video_watches"} {
+		_, err = tx.Exec(`UPDATE `+table+` SET deleted_at = NOW() WHERE user_id = $1 AND deleted_at IS NULL`, userID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := insertProgressEvent(tx, &models.ProgressEvent{UserID: userID, EventType: models.ProgressReset}); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (s *SQLStore) ListCompletions(userID string) ([]models.ChapterCompletion, error) {
+	query := `SELECT id, user_id, chapter_id, video_percent, quiz_score_percent, completed_at
+			  FROM chapter_completions
+			  WHERE user_id = $1 AND deleted_at IS NULL
+			  ORDER BY completed_at ASC, id ASC`
+
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var completions []models.ChapterCompletion
+	for rows.Next() {
+		var c models.ChapterCompletion
+		var videoPercent, quizScore sql.NullFloat64
+		err := rows.Scan(&c.ID, &c.UserID, &c.ChapterID, &videoPercent, &quizScore, &c.CompletedAt)
+		if err != nil {
+			continue
+		}
+		if videoPercent.Valid {
+			c.VideoPercent = &videoPercent.Float64
+		}
+		if quizScore.Valid {
+			c.QuizScorePercent = &quizScore.Float64
+		}
+		completions = append(completions, c)
+	}
+	return completions, rows.Err()
+}
+
+func (s *SQLStore) CreateCompletion(completion *models.ChapterCompletion) error {
+	query := `INSERT INTO chapter_completions (user_id, chapter_id, video_percent, quiz_score_percent,
+			  completed_at, created_at)
+			  VALUES ($1, $2, $3, $4, NOW(), NOW())
+			  ON CONFLICT (user_id, chapter_id) WHERE deleted_at IS NULL DO NOTHING
+			  RETURNING id, completed_at`
+
+	err := s.db.QueryRow(query, completion.UserID, completion.ChapterID, completion.VideoPercent,
+		completion.QuizScorePercent).Scan(&completion.ID, &completion.CompletedAt)
+	if err == sql.ErrNoRows {
+		// Already completed earlier
+		return nil
+	}
+	return err
+}
+
+func (s *SQLStore) queryProgress(query string, args ...interface{}) ([]models.Progress, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var progressRecords []models.Progress
+	for rows.Next() {
+		var p models.Progress
+		if err := scanProgress(rows, &p); err == nil {
+			progressRecords = append(progressRecords, p)
+		}
+	}
+	return progressRecords, rows.Err()
+}
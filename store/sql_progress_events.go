@@ -0,0 +1,70 @@
+package store
+
+import (
+	"database/sql"
+	"learning-app-backend/database"
+	"learning-app-backend/models"
+	"strconv"
+)
+
+// insertProgressEvent appends an event to the progress log inside the caller's transaction
+func insertProgressEvent(tx *database.Tx, event *models.ProgressEvent) error {
+	var occurredAt interface{}
+	if !event.OccurredAt.IsZero() {
+		occurredAt = event.OccurredAt.UTC()
+	}
+	var chapterID interface{}
+	if event.ChapterID != 0 {
+		chapterID = event.ChapterID
+	}
+
+	query := `INSERT INTO progress_events (user_id, event_type, source, chapter_id, content_type,
+			  video_timestamp, quiz_question_index, is_completed, occurred_at, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, NOW()), NOW())
+			  RETURNING id, occurred_at, created_at`
+
+	return tx.QueryRow(query, event.UserID, event.EventType, event.Source, chapterID, event.ContentType,
+		event.VideoTimestamp, event.QuizQuestionIndex, event.IsCompleted, occurredAt).Scan(
+		&event.ID, &event.OccurredAt, &event.CreatedAt)
+}
+
+func (s *SQLStore) ListProgressEvents(filter ProgressEventFilter) ([]models.ProgressEvent, error) {
+	query := `SELECT id, user_id, event_type, source, chapter_id, content_type, video_timestamp,
+			  quiz_question_index, is_completed, occurred_at, created_at
+			  FROM progress_events
+			  WHERE user_id = $1 AND ($2 = 0 OR chapter_id = $2 OR event_type = 'reset')`
+	args := []interface{}{filter.UserID, filter.ChapterID}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From.UTC())
+		query += ` AND occurred_at >= $` + strconv.Itoa(len(args))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To.UTC())
+		query += ` AND occurred_at < $` + strconv.Itoa(len(args))
+	}
+	if !filter.RecordedTo.IsZero() {
+		args = append(args, filter.RecordedTo.UTC())
+		query += ` AND created_at < $` + strconv.Itoa(len(args))
+	}
+	query += ` ORDER BY occurred_at ASC, id ASC`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.ProgressEvent
+	for rows.Next() {
+		var e models.ProgressEvent
+		var chapterID sql.NullInt64
+		err := rows.Scan(&e.ID, &e.UserID, &e.EventType, &e.Source, &chapterID, &e.ContentType,
+			&e.VideoTimestamp, &e.QuizQuestionIndex, &e.IsCompleted, &e.OccurredAt, &e.CreatedAt)
+		if err != nil {
+			continue
+		}
+		e.ChapterID = uint(chapterID.Int64)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
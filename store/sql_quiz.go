@@ -0,0 +1,399 @@
+package store
+
+import (
+	"database/sql"
+	"learning-app-backend/models"
+	"time"
+)
+
+const quizAttemptColumns = `id, user_id, chapter_id, status, total_questions, correct_answers,
+			  score_percent, started_at, deadline_at, finished_at, created_at, updated_at`
+
+func scanQuizAttempt(row interface{ Scan(...interface{}) error }, a *models.QuizAttempt) error {
+	var deadlineAt, finishedAt sql.NullTime
+	err := row.Scan(&a.ID, &a.UserID, &a.ChapterID, &a.Status, &a.TotalQuestions, &a.CorrectAnswers,
+		&a.ScorePercent, &a.StartedAt, &deadlineAt, &finishedAt, &a.CreatedAt, &a.UpdatedAt)
+	if deadlineAt.Valid {
+		a.DeadlineAt = &deadlineAt.Time
+	}
+	if finishedAt.Valid {
+		a.FinishedAt = &finishedAt.Time
+	}
+	return err
+}
+
+func (s *SQLStore) CreateAttempt(attempt *models.QuizAttempt, questionIDs []uint) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO quiz_attempts (user_id, chapter_id, status, total_questions, deadline_at,
+			  started_at, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, NOW(), NOW(), NOW())
+			  RETURNING id, started_at, created_at, updated_at`
+
+	err = tx.QueryRow(query, attempt.UserID, attempt.ChapterID, attempt.Status,
+		attempt.TotalQuestions, attempt.DeadlineAt).Scan(
+		&attempt.ID, &attempt.StartedAt, &attempt.CreatedAt, &attempt.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	for position, questionID := range questionIDs {
+		_, err := tx.Exec(`INSERT INTO quiz_attempt_questions (attempt_id, quiz_question_id, position)
+			  VALUES ($1, $2, $3)`, attempt.ID, questionID, position)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLStore) AttemptQuestionIDs(attemptID uint) ([]uint, error) {
+	rows, err := s.db.Query(`SELECT quiz_question_id FROM quiz_attempt_questions
+			  WHERE attempt_id = $1 ORDER BY position ASC`, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *SQLStore) GetAttempt(attemptID uint) (*models.QuizAttempt, error) {
+	var a models.QuizAttempt
+	query := `SELECT ` + quizAttemptColumns + `
+			  FROM quiz_attempts WHERE id = $1 AND deleted_at IS NULL`
+
+	if err := scanQuizAttempt(s.db.QueryRow(query, attemptID), &a); err != nil {
+		return nil, notFound(err)
+	}
+	return &a, nil
+}
+
+func (s *SQLStore) ActiveAttempt(userID string, chapterID uint) (*models.QuizAttempt, error) {
+	var a models.QuizAttempt
+	query := `SELECT ` + quizAttemptColumns + `
+			  FROM quiz_attempts
+			  WHERE user_id = $1 AND chapter_id = $2 AND status = $3 AND deleted_at IS NULL
+			  ORDER BY started_at DESC, id DESC
+			  LIMIT 1`
+
+	if err := scanQuizAttempt(s.db.QueryRow(query, userID, chapterID, models.AttemptInProgress), &a); err != nil {
+		return nil, notFound(err)
+	}
+	return &a, nil
+}
+
+func (s *SQLStore) FinishAttempt(attempt *models.QuizAttempt) error {
+	query := `UPDATE quiz_attempts SET status = $1, total_questions = $2, correct_answers = $3,
+			  score_percent = $4, finished_at = NOW(), updated_at = NOW()
+			  WHERE id = $5 AND status = $6 AND deleted_at IS NULL
+			  RETURNING finished_at, updated_at`
+
+	var finishedAt time.Time
+	err := s.db.QueryRow(query, models.AttemptFinished, attempt.TotalQuestions, attempt.CorrectAnswers,
+		attempt.ScorePercent, attempt.ID, models.AttemptInProgress).Scan(&finishedAt, &attempt.UpdatedAt)
+	if err != nil {
+		return notFound(err)
+	}
+	attempt.Status = models.AttemptFinished
+	attempt.FinishedAt = &finishedAt
+	return nil
+}
+
+func (s *SQLStore) ListAttempts(userID string, chapterID uint) ([]models.QuizAttempt, error) {
+	query := `SELECT ` + quizAttemptColumns + `
+			  FROM quiz_attempts
+			  WHERE user_id = $1 AND ($2 = 0 OR chapter_id = $2) AND deleted_at IS NULL
+			  ORDER BY started_at DESC, id DESC`
+
+	rows, err := s.db.Query(query, userID, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []models.QuizAttempt
+	for rows.Next() {
+		var a models.QuizAttempt
+		if err := scanQuizAttempt(rows, &a); err == nil {
+			attempts = append(attempts, a)
+		}
+	}
+	return attempts, rows.Err()
+}
+
+func (s *SQLStore) StartedAttempts(userID string, chapterID uint) (map[uint]int, error) {
+	query := `SELECT chapter_id, COUNT(*)
+			  FROM quiz_attempts
+			  WHERE user_id = $1 AND ($2 = 0 OR chapter_id = $2)
+			  GROUP BY chapter_id`
+
+	rows, err := s.db.Query(query, userID, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	started := map[uint]int{}
+	for rows.Next() {
+		var id uint
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		started[id] = count
+	}
+	return started, rows.Err()
+}
+
+func (s *SQLStore) CreateAnswer(answer *models.QuizAnswer) error {
+	query := `INSERT INTO quiz_answers (user_id, chapter_id, quiz_question_id, attempt_id, user_answer,
+			  is_correct, is_late, answered_at, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, NOW()), NOW(), NOW())
+			  RETURNING id, answered_at, created_at, updated_at`
+
+	var answeredAt *time.Time
+	if !answer.AnsweredAt.IsZero() {
+		answeredAt = &answer.AnsweredAt
+	}
+	return s.db.QueryRow(query, answer.UserID, answer.ChapterID, answer.QuizQuestionID,
+		answer.AttemptID, answer.UserAnswer, answer.IsCorrect, answer.IsLate, nullableTime(answeredAt)).Scan(
+		&answer.ID, &answer.AnsweredAt, &answer.CreatedAt, &answer.UpdatedAt,
+	)
+}
+
+func (s *SQLStore) ListAnswers(filter AnswerFilter) ([]models.QuizAnswerWithDetails, error) {
+	query := `SELECT qa.id, qa.user_id, qa.chapter_id, qa.quiz_question_id, qa.attempt_id, qa.user_answer,
+			  qa.is_correct, qa.is_late, qa.answered_at, qa.created_at, qa.updated_at,
+			  qq.question_type, qq.question_text, qq.options, qq.answer_key, qq.explanation
+			  FROM quiz_answers qa
+			  JOIN quiz_questions qq ON qa.quiz_question_id = qq.id
+			  WHERE qa.user_id = $1 AND ($5 OR qa.deleted_at IS NULL)
+			  AND ($2 = 0 OR qa.chapter_id = $2)
+			  AND ($3 = 0 OR qa.quiz_question_id = $3)
+			  AND ($4 = 0 OR qa.attempt_id = $4)
+			  ORDER BY qa.chapter_id ASC, qa.answered_at DESC, qa.id DESC`
+
+	rows, err := s.db.Query(query, filter.UserID, filter.ChapterID, filter.QuestionID, filter.AttemptID,
+		filter.IncludeCleared)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var answers []models.QuizAnswerWithDetails
+	for rows.Next() {
+		var a models.QuizAnswerWithDetails
+		var q models.QuizQuestion
+		var attemptID sql.NullInt64
+		var options, answerKey string
+		err := rows.Scan(&a.ID, &a.UserID, &a.ChapterID, &a.QuizQuestionID, &attemptID, &a.UserAnswer,
+			&a.IsCorrect, &a.IsLate, &a.AnsweredAt, &a.CreatedAt, &a.UpdatedAt,
+			&q.QuestionType, &q.QuestionText, &options, &answerKey, &q.Explanation)
+		if err == nil && decodeQuestionJSON(&q, options, answerKey) == nil {
+			if attemptID.Valid {
+				id := uint(attemptID.Int64)
+				a.AttemptID = &id
+			}
+			answers = append(answers, answerWithDetails(a.QuizAnswer, q))
+		}
+	}
+	return answers, rows.Err()
+}
+
+func (s *SQLStore) ChapterScores(userID string, courseID uint) ([]models.QuizHistorySummary, error) {
+	// Only the latest answer to each question counts, so retries do not inflate the totals
+	query := `SELECT qa.chapter_id, ch.title,
+			  COUNT(*) as total_answered,
+			  SUM(CASE WHEN qa.is_correct = true THEN 1 ELSE 0 END) as total_correct,
+			  SUM(CASE WHEN qa.is_correct = false THEN 1 ELSE 0 END) as total_wrong
+			  FROM quiz_answers qa
+			  JOIN chapters ch ON qa.chapter_id = ch.id
+			  WHERE qa.user_id = $1 AND qa.deleted_at IS NULL AND ($2 = 0 OR ch.course_id = $2)
+			  AND qa.id = (
+				  SELECT latest.id FROM quiz_answers latest
+				  WHERE latest.user_id = qa.user_id AND latest.quiz_question_id = qa.quiz_question_id
+				  AND latest.deleted_at IS NULL
+				  ORDER BY latest.answered_at DESC, latest.id DESC
+				  LIMIT 1
+			  )
+			  GROUP BY qa.chapter_id, ch.title`
+
+	rows, err := s.db.Query(query, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byChapter := map[uint]*models.QuizHistorySummary{}
+	for rows.Next() {
+		var sum models.QuizHistorySummary
+		err := rows.Scan(&sum.ChapterID, &sum.ChapterTitle, &sum.TotalAnswered, &sum.TotalCorrect, &sum.TotalWrong)
+		if err == nil {
+			byChapter[sum.ChapterID] = &sum
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	attemptQuery := `SELECT a.chapter_id, ch.title, a.score_percent
+			  FROM quiz_attempts a
+			  JOIN chapters ch ON a.chapter_id = ch.id
+			  WHERE a.user_id = $1 AND a.status = $2 AND a.deleted_at IS NULL
+			  AND ($3 = 0 OR ch.course_id = $3)
+			  ORDER BY a.finished_at ASC, a.id ASC`
+
+	attemptRows, err := s.db.Query(attemptQuery, userID, models.AttemptFinished, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer attemptRows.Close()
+
+	for attemptRows.Next() {
+		var chapterID uint
+		var title string
+		var score float64
+		if err := attemptRows.Scan(&chapterID, &title, &score); err == nil {
+			addAttemptScore(byChapter, chapterID, title, score)
+		}
+	}
+	return sortedSummaries(byChapter), attemptRows.Err()
+}
+
+func (s *SQLStore) ListQuestionsWithLatestAnswer(userID string, chapterID uint) ([]models.QuizQuestionWithUserAnswer, error) {
+	query := `
+		SELECT
+			qq.id, qq.chapter_id, qq.question_type, qq.question_text, qq.options, qq.answer_key,
+			qq.explanation, qq.order_index, qq.created_at, qq.updated_at,
+			qa.user_answer, qa.is_correct, qa.answered_at,
+			COALESCE((SELECT COUNT(*) FROM quiz_answers
+					  WHERE quiz_question_id = qq.id AND user_id = $2 AND deleted_at IS NULL), 0) as times_attempted
+		FROM quiz_questions qq
+		LEFT JOIN quiz_answers qa ON qa.id = (
+			SELECT id
+			FROM quiz_answers
+			WHERE quiz_question_id = qq.id
+			AND user_id = $2
+			AND deleted_at IS NULL
+			ORDER BY answered_at DESC, id DESC
+			LIMIT 1
+		)
+		WHERE ($1 = 0 OR qq.chapter_id = $1) AND qq.deleted_at IS NULL
+		ORDER BY qq.chapter_id ASC, qq.order_index ASC
+	`
+
+	rows, err := s.db.Query(query, chapterID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []models.QuizQuestionWithUserAnswer
+	for rows.Next() {
+		var question models.QuizQuestion
+		var options, answerKey string
+		var userAnswer sql.NullString
+		var isCorrect sql.NullBool
+		var answeredAt sql.NullTime
+		var timesAttempted int
+
+		err := rows.Scan(
+			&question.ID, &question.ChapterID, &question.QuestionType, &question.QuestionText,
+			&options, &answerKey,
+			&question.Explanation, &question.OrderIndex, &question.CreatedAt, &question.UpdatedAt,
+			&userAnswer, &isCorrect, &answeredAt,
+			&timesAttempted,
+		)
+		if err != nil || decodeQuestionJSON(&question, options, answerKey) != nil {
+			continue
+		}
+
+		q := questionWithUserAnswer(question)
+		q.TimesAttempted = timesAttempted
+
+		// Check if user has answered this question
+		if userAnswer.Valid {
+			revealAnswer(&q, question, models.QuizAnswer{
+				UserAnswer: userAnswer.String,
+				IsCorrect:  isCorrect.Bool,
+				AnsweredAt: answeredAt.Time,
+			})
+		}
+
+		questions = append(questions, q)
+	}
+	return questions, rows.Err()
+}
+
+func (s *SQLStore) FirstUnansweredQuestion(userID string, chapterID uint) (*models.QuizQuestion, error) {
+	query := `
+		SELECT ` + quizQuestionColumns + `
+		FROM quiz_questions qq
+		WHERE qq.chapter_id = $1
+		AND qq.deleted_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM quiz_answers qa
+			WHERE qa.quiz_question_id = qq.id
+			AND qa.user_id = $2
+			AND qa.deleted_at IS NULL
+		)
+		ORDER BY qq.order_index ASC
+		LIMIT 1
+	`
+
+	var q models.QuizQuestion
+	if err := scanQuizQuestion(s.db.QueryRow(query, chapterID, userID), &q); err != nil {
+		return nil, notFound(err)
+	}
+	return &q, nil
+}
+
+func (s *SQLStore) ClearAttemptAnswers(attemptID, questionID uint) (int64, error) {
+	query := `UPDATE quiz_answers SET deleted_at = NOW()
+			  WHERE attempt_id = $1 AND quiz_question_id = $2 AND deleted_at IS NULL`
+
+	result, err := s.db.Exec(query, attemptID, questionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (s *SQLStore) ClearAnswers(userID string, chapterID uint) (int64, error) {
+	query := `UPDATE quiz_answers SET deleted_at = NOW()
+			  WHERE user_id = $1 AND ($2 = 0 OR chapter_id = $2) AND deleted_at IS NULL`
+
+	result, err := s.db.Exec(query, userID, chapterID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Attempts go with their answers
+	_, err = s.db.Exec(`UPDATE quiz_attempts SET deleted_at = NOW()
+			  WHERE user_id = $1 AND ($2 = 0 OR chapter_id = $2) AND deleted_at IS NULL`, userID, chapterID)
+	if err != nil {
+		return 0, err
+	}
+
+	// So do the review schedules built from them
+	_, err = s.db.Exec(`UPDATE review_items SET deleted_at = NOW()
+			  WHERE user_id = $1 AND ($2 = 0 OR chapter_id = $2) AND deleted_at IS NULL`, userID, chapterID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"database/sql"
+	"learning-app-backend/models"
+	"strconv"
+	"time"
+)
+
+const reviewItemColumns = `id, user_id, chapter_id, quiz_question_id, ease_factor, interval_days,
+			  repetitions, lapses, due_at, last_reviewed_at, created_at, updated_at`
+
+func scanReviewItem(row interface{ Scan(...interface{}) error }, r *models.ReviewItem, extra ...interface{}) error {
+	var lastReviewedAt sql.NullTime
+	dest := []interface{}{&r.ID, &r.UserID, &r.ChapterID, &r.QuizQuestionID, &r.EaseFactor, &r.IntervalDays,
+		&r.Repetitions, &r.Lapses, &r.DueAt, &lastReviewedAt, &r.CreatedAt, &r.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	if lastReviewedAt.Valid {
+		r.LastReviewedAt = &lastReviewedAt.Time
+	}
+	return nil
+}
+
+func (s *SQLStore) GetReviewItem(userID string, questionID uint) (*models.ReviewItem, error) {
+	var r models.ReviewItem
+	query := `SELECT ` + reviewItemColumns + `
+			  FROM review_items WHERE user_id = $1 AND quiz_question_id = $2 AND deleted_at IS NULL`
+
+	if err := scanReviewItem(s.db.QueryRow(query, userID, questionID), &r); err != nil {
+		return nil, notFound(err)
+	}
+	return &r, nil
+}
+
+func (s *SQLStore) SaveReviewItem(item *models.ReviewItem) error {
+	if item.ID == 0 {
+		query := `INSERT INTO review_items (user_id, chapter_id, quiz_question_id, ease_factor, interval_days,
+				  repetitions, lapses, due_at, last_reviewed_at, created_at, updated_at)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+				  RETURNING ` + reviewItemColumns
+
+		return scanReviewItem(s.db.QueryRow(query, item.UserID, item.ChapterID, item.QuizQuestionID,
+			item.EaseFactor, item.IntervalDays, item.Repetitions, item.Lapses,
+			item.DueAt.UTC(), nullableTime(item.LastReviewedAt)), item)
+	}
+	return updateReviewItem(s.db.QueryRow, item)
+}
+
+func (s *SQLStore) RecordReview(item *models.ReviewItem, answer *models.ReviewAnswer) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateReviewItem(tx.QueryRow, item); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO review_answers (review_item_id, user_id, quiz_question_id, user_answer,
+			  is_correct, quality, reviewed_at, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+			  RETURNING id, reviewed_at`
+
+	answer.ReviewItemID = item.ID
+	err = tx.QueryRow(query, answer.ReviewItemID, answer.UserID, answer.QuizQuestionID, answer.UserAnswer,
+		answer.IsCorrect, answer.Quality).Scan(&answer.ID, &answer.ReviewedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateReviewItem writes the item's schedule through either the connection or a transaction
+func updateReviewItem(queryRow func(string, ...interface{}) *sql.Row, item *models.ReviewItem) error {
+	query := `UPDATE review_items SET ease_factor = $1, interval_days = $2, repetitions = $3, lapses = $4,
+			  due_at = $5, last_reviewed_at = $6, updated_at = NOW()
+			  WHERE id = $7 AND deleted_at IS NULL
+			  RETURNING ` + reviewItemColumns
+
+	err := scanReviewItem(queryRow(query, item.EaseFactor, item.IntervalDays, item.Repetitions, item.Lapses,
+		item.DueAt.UTC(), nullableTime(item.LastReviewedAt), item.ID), item)
+	return notFound(err)
+}
+
+// nullableTime binds an optional timestamp in UTC, so SQLite compares it correctly as text
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+func (s *SQLStore) ListDueReviews(userID string, before time.Time, limit int) ([]models.DueReview, error) {
+	query := `SELECT ri.id, ri.user_id, ri.chapter_id, ri.quiz_question_id, ri.ease_factor, ri.interval_days,
+			  ri.repetitions, ri.lapses, ri.due_at, ri.last_reviewed_at, ri.created_at, ri.updated_at,
+			  ch.title, qq.question_type, qq.question_text, qq.options, qq.answer_key, qq.order_index,
+			  qq.created_at, qq.updated_at
+			  FROM review_items ri
+			  JOIN quiz_questions qq ON ri.quiz_question_id = qq.id
+			  JOIN chapters ch ON ri.chapter_id = ch.id
+			  WHERE ri.user_id = $1 AND ri.due_at < $2 AND ri.deleted_at IS NULL
+			  AND qq.deleted_at IS NULL AND ch.deleted_at IS NULL
+			  ORDER BY ri.due_at ASC, ri.id ASC`
+	if limit > 0 {
+		query += ` LIMIT ` + strconv.Itoa(limit)
+	}
+
+	rows, err := s.db.Query(query, userID, before.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []models.DueReview
+	for rows.Next() {
+		var r models.DueReview
+		var q models.QuizQuestion
+		var options, answerKey string
+		err := scanReviewItem(rows, &r.ReviewItem, &r.ChapterTitle, &q.QuestionType, &q.QuestionText,
+			&options, &answerKey, &q.OrderIndex, &q.CreatedAt, &q.UpdatedAt)
+		if err == nil && decodeQuestionJSON(&q, options, answerKey) == nil {
+			q.ID = r.QuizQuestionID
+			q.ChapterID = r.ChapterID
+			r.Question = q.LearnerView()
+			reviews = append(reviews, r)
+		}
+	}
+	return reviews, rows.Err()
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"database/sql"
+	"learning-app-backend/models"
+	"time"
+)
+
+func (s *SQLStore) ListStreakGoals(userID string) ([]models.StreakGoal, error) {
+	query := `SELECT id, user_id, goal_type, target, timezone, effective_from, created_at, updated_at
+			  FROM streak_goals WHERE user_id = $1
+			  ORDER BY effective_from ASC`
+
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var goals []models.StreakGoal
+	for rows.Next() {
+		var g models.StreakGoal
+		if err := rows.Scan(&g.ID, &g.UserID, &g.GoalType, &g.Target, &g.Timezone, &g.EffectiveFrom,
+			&g.CreatedAt, &g.UpdatedAt); err == nil {
+			goals = append(goals, g)
+		}
+	}
+	return goals, rows.Err()
+}
+
+func (s *SQLStore) SaveStreakGoal(goal *models.StreakGoal) error {
+	query := `INSERT INTO streak_goals (user_id, goal_type, target, timezone, effective_from, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+			  ON CONFLICT (user_id, effective_from) DO UPDATE
+			  SET goal_type = excluded.goal_type, target = excluded.target, timezone = excluded.timezone,
+			  updated_at = NOW()
+			  RETURNING id, created_at, updated_at`
+
+	return s.db.QueryRow(query, goal.UserID, goal.GoalType, goal.Target, goal.Timezone, goal.EffectiveFrom).Scan(
+		&goal.ID, &goal.CreatedAt, &goal.UpdatedAt)
+}
+
+func (s *SQLStore) ListStreakFreezes(userID string) ([]models.StreakFreeze, error) {
+	query := `SELECT id, user_id, freeze_date, created_at
+			  FROM streak_freezes WHERE user_id = $1
+			  ORDER BY freeze_date ASC`
+
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var freezes []models.StreakFreeze
+	for rows.Next() {
+		var f models.StreakFreeze
+		if err := rows.Scan(&f.ID, &f.UserID, &f.Date, &f.CreatedAt); err == nil {
+			freezes = append(freezes, f)
+		}
+	}
+	return freezes, rows.Err()
+}
+
+func (s *SQLStore) CreateStreakFreeze(freeze *models.StreakFreeze) error {
+	query := `INSERT INTO streak_freezes (user_id, freeze_date, created_at)
+			  VALUES ($1, $2, NOW())
+			  ON CONFLICT (user_id, freeze_date) DO NOTHING
+			  RETURNING id, created_at`
+
+	err := s.db.QueryRow(query, freeze.UserID, freeze.Date).Scan(&freeze.ID, &freeze.CreatedAt)
+	if err == sql.ErrNoRows {
+		// Frozen concurrently by another request
+		return nil
+	}
+	return err
+}
+
+func (s *SQLStore) DeleteStreakFreeze(userID, date string) error {
+	return requireAffected(s.db.Exec(`DELETE FROM streak_freezes WHERE user_id = $1 AND freeze_date = $2`, userID, date))
+}
+
+func (s *SQLStore) ListAnswerTimes(userID string, from time.Time) ([]time.Time, error) {
+	query := `SELECT answered_at FROM quiz_answers WHERE user_id = $1 AND answered_at >= $2
+			  UNION ALL
+			  SELECT reviewed_at FROM review_answers WHERE user_id = $1 AND reviewed_at >= $2`
+
+	rows, err := s.db.Query(query, userID, from.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var times []time.Time
+	for rows.Next() {
+		var t time.Time
+		if err := rows.Scan(&t); err == nil {
+			times = append(times, t)
+		}
+	}
+	return times, rows.Err()
+}
+
+func (s *SQLStore) GetStreakRecord(userID string) (*models.StreakRecord, error) {
+	var r models.StreakRecord
+	query := `SELECT user_id, longest_streak, last_goal_date, counted_through, updated_at
+			  FROM streak_records WHERE user_id = $1`
+
+	err := s.db.QueryRow(query, userID).Scan(&r.UserID, &r.LongestStreak, &r.LastGoalDate, &r.CountedThrough,
+		&r.UpdatedAt)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &r, nil
+}
+
+func (s *SQLStore) SaveStreakRecord(record *models.StreakRecord) error {
+	query := `INSERT INTO streak_records (user_id, longest_streak, last_goal_date, counted_through, updated_at)
+			  VALUES ($1, $2, $3, $4, NOW())
+			  ON CONFLICT (user_id) DO UPDATE
+			  SET longest_streak = excluded.longest_streak, last_goal_date = excluded.last_goal_date,
+			  counted_through = excluded.counted_through, updated_at = NOW()
+			  RETURNING updated_at`
+
+	return s.db.QueryRow(query, record.UserID, record.LongestStreak, record.LastGoalDate,
+		record.CountedThrough).Scan(&record.UpdatedAt)
+}
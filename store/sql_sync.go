@@ -0,0 +1,45 @@
+package store
+
+import (
+	"database/sql"
+	"learning-app-backend/models"
+)
+
+func (s *SQLStore) GetSyncEvent(userID, clientEventID string) (*models.SyncEvent, error) {
+	var e models.SyncEvent
+	query := `SELECT id, user_id, client_event_id, event_type, status, message, occurred_at, created_at
+			  FROM sync_events WHERE user_id = $1 AND client_event_id = $2`
+
+	err := s.db.QueryRow(query, userID, clientEventID).Scan(&e.ID, &e.UserID, &e.ClientEventID,
+		&e.EventType, &e.Status, &e.Message, &e.OccurredAt, &e.CreatedAt)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &e, nil
+}
+
+func (s *SQLStore) ClaimSyncEvent(event *models.SyncEvent) (bool, error) {
+	query := `INSERT INTO sync_events (user_id, client_event_id, event_type, status, message, occurred_at, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, NOW())
+			  ON CONFLICT (user_id, client_event_id) DO NOTHING
+			  RETURNING id, created_at`
+
+	err := s.db.QueryRow(query, event.UserID, event.ClientEventID, event.EventType, event.Status,
+		event.Message, event.OccurredAt.UTC()).Scan(&event.ID, &event.CreatedAt)
+	if err == sql.ErrNoRows {
+		// Synced before, or being synced by another request
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *SQLStore) FinishSyncEvent(event *models.SyncEvent) error {
+	query := `UPDATE sync_events SET status = $1, message = $2 WHERE id = $3`
+
+	return requireAffected(s.db.Exec(query, event.Status, event.Message, event.ID))
+}
+
+func (s *SQLStore) ReleaseSyncEvent(userID, clientEventID string) error {
+	_, err := s.db.Exec(`DELETE FROM sync_events WHERE user_id = $1 AND client_event_id = $2`, userID, clientEventID)
+	return err
+}
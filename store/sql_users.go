@@ -0,0 +1,132 @@
+package store
+
+import (
+	"learning-app-backend/models"
+	"time"
+)
+
+const userColumns = `id, user_id, username, role, COALESCE(password_hash, ''), created_at, updated_at`
+
+func scanUser(row interface{ Scan(...interface{}) error }, u *models.User) error {
+	return row.Scan(&u.ID, &u.UserID, &u.Username, &u.Role, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
+}
+
+func (s *SQLStore) GetUser(userID string) (*models.User, error) {
+	var user models.User
+	query := `SELECT ` + userColumns + `
+			  FROM users WHERE user_id = $1 AND deleted_at IS NULL`
+
+	if err := scanUser(s.db.QueryRow(query, userID), &user); err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (s *SQLStore) UserExists(userID string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1 AND deleted_at IS NULL)`
+	err := s.db.QueryRow(query, userID).Scan(&exists)
+	return exists, err
+}
+
+func (s *SQLStore) CreateUser(userID, username, passwordHash string) (*models.User, error) {
+	var user models.User
+	query := `INSERT INTO users (user_id, username, password_hash, created_at, updated_at)
+			  VALUES ($1, $2, $3, NOW(), NOW())
+			  RETURNING ` + userColumns
+
+	if err := scanUser(s.db.QueryRow(query, userID, username, passwordHash), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *SQLStore) ListUsers(role string) ([]models.User, error) {
+	query := `SELECT ` + userColumns + `
+			  FROM users WHERE deleted_at IS NULL AND ($1 = '' OR role = $1)
+			  ORDER BY user_id ASC`
+
+	rows, err := s.db.Query(query, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var u models.User
+		if err := scanUser(rows, &u); err == nil {
+			users = append(users, u)
+		}
+	}
+	return users, rows.Err()
+}
+
+func (s *SQLStore) UpdateUserRole(userID, role string) (*models.User, error) {
+	var user models.User
+	query := `UPDATE users SET role = $1, updated_at = NOW()
+			  WHERE user_id = $2 AND deleted_at IS NULL
+			  RETURNING ` + userColumns
+
+	if err := scanUser(s.db.QueryRow(query, role, userID), &user); err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (s *SQLStore) CreateSession(userID, refreshTokenHash string, expiresAt time.Time) (*models.AuthSession, error) {
+	var session models.AuthSession
+	query := `INSERT INTO auth_sessions (user_id, refresh_token_hash, expires_at, created_at, updated_at)
+			  VALUES ($1, $2, $3, NOW(), NOW())
+			  RETURNING id, user_id, refresh_token_hash, expires_at, created_at, updated_at`
+
+	err := s.db.QueryRow(query, userID, refreshTokenHash, expiresAt.UTC()).Scan(
+		&session.ID, &session.UserID, &session.RefreshTokenHash, &session.ExpiresAt,
+		&session.CreatedAt, &session.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *SQLStore) FindActiveSessionByRefreshHash(refreshTokenHash string) (*models.AuthSession, error) {
+	var session models.AuthSession
+	query := `SELECT id, user_id, refresh_token_hash, expires_at, created_at, updated_at
+			  FROM auth_sessions
+			  WHERE refresh_token_hash = $1 AND revoked_at IS NULL AND expires_at > NOW()`
+
+	err := s.db.QueryRow(query, refreshTokenHash).Scan(
+		&session.ID, &session.UserID, &session.RefreshTokenHash, &session.ExpiresAt,
+		&session.CreatedAt, &session.UpdatedAt,
+	)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &session, nil
+}
+
+func (s *SQLStore) RotateSession(sessionID uint, refreshTokenHash string, expiresAt time.Time) error {
+	query := `UPDATE auth_sessions SET refresh_token_hash = $1, expires_at = $2, updated_at = NOW()
+			  WHERE id = $3`
+	return requireAffected(s.db.Exec(query, refreshTokenHash, expiresAt.UTC(), sessionID))
+}
+
+func (s *SQLStore) RevokeSession(sessionID uint) error {
+	query := `UPDATE auth_sessions SET revoked_at = NOW(), updated_at = NOW()
+			  WHERE id = $1 AND revoked_at IS NULL`
+	_, err := s.db.Exec(query, sessionID)
+	return err
+}
+
+func (s *SQLStore) ActiveSessionRole(sessionID uint, userID string) (string, error) {
+	var role string
+	query := `SELECT u.role FROM auth_sessions s
+			  JOIN users u ON u.user_id = s.user_id AND u.deleted_at IS NULL
+			  WHERE s.id = $1 AND s.user_id = $2 AND s.revoked_at IS NULL AND s.expires_at > NOW()`
+
+	if err := s.db.QueryRow(query, sessionID, userID).Scan(&role); err != nil {
+		return "", notFound(err)
+	}
+	return role, nil
+}
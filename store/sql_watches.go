@@ -0,0 +1,111 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"learning-app-backend/models"
+)
+
+const videoWatchColumns = `vw.id, vw.user_id, vw.chapter_id, vw.video_id, vw.watched_ranges, vw.watched_seconds,
+			  vw.last_position, vw.last_heartbeat_at, vw.created_at, vw.updated_at, v.duration_seconds`
+
+func scanVideoWatch(row interface{ Scan(...interface{}) error }, w *models.VideoWatch) error {
+	var ranges string
+	var duration int
+	var lastHeartbeatAt sql.NullTime
+	err := row.Scan(&w.ID, &w.UserID, &w.ChapterID, &w.VideoID, &ranges, &w.WatchedSeconds,
+		&w.LastPosition, &lastHeartbeatAt, &w.CreatedAt, &w.UpdatedAt, &duration)
+	if err != nil {
+		return err
+	}
+	if lastHeartbeatAt.Valid {
+		w.LastHeartbeatAt = &lastHeartbeatAt.Time
+	}
+	if err := json.Unmarshal([]byte(ranges), &w.Ranges); err != nil {
+		return err
+	}
+	w.SetDuration(duration)
+	return nil
+}
+
+func (s *SQLStore) GetVideoWatch(userID string, videoID uint) (*models.VideoWatch, error) {
+	var w models.VideoWatch
+	query := `SELECT ` + videoWatchColumns + `
+			  FROM video_watches vw
+			  JOIN videos v ON vw.video_id = v.id
+			  WHERE vw.user_id = $1 AND vw.video_id = $2 AND vw.deleted_at IS NULL AND v.deleted_at IS NULL`
+
+	if err := scanVideoWatch(s.db.QueryRow(query, userID, videoID), &w); err != nil {
+		return nil, notFound(err)
+	}
+	return &w, nil
+}
+
+func (s *SQLStore) ListVideoWatches(userID string, chapterID uint) ([]models.VideoWatch, error) {
+	query := `SELECT ` + videoWatchColumns + `
+			  FROM video_watches vw
+			  JOIN videos v ON vw.video_id = v.id
+			  WHERE vw.user_id = $1 AND ($2 = 0 OR vw.chapter_id = $2)
+			  AND vw.deleted_at IS NULL AND v.deleted_at IS NULL
+			  ORDER BY vw.chapter_id ASC`
+
+	rows, err := s.db.Query(query, userID, chapterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var watches []models.VideoWatch
+	for rows.Next() {
+		var w models.VideoWatch
+		if err := scanVideoWatch(rows, &w); err == nil {
+			watches = append(watches, w)
+		}
+	}
+	return watches, rows.Err()
+}
+
+func (s *SQLStore) UpdateVideoWatch(userID string, chapterID, videoID uint, update func(watch *models.VideoWatch)) (*models.VideoWatch, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	insertQuery := `INSERT INTO video_watches (user_id, chapter_id, video_id, created_at, updated_at)
+					VALUES ($1, $2, $3, NOW(), NOW())
+					ON CONFLICT (user_id, video_id) WHERE deleted_at IS NULL DO NOTHING`
+	if _, err := tx.Exec(insertQuery, userID, chapterID, videoID); err != nil {
+		return nil, err
+	}
+
+	var w models.VideoWatch
+	query := `SELECT ` + videoWatchColumns + `
+			  FROM video_watches vw
+			  JOIN videos v ON vw.video_id = v.id
+			  WHERE vw.user_id = $1 AND vw.video_id = $2 AND vw.deleted_at IS NULL AND v.deleted_at IS NULL` +
+		tx.Dialect.ForUpdate("vw")
+	if err := scanVideoWatch(tx.QueryRow(query, userID, videoID), &w); err != nil {
+		return nil, notFound(err)
+	}
+
+	update(&w)
+	ranges, err := json.Marshal(w.Ranges)
+	if err != nil {
+		return nil, err
+	}
+
+	updateQuery := `UPDATE video_watches SET watched_ranges = $1, watched_seconds = $2, last_position = $3,
+					last_heartbeat_at = $4, updated_at = NOW()
+					WHERE id = $5
+					RETURNING updated_at`
+	err = tx.QueryRow(updateQuery, string(ranges), w.WatchedSeconds, w.LastPosition, nullableTime(w.LastHeartbeatAt),
+		w.ID).Scan(&w.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
@@ -0,0 +1,231 @@
+package store
+
+import (
+	"errors"
+	"learning-app-backend/models"
+	"time"
+)
+
+// ErrNotFound is returned when the requested row does not exist (or is soft-deleted)
+var ErrNotFound = errors.New("not found")
+
+// UserStore - Users, roles and auth sessions
+type UserStore interface {
+	GetUser(userID string) (*models.User, error)
+	UserExists(userID string) (bool, error)
+	CreateUser(userID, username, passwordHash string) (*models.User, error)
+	ListUsers(role string) ([]models.User, error)
+	UpdateUserRole(userID, role string) (*models.User, error)
+
+	CreateSession(userID, refreshTokenHash string, expiresAt time.Time) (*models.AuthSession, error)
+	FindActiveSessionByRefreshHash(refreshTokenHash string) (*models.AuthSession, error)
+	RotateSession(sessionID uint, refreshTokenHash string, expiresAt time.Time) error
+	RevokeSession(sessionID uint) error
+	// ActiveSessionRole returns the user's current role if the session is neither revoked nor expired
+	ActiveSessionRole(sessionID uint, userID string) (string, error)
+}
+
+// CourseStore - Courses, the top-level grouping of chapters
+type CourseStore interface {
+	ListCourses() ([]models.Course, error)
+	GetCourse(courseID uint) (*models.Course, error)
+	CourseExists(courseID uint) (bool, error)
+	CreateCourse(course *models.Course) error
+	UpdateCourse(course *models.Course) error
+	DeleteCourse(courseID uint) error
+	CourseOrderIndexTaken(orderIndex int, excludeCourseID uint) (bool, error)
+}
+
+// ChapterStore - Chapters, their video and quiz questions
+type ChapterStore interface {
+	// ListChapters returns chapters in course order, optionally only one course's (courseID 0 = all)
+	ListChapters(courseID uint) ([]models.Chapter, error)
+	GetChapter(chapterID uint) (*models.Chapter, error)
+	ChapterExists(chapterID uint) (bool, error)
+	CreateChapter(chapter *models.Chapter) error
+	UpdateChapter(chapter *models.Chapter) error
+	// DeleteChapter soft-deletes the chapter together with its video, quiz questions, pools and draw rules
+	DeleteChapter(chapterID uint) error
+	ChapterOrderIndexTaken(courseID uint, orderIndex int, excludeChapterID uint) (bool, error)
+
+	GetChapterVideo(chapterID uint) (*models.Video, error)
+	// ListVideos returns the videos of live chapters, optionally only one course's (courseID 0 = all)
+	ListVideos(courseID uint) ([]models.Video, error)
+	CreateVideo(video *models.Video) error
+	UpdateVideo(video *models.Video) error
+	DeleteVideo(videoID uint) error
+
+	ListQuizQuestions(chapterID uint) ([]models.QuizQuestion, error)
+	GetQuizQuestion(questionID uint) (*models.QuizQuestion, error)
+	CreateQuizQuestion(question *models.QuizQuestion) error
+	UpdateQuizQuestion(question *models.QuizQuestion) error
+	DeleteQuizQuestion(questionID uint) error
+	QuestionOrderIndexTaken(chapterID uint, orderIndex int, excludeQuestionID uint) (bool, error)
+
+	// ListPools returns the chapter's question pools with their live question counts
+	ListPools(chapterID uint) ([]models.QuestionPool, error)
+	GetPool(poolID uint) (*models.QuestionPool, error)
+	CreatePool(pool *models.QuestionPool) error
+	UpdatePool(pool *models.QuestionPool) error
+	// DeletePool soft-deletes the pool and the draw rules using it; its questions leave the pool
+	DeletePool(poolID uint) error
+
+	// ListDrawRules returns the chapter's draw rules in the order they apply
+	ListDrawRules(chapterID uint) ([]models.QuizDrawRule, error)
+	// ReplaceDrawRules swaps the chapter's draw rules for rules (none = every question is shown)
+	ReplaceDrawRules(chapterID uint, rules []models.QuizDrawRule) error
+
+	// ListPrerequisites returns a chapter's prerequisites (chapterID 0 = every chapter's),
+	// skipping rules whose required chapter has been deleted
+	ListPrerequisites(chapterID uint) ([]models.ChapterPrerequisite, error)
+	CreatePrerequisite(prerequisite *models.ChapterPrerequisite) error
+	DeletePrerequisite(prerequisiteID uint) error
+}
+
+// AnswerFilter - Narrow ListAnswers to a chapter, question and/or attempt (zero means any)
+type AnswerFilter struct {
+	UserID         string
+	ChapterID      uint
+	QuestionID     uint
+	AttemptID      uint
+	IncludeCleared bool // Also answers removed by ClearAnswers
+}
+
+// QuizStore - Quiz attempts and answer history
+type QuizStore interface {
+	// CreateAttempt starts an attempt; questionIDs freezes the questions drawn for it (nil = every chapter question)
+	CreateAttempt(attempt *models.QuizAttempt, questionIDs []uint) error
+	// AttemptQuestionIDs returns the questions frozen for an attempt in drawn order (empty when none were drawn)
+	AttemptQuestionIDs(attemptID uint) ([]uint, error)
+	GetAttempt(attemptID uint) (*models.QuizAttempt, error)
+	// ActiveAttempt returns the user's in-progress attempt for the chapter (ErrNotFound if none)
+	ActiveAttempt(userID string, chapterID uint) (*models.QuizAttempt, error)
+	// FinishAttempt stores the attempt's score and marks it finished (ErrNotFound if it is not in progress)
+	FinishAttempt(attempt *models.QuizAttempt) error
+	// ListAttempts returns the user's attempts newest first, optionally only for one chapter (chapterID 0 = all)
+	ListAttempts(userID string, chapterID uint) ([]models.QuizAttempt, error)
+	// StartedAttempts counts the attempts the user started per chapter, including ones removed by
+	// ClearAnswers (chapterID 0 = every chapter)
+	StartedAttempts(userID string, chapterID uint) (map[uint]int, error)
+
+	// CreateAnswer records an answer, at answer.AnsweredAt when set (offline answers) or now
+	CreateAnswer(answer *models.QuizAnswer) error
+	// ListAnswers returns answers with question details, by chapter then newest first
+	ListAnswers(filter AnswerFilter) ([]models.QuizAnswerWithDetails, error)
+	// ChapterScores returns per-chapter scores, optionally only for one course (courseID 0 = all)
+	ChapterScores(userID string, courseID uint) ([]models.QuizHistorySummary, error)
+	// ListQuestionsWithLatestAnswer returns the chapter's questions with the user's latest answer to each
+	// (chapterID 0 = every chapter's, by chapter)
+	ListQuestionsWithLatestAnswer(userID string, chapterID uint) ([]models.QuizQuestionWithUserAnswer, error)
+	FirstUnansweredQuestion(userID string, chapterID uint) (*models.QuizQuestion, error)
+	// ClearAttemptAnswers soft-deletes the answers to one question within an attempt, e.g. when an
+	// earlier offline answer replaces them
+	ClearAttemptAnswers(attemptID, questionID uint) (int64, error)
+	// ClearAnswers soft-deletes a user's answers, attempts and review items, optionally only for one chapter (chapterID 0 = all)
+	ClearAnswers(userID string, chapterID uint) (int64, error)
+}
+
+// ReviewStore - Spaced-repetition schedules for answered questions
+type ReviewStore interface {
+	// GetReviewItem returns the user's schedule for a question (ErrNotFound if it was never answered)
+	GetReviewItem(userID string, questionID uint) (*models.ReviewItem, error)
+	// SaveReviewItem creates or updates the (user, question) schedule
+	SaveReviewItem(item *models.ReviewItem) error
+	// RecordReview stores a review answer and the schedule it produced
+	RecordReview(item *models.ReviewItem, answer *models.ReviewAnswer) error
+	// ListDueReviews returns the user's reviews due before the given time, most overdue first
+	// (limit 0 = all), skipping deleted questions and chapters
+	ListDueReviews(userID string, before time.Time, limit int) ([]models.DueReview, error)
+}
+
+// ProgressEventFilter - Narrow ListProgressEvents to a chapter and/or time range (zero means any).
+// A chapter filter keeps the user-wide reset events; From is inclusive, To and RecordedTo exclusive
+type ProgressEventFilter struct {
+	UserID     string
+	ChapterID  uint
+	From       time.Time // occurred_at
+	To         time.Time // occurred_at
+	RecordedTo time.Time // created_at, for the log as it stood at a time
+}
+
+// ProgressStore - Per-chapter video/quiz progress
+type ProgressStore interface {
+	// SaveProgress appends a saved event to the progress log and applies it to the (user, chapter,
+	// content type) row; last_updated is progress.LastUpdated when set (offline events) or now.
+	// event supplies the source and occurred_at (zero = now); the rest is filled in from the row
+	SaveProgress(progress *models.Progress, event *models.ProgressEvent) error
+	// LatestProgress returns the most recently updated row and its chapter title,
+	// optionally only within one course (courseID 0 = any)
+	LatestProgress(userID string, courseID uint) (*models.Progress, string, error)
+	ListChapterProgress(userID string, chapterID uint) ([]models.Progress, error)
+	ListUserProgress(userID string) ([]models.Progress, error)
+	// ResetProgress logs a reset event and soft-deletes the user's progress rows, chapter
+	// completions and video watches
+	ResetProgress(userID string) (int64, error)
+	// ListProgressEvents returns the user's progress log by occurred_at, then recording order
+	ListProgressEvents(filter ProgressEventFilter) ([]models.ProgressEvent, error)
+
+	// ListCompletions returns the chapters the user has passed
+	ListCompletions(userID string) ([]models.ChapterCompletion, error)
+	// CreateCompletion records that the user passed a chapter (a no-op if already recorded)
+	CreateCompletion(completion *models.ChapterCompletion) error
+
+	// GetVideoWatch returns the user's watched ranges for a live video, measured against its duration
+	GetVideoWatch(userID string, videoID uint) (*models.VideoWatch, error)
+	// ListVideoWatches returns the user's watches of live videos (chapterID 0 = every chapter)
+	ListVideoWatches(userID string, chapterID uint) ([]models.VideoWatch, error)
+	// UpdateVideoWatch locks the user's watch of a live video (starting an empty one when missing),
+	// applies update to it and saves its ranges and position, so concurrent heartbeats apply one
+	// after the other. update must not call the store
+	UpdateVideoWatch(userID string, chapterID, videoID uint, update func(watch *models.VideoWatch)) (*models.VideoWatch, error)
+
+	// GetSyncEvent returns an offline event already synced under the client's event ID
+	GetSyncEvent(userID, clientEventID string) (*models.SyncEvent, error)
+	// ClaimSyncEvent records an offline event before it is applied, so the unique event ID lets
+	// only one request apply it; false when the event ID is already known
+	ClaimSyncEvent(event *models.SyncEvent) (bool, error)
+	// FinishSyncEvent stores the outcome of a claimed event
+	FinishSyncEvent(event *models.SyncEvent) error
+	// ReleaseSyncEvent forgets a claimed event, so it may be sent again
+	ReleaseSyncEvent(userID, clientEventID string) error
+
+	// FindLearningSession returns the user's most recent session on the chapter (0 = any chapter)
+	// that was active at some point between from and to
+	FindLearningSession(userID string, chapterID uint, from, to time.Time) (*models.LearningSession, error)
+	// ListLearningSessions returns the user's sessions started in [from, to) (zero = unbounded), oldest first
+	ListLearningSessions(userID string, from, to time.Time) ([]models.LearningSession, error)
+	// SaveLearningSession inserts the session (ID 0) or updates its span, active and watched time
+	SaveLearningSession(session *models.LearningSession) error
+}
+
+// StreakStore - Learners' daily goals and streak freezes
+type StreakStore interface {
+	// ListStreakGoals returns the user's daily goals, oldest effective_from first (none = the default goal)
+	ListStreakGoals(userID string) ([]models.StreakGoal, error)
+	// SaveStreakGoal creates the goal taking effect on goal.EffectiveFrom, or replaces the one already set for that day
+	SaveStreakGoal(goal *models.StreakGoal) error
+	// ListStreakFreezes returns the days the user froze, oldest first
+	ListStreakFreezes(userID string) ([]models.StreakFreeze, error)
+	// CreateStreakFreeze freezes a day (a no-op if it is already frozen)
+	CreateStreakFreeze(freeze *models.StreakFreeze) error
+	// DeleteStreakFreeze unfreezes a day (ErrNotFound if it was not frozen)
+	DeleteStreakFreeze(userID, date string) error
+	// ListAnswerTimes returns when the user answered quiz and review questions from from on (zero =
+	// ever), including quiz answers cleared since: they still count as activity on their day
+	ListAnswerTimes(userID string, from time.Time) ([]time.Time, error)
+	// GetStreakRecord returns the user's streak as last worked out (ErrNotFound before the first time)
+	GetStreakRecord(userID string) (*models.StreakRecord, error)
+	// SaveStreakRecord creates or replaces the user's streak record
+	SaveStreakRecord(record *models.StreakRecord) error
+}
+
+// Stores bundles every store the handlers depend on
+type Stores struct {
+	Users    UserStore
+	Courses  CourseStore
+	Chapters ChapterStore
+	Quiz     QuizStore
+	Reviews  ReviewStore
+	Progress ProgressStore
+	Streaks  StreakStore
+}